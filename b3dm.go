@@ -0,0 +1,311 @@
+package tesselator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 3D Tiles b3dm文件头长度
+const b3dmHeaderLength = 28
+
+// newBatchedGLB 将所有要素合并为一个图元，并用idAttribute属性记录每个顶点所属要素的序号。
+// 序号以FLOAT存储，保证每个元素按4字节对齐
+func newBatchedGLB(features []TesselatedFeature, idAttribute string, opts *GLBOptions) (*glbBuilder, error) {
+	var merged GLTFPrimitive
+	var ids []float32
+	for i, f := range features {
+		if err := (*MeshAttributes)(nil).check(f.Indices, f.Vertices); err != nil {
+			return nil, fmt.Errorf("feature %d: %v", i, err)
+		}
+		if len(f.Indices) == 0 {
+			continue
+		}
+		base := len(merged.Vertices)
+		for _, idx := range f.Indices {
+			merged.Indices = append(merged.Indices, base+idx)
+		}
+		merged.Vertices = append(merged.Vertices, f.Vertices...)
+		for range f.Vertices {
+			ids = append(ids, float32(i))
+		}
+	}
+
+	b := newGLBBuilder()
+	if len(merged.Indices) == 0 {
+		return b, nil
+	}
+	prim := b.addPrimitive(&merged)
+	prim.Attributes[idAttribute] = b.addFloats(ids, 1, "SCALAR", false)
+	b.addMesh([]gltfPrimitive{prim}, opts)
+	return b, nil
+}
+
+// featurePropertyColumns 把要素属性整理为按列存储的表，每列的长度等于要素个数，
+// 缺失的值为nil。只要有要素带ID，就增加一列"id"（属性中已有同名字段时以属性为准）
+func featurePropertyColumns(features []TesselatedFeature) ([]string, map[string][]interface{}) {
+	columns := map[string][]interface{}{}
+	column := func(name string) []interface{} {
+		c, ok := columns[name]
+		if !ok {
+			c = make([]interface{}, len(features))
+			columns[name] = c
+		}
+		return c
+	}
+	for i, f := range features {
+		for k, v := range f.Properties {
+			column(k)[i] = v
+		}
+	}
+	if _, ok := columns["id"]; !ok {
+		for i, f := range features {
+			if f.ID != nil {
+				column("id")[i] = f.ID
+			}
+		}
+	}
+
+	names := make([]string, 0, len(columns))
+	for k := range columns {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names, columns
+}
+
+// WriteB3DM 将要素合并为一个带_BATCHID顶点属性的网格，写为3D Tiles 1.0的b3dm瓦片。
+// 要素在列表中的序号即为批次ID，要素属性写入批次表的JSON部分。opts可为nil
+func WriteB3DM(w io.Writer, features []TesselatedFeature, opts *GLBOptions) error {
+	b, err := newBatchedGLB(features, "_BATCHID", opts)
+	if err != nil {
+		return fmt.Errorf("b3dm: %v", err)
+	}
+	var glb bytes.Buffer
+	if err := b.encode(&glb); err != nil {
+		return err
+	}
+
+	featureTable, err := json.Marshal(map[string]interface{}{"BATCH_LENGTH": len(features)})
+	if err != nil {
+		return err
+	}
+	var batchTable []byte
+	if names, columns := featurePropertyColumns(features); len(names) > 0 {
+		if batchTable, err = json.Marshal(columns); err != nil {
+			return fmt.Errorf("b3dm: batch table: %v", err)
+		}
+	}
+	// 各部分都必须结束在8字节边界上，JSON用空格补齐
+	for (b3dmHeaderLength+len(featureTable))%8 != 0 {
+		featureTable = append(featureTable, ' ')
+	}
+	for len(batchTable)%8 != 0 {
+		batchTable = append(batchTable, ' ')
+	}
+
+	total := b3dmHeaderLength + len(featureTable) + len(batchTable) + glb.Len()
+	header := make([]byte, 0, b3dmHeaderLength)
+	header = append(header, "b3dm"...)
+	header = binary.LittleEndian.AppendUint32(header, 1)
+	header = binary.LittleEndian.AppendUint32(header, uint32(total))
+	header = binary.LittleEndian.AppendUint32(header, uint32(len(featureTable)))
+	header = binary.LittleEndian.AppendUint32(header, 0)
+	header = binary.LittleEndian.AppendUint32(header, uint32(len(batchTable)))
+	header = binary.LittleEndian.AppendUint32(header, 0)
+	for _, part := range [][]byte{header, featureTable, batchTable, glb.Bytes()} {
+		if _, err := w.Write(part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteFeatureGLB 将要素合并为一个网格，写为带EXT_mesh_features的GLB（3D Tiles 1.1）。
+// 顶点属性_FEATURE_ID_0为要素序号，要素属性通过EXT_structural_metadata写成属性表：
+// 全是数值的列为FLOAT64，全是布尔值的列为BOOLEAN，其余列为STRING（非字符串值按JSON编码）。
+// 属性名中不能用作元数据标识符的字符会被替换为下划线。opts可为nil
+func WriteFeatureGLB(w io.Writer, features []TesselatedFeature, opts *GLBOptions) error {
+	b, err := newBatchedGLB(features, "_FEATURE_ID_0", opts)
+	if err != nil {
+		return fmt.Errorf("glb: %v", err)
+	}
+
+	names, columns := featurePropertyColumns(features)
+	hasTable := len(names) > 0 && len(features) > 0
+	if hasTable {
+		classProps := map[string]interface{}{}
+		tableProps := map[string]interface{}{}
+		used := map[string]bool{}
+		for _, name := range names {
+			id := metadataIdentifier(name, used)
+			classProps[id], tableProps[id] = b.addPropertyColumn(name, columns[name])
+		}
+		b.doc.Extensions = map[string]interface{}{
+			"EXT_structural_metadata": map[string]interface{}{
+				"schema": map[string]interface{}{
+					"id": "tesselator",
+					"classes": map[string]interface{}{
+						"feature": map[string]interface{}{"properties": classProps},
+					},
+				},
+				"propertyTables": []interface{}{
+					map[string]interface{}{
+						"class":      "feature",
+						"count":      len(features),
+						"properties": tableProps,
+					},
+				},
+			},
+		}
+		b.doc.ExtensionsUsed = append(b.doc.ExtensionsUsed, "EXT_structural_metadata")
+	}
+
+	if len(b.doc.Meshes) > 0 {
+		featureID := map[string]interface{}{"featureCount": len(features), "attribute": 0}
+		if hasTable {
+			featureID["propertyTable"] = 0
+		}
+		b.doc.Meshes[0].Primitives[0].Extensions = map[string]interface{}{
+			"EXT_mesh_features": map[string]interface{}{"featureIds": []interface{}{featureID}},
+		}
+		b.doc.ExtensionsUsed = append([]string{"EXT_mesh_features"}, b.doc.ExtensionsUsed...)
+	}
+	return b.encode(w)
+}
+
+// metadataIdentifier 把属性名转换为合法且唯一的元数据标识符（^[a-zA-Z_][a-zA-Z0-9_]*$）
+func metadataIdentifier(name string, used map[string]bool) string {
+	var sb strings.Builder
+	for i, r := range name {
+		switch {
+		case r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z':
+			sb.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				sb.WriteByte('_')
+			}
+			sb.WriteRune(r)
+		default:
+			sb.WriteByte('_')
+		}
+	}
+	id := sb.String()
+	if id == "" {
+		id = "_"
+	}
+	base := id
+	for n := 1; used[id]; n++ {
+		id = base + "_" + strconv.Itoa(n)
+	}
+	used[id] = true
+	return id
+}
+
+// propertyNumber 把JSON数值或Go数值类型转换为float64
+func propertyNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// addPropertyColumn 写入一列属性值，返回该列在类定义和属性表中的描述
+func (b *glbBuilder) addPropertyColumn(name string, values []interface{}) (map[string]interface{}, map[string]interface{}) {
+	allNumbers, allBools, hasMissing := true, true, false
+	for _, v := range values {
+		if v == nil {
+			hasMissing = true
+			continue
+		}
+		if _, ok := propertyNumber(v); !ok {
+			allNumbers = false
+		}
+		if _, ok := v.(bool); !ok {
+			allBools = false
+		}
+	}
+	class := map[string]interface{}{"name": name}
+
+	switch {
+	case allNumbers && !allBools:
+		// 缺失值用noData表示
+		const noData = -math.MaxFloat64
+		data := make([]byte, 8*len(values))
+		for i, v := range values {
+			f := noData
+			if v != nil {
+				f, _ = propertyNumber(v)
+			}
+			binary.LittleEndian.PutUint64(data[8*i:], math.Float64bits(f))
+		}
+		class["type"] = "SCALAR"
+		class["componentType"] = "FLOAT64"
+		if hasMissing {
+			class["noData"] = noData
+		}
+		return class, map[string]interface{}{"values": b.addBufferView(data, 0)}
+
+	case allBools && !hasMissing:
+		data := make([]byte, (len(values)+7)/8)
+		for i, v := range values {
+			if v.(bool) {
+				data[i/8] |= 1 << (i % 8)
+			}
+		}
+		class["type"] = "BOOLEAN"
+		return class, map[string]interface{}{"values": b.addBufferView(data, 0)}
+	}
+
+	var data []byte
+	offsets := make([]byte, 0, 4*(len(values)+1))
+	for _, v := range values {
+		offsets = binary.LittleEndian.AppendUint32(offsets, uint32(len(data)))
+		switch s := v.(type) {
+		case nil:
+		case string:
+			data = append(data, s...)
+		default:
+			js, err := json.Marshal(s)
+			if err != nil {
+				js = []byte(fmt.Sprint(s))
+			}
+			data = append(data, js...)
+		}
+	}
+	offsets = binary.LittleEndian.AppendUint32(offsets, uint32(len(data)))
+	if len(data) == 0 {
+		// bufferView的长度不能为0
+		data = append(data, 0)
+	}
+	class["type"] = "STRING"
+	if hasMissing {
+		class["noData"] = ""
+	}
+	return class, map[string]interface{}{
+		"values":        b.addBufferView(data, 0),
+		"stringOffsets": b.addBufferView(offsets, 0),
+	}
+}
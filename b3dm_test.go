@@ -0,0 +1,235 @@
+package tesselator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+// 测试用要素：两个带属性的要素和一个没有几何的要素
+func batchTestFeatures(t *testing.T) []TesselatedFeature {
+	t.Helper()
+	features, err := DecodeGeoJSON([]byte(testFeatureCollection))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := TesselateFeatures(features, WindingRuleOdd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result[2].Properties = map[string]interface{}{"flag": true, "height": 3}
+	result[0].Properties["flag"] = false
+	result[1].Properties["flag"] = true
+	result = append(result, TesselatedFeature{ID: "empty", Properties: map[string]interface{}{"building:levels": 2, "flag": false}})
+	return result
+}
+
+// 检查批次ID属性：每个三角形的三个顶点属于同一个要素，且顶点位置与该要素的顶点一致
+func checkBatchIDs(t *testing.T, d glbPrimitiveData, attribute string, features []TesselatedFeature) {
+	t.Helper()
+	ids, ok := d.Attributes[attribute]
+	if !ok {
+		t.Fatalf("missing %s attribute", attribute)
+	}
+	triangles := make([]int, len(features))
+	for k := 0; k < len(d.Indices); k += 3 {
+		id := ids[d.Indices[k]][0]
+		if ids[d.Indices[k+1]][0] != id || ids[d.Indices[k+2]][0] != id {
+			t.Fatalf("triangle %d spans several features", k/3)
+		}
+		f := features[int(id)]
+		p := d.Positions[d.Indices[k]]
+		found := false
+		for _, v := range f.Vertices {
+			if float64(v.X) == p[0] && float64(v.Y) == p[1] && float64(v.Z) == p[2] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Fatalf("vertex %v does not belong to feature %d", p, int(id))
+		}
+		triangles[int(id)]++
+	}
+	for i, f := range features {
+		if triangles[i] != len(f.Indices)/3 {
+			t.Errorf("feature %d: expected %d triangles, got %d", i, len(f.Indices)/3, triangles[i])
+		}
+	}
+}
+
+// TestWriteB3DM 测试b3dm容器、特征表、批次表和_BATCHID属性
+func TestWriteB3DM(t *testing.T) {
+	features := batchTestFeatures(t)
+
+	var buf bytes.Buffer
+	if err := WriteB3DM(&buf, features, &GLBOptions{ZUp: true}); err != nil {
+		t.Fatalf("WriteB3DM returned an error: %v", err)
+	}
+	data := buf.Bytes()
+	le := binary.LittleEndian
+	if string(data[:4]) != "b3dm" || le.Uint32(data[4:]) != 1 || int(le.Uint32(data[8:])) != len(data) {
+		t.Fatalf("invalid b3dm header")
+	}
+	ftJSON, ftBin := int(le.Uint32(data[12:])), int(le.Uint32(data[16:]))
+	btJSON, btBin := int(le.Uint32(data[20:])), int(le.Uint32(data[24:]))
+	if ftBin != 0 || btBin != 0 || (28+ftJSON)%8 != 0 || btJSON%8 != 0 || len(data)%8 != 0 {
+		t.Fatalf("b3dm sections are not 8-byte aligned: %d %d %d", ftJSON, btJSON, len(data))
+	}
+
+	var featureTable map[string]interface{}
+	if err := json.Unmarshal(data[28:28+ftJSON], &featureTable); err != nil {
+		t.Fatalf("invalid feature table: %v", err)
+	}
+	if featureTable["BATCH_LENGTH"] != float64(len(features)) {
+		t.Errorf("unexpected BATCH_LENGTH %v", featureTable["BATCH_LENGTH"])
+	}
+	var batchTable map[string][]interface{}
+	if err := json.Unmarshal(data[28+ftJSON:28+ftJSON+btJSON], &batchTable); err != nil {
+		t.Fatalf("invalid batch table: %v", err)
+	}
+	for name, column := range batchTable {
+		if len(column) != len(features) {
+			t.Errorf("batch table column %s has %d values, expected %d", name, len(column), len(features))
+		}
+	}
+	if batchTable["name"][0] != "square with hole" || batchTable["id"][1] != float64(7) || batchTable["id"][2] != nil {
+		t.Errorf("unexpected batch table %v", batchTable)
+	}
+	if batchTable["building:levels"][3] != float64(2) {
+		t.Errorf("properties of features without geometry should be kept")
+	}
+
+	glb := data[28+ftJSON+btJSON:]
+	if len(glb)%8 != 0 {
+		t.Errorf("embedded GLB should end on an 8-byte boundary")
+	}
+	_, meshes := parseGLB(t, glb)
+	if len(meshes) != 1 || len(meshes[0]) != 1 {
+		t.Fatalf("expected a single batched primitive")
+	}
+	checkBatchIDs(t, meshes[0][0], "_BATCHID", features)
+}
+
+// TestWriteFeatureGLB 测试EXT_mesh_features特征ID和EXT_structural_metadata属性表
+func TestWriteFeatureGLB(t *testing.T) {
+	features := batchTestFeatures(t)
+
+	var buf bytes.Buffer
+	if err := WriteFeatureGLB(&buf, features, nil); err != nil {
+		t.Fatalf("WriteFeatureGLB returned an error: %v", err)
+	}
+	data := buf.Bytes()
+	doc, meshes := parseGLB(t, data)
+	checkBatchIDs(t, meshes[0][0], "_FEATURE_ID_0", features)
+
+	if len(doc.ExtensionsUsed) != 2 || doc.ExtensionsUsed[0] != "EXT_mesh_features" || doc.ExtensionsUsed[1] != "EXT_structural_metadata" {
+		t.Errorf("unexpected extensionsUsed %v", doc.ExtensionsUsed)
+	}
+
+	// 重新按通用JSON解析扩展内容
+	jsonLen := binary.LittleEndian.Uint32(data[12:])
+	var raw struct {
+		Meshes []struct {
+			Primitives []struct {
+				Extensions struct {
+					Features struct {
+						FeatureIds []struct {
+							FeatureCount  int  `json:"featureCount"`
+							Attribute     *int `json:"attribute"`
+							PropertyTable *int `json:"propertyTable"`
+						} `json:"featureIds"`
+					} `json:"EXT_mesh_features"`
+				} `json:"extensions"`
+			} `json:"primitives"`
+		} `json:"meshes"`
+		Extensions struct {
+			Metadata struct {
+				Schema struct {
+					ID      string `json:"id"`
+					Classes map[string]struct {
+						Properties map[string]struct {
+							Name          string          `json:"name"`
+							Type          string          `json:"type"`
+							ComponentType string          `json:"componentType"`
+							NoData        json.RawMessage `json:"noData"`
+						} `json:"properties"`
+					} `json:"classes"`
+				} `json:"schema"`
+				PropertyTables []struct {
+					Class      string `json:"class"`
+					Count      int    `json:"count"`
+					Properties map[string]struct {
+						Values        int  `json:"values"`
+						StringOffsets *int `json:"stringOffsets"`
+					} `json:"properties"`
+				} `json:"propertyTables"`
+			} `json:"EXT_structural_metadata"`
+		} `json:"extensions"`
+	}
+	if err := json.Unmarshal(data[20:20+jsonLen], &raw); err != nil {
+		t.Fatal(err)
+	}
+	ids := raw.Meshes[0].Primitives[0].Extensions.Features.FeatureIds
+	if len(ids) != 1 || ids[0].FeatureCount != len(features) || ids[0].Attribute == nil || *ids[0].Attribute != 0 ||
+		ids[0].PropertyTable == nil || *ids[0].PropertyTable != 0 {
+		t.Fatalf("unexpected featureIds %+v", ids)
+	}
+
+	meta := raw.Extensions.Metadata
+	class, ok := meta.Schema.Classes["feature"]
+	if !ok || meta.Schema.ID == "" || len(meta.PropertyTables) != 1 || meta.PropertyTables[0].Count != len(features) {
+		t.Fatalf("unexpected metadata %+v", meta)
+	}
+	if p := class.Properties["height"]; p.Type != "SCALAR" || p.ComponentType != "FLOAT64" || p.NoData == nil {
+		t.Errorf("height should be a FLOAT64 scalar with noData, got %+v", p)
+	}
+	if p := class.Properties["flag"]; p.Type != "BOOLEAN" {
+		t.Errorf("flag should be BOOLEAN, got %+v", p)
+	}
+	if p := class.Properties["building_levels"]; p.Name != "building:levels" {
+		t.Errorf("property names should be sanitised, got %+v", class.Properties)
+	}
+	if p := class.Properties["id"]; p.Type != "STRING" {
+		t.Errorf("mixed id column should be STRING, got %+v", p)
+	}
+
+	// 读取二进制属性值
+	bin := data[20+jsonLen+8:]
+	view := func(i int) []byte {
+		bv := doc.BufferViews[i]
+		if bv.ByteOffset%8 != 0 {
+			t.Fatalf("property bufferView %d is not 8-byte aligned", i)
+		}
+		return bin[bv.ByteOffset : bv.ByteOffset+bv.ByteLength]
+	}
+	table := meta.PropertyTables[0].Properties
+
+	heights := view(table["height"].Values)
+	want := []float64{12.5, -math.MaxFloat64, 3, -math.MaxFloat64}
+	for i, w := range want {
+		if h := math.Float64frombits(binary.LittleEndian.Uint64(heights[8*i:])); h != w {
+			t.Errorf("height %d: expected %v, got %v", i, w, h)
+		}
+	}
+	if flags := view(table["flag"].Values); len(flags) != 1 || flags[0] != 0b0110 {
+		t.Errorf("unexpected boolean bitstream %b", flags)
+	}
+
+	names := view(table["name"].Values)
+	offsets := view(*table["name"].StringOffsets)
+	str := func(i int) string {
+		a, b := binary.LittleEndian.Uint32(offsets[4*i:]), binary.LittleEndian.Uint32(offsets[4*i+4:])
+		return string(names[a:b])
+	}
+	if str(0) != "square with hole" || str(1) != "two triangles" || str(2) != "" {
+		t.Errorf("unexpected string values %q %q %q", str(0), str(1), str(2))
+	}
+	idValues := view(table["id"].Values)
+	idOffsets := view(*table["id"].StringOffsets)
+	if got := string(idValues[binary.LittleEndian.Uint32(idOffsets[4:]):binary.LittleEndian.Uint32(idOffsets[8:])]); got != "7" {
+		t.Errorf("numeric id should be JSON encoded, got %q", got)
+	}
+}
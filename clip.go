@@ -0,0 +1,355 @@
+package tesselator
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// halfPlane 半平面a·x + b·y <= c
+type halfPlane struct {
+	a, b, c float64
+}
+
+// side 返回点相对于边界直线的有向距离（未归一化），在半平面内时不大于0
+func (h halfPlane) side(v Vertex) float64 {
+	return h.a*float64(v.X) + h.b*float64(v.Y) - h.c
+}
+
+// intersect 返回线段pq与边界直线的交点，Z线性插值。端点按固定顺序参与计算，
+// 使同一条线段无论方向如何都得到同一个交点；与坐标轴平行的直线上的交点取直线上的精确坐标
+func (h halfPlane) intersect(p, q Vertex) Vertex {
+	if q.X < p.X || (q.X == p.X && q.Y < p.Y) {
+		p, q = q, p
+	}
+	sp, sq := h.side(p), h.side(q)
+	t := sp / (sp - sq)
+	v := Vertex{
+		X: float32(float64(p.X) + (float64(q.X)-float64(p.X))*t),
+		Y: float32(float64(p.Y) + (float64(q.Y)-float64(p.Y))*t),
+		Z: float32(float64(p.Z) + (float64(q.Z)-float64(p.Z))*t),
+	}
+	if h.b == 0 {
+		v.X = float32(h.c / h.a)
+	}
+	if h.a == 0 {
+		v.Y = float32(h.c / h.b)
+	}
+	return v
+}
+
+// rectHalfPlanes 返回矩形[minX, maxX]×[minY, maxY]的四个半平面
+func rectHalfPlanes(minX, minY, maxX, maxY float32) []halfPlane {
+	return []halfPlane{
+		{a: -1, c: -float64(minX)},
+		{a: 1, c: float64(maxX)},
+		{b: -1, c: -float64(minY)},
+		{b: 1, c: float64(maxY)},
+	}
+}
+
+// clipContour 用Sutherland-Hodgman算法把轮廓裁剪到半平面内，边界直线上的点视为在内部。
+// 凸区域内每一点的环绕数在裁剪后保持不变，轮廓离开和重新进入区域之间由沿边界的边连接，
+// 因此结果可能包含沿边界来回的退化部分，它们在扫描中相互抵消
+func clipContour(c Contour, h halfPlane) Contour {
+	var result Contour
+	for i, cur := range c {
+		prev := c[(i+len(c)-1)%len(c)]
+		sPrev, sCur := h.side(prev), h.side(cur)
+		if (sPrev < 0 && sCur > 0) || (sPrev > 0 && sCur < 0) {
+			result = append(result, h.intersect(prev, cur))
+		}
+		if sCur <= 0 {
+			result = append(result, cur)
+		}
+	}
+	return result
+}
+
+// clipContours 把轮廓依次裁剪到所有半平面内，丢弃顶点少于3个的结果
+func clipContours(contours []Contour, planes []halfPlane) []Contour {
+	var result []Contour
+	for _, c := range contours {
+		for _, h := range planes {
+			if len(c) < 3 {
+				break
+			}
+			c = clipContour(c, h)
+		}
+		if len(c) >= 3 {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// Rect 轴对齐矩形[MinX, MaxX]×[MinY, MaxY]
+type Rect struct {
+	MinX, MinY, MaxX, MaxY float32
+}
+
+// ClipRect 把轮廓裁剪到矩形内。每个点的环绕数在裁剪后保持不变，因此对结果使用与朝向无关的
+// 环绕规则（Odd、NonZero、AbsGeqTwo）得到原区域与矩形的交集。Positive、Negative和Nesting
+// 依赖由全部轮廓确定的整体朝向或嵌套关系，裁剪掉部分轮廓后结果可能不同，应使用TesselateClipped。
+// 洞与外轮廓照常保留，被矩形切开的洞并入外轮廓；
+// 裁剪沿矩形边界产生的来回重叠、共线的退化部分被消去，被切成几块的轮廓分成几个轮廓。
+// 矩形为空时返回nil
+func ClipRect(contours []Contour, rect Rect) []Contour {
+	if !(rect.MinX <= rect.MaxX && rect.MinY <= rect.MaxY) {
+		return nil
+	}
+	planes := rectHalfPlanes(rect.MinX, rect.MinY, rect.MaxX, rect.MaxY)
+	return cleanClippedContours(clipContours(contours, planes), planes)
+}
+
+// ClipConvex 与ClipRect相同，但裁剪区域为凸多边形，顶点可以为顺时针或逆时针，
+// 允许重复和共线的顶点。裁剪多边形不是凸多边形或面积为0时返回错误
+func ClipConvex(contours []Contour, clipPolygon Contour) ([]Contour, error) {
+	planes, err := convexHalfPlanes(clipPolygon)
+	if err != nil {
+		return nil, err
+	}
+	return cleanClippedContours(clipContours(contours, planes), planes), nil
+}
+
+// TesselateClipped 对轮廓按环绕规则确定的区域与凸多边形clipPolygon的交集进行三角剖分，输出格式与Tesselate相同。
+// 裁剪后的轮廓直接进入扫描，沿裁剪边界的退化部分在同一次扫描中被消去，不需要单独的清理
+func TesselateClipped(contours []Contour, windingRule WindingRule, clipPolygon Contour) ([]int, []Vertex, error) {
+	planes, err := convexHalfPlanes(clipPolygon)
+	if err != nil {
+		return nil, nil, err
+	}
+	validContours := make([]Contour, 0, len(contours))
+	for _, c := range contours {
+		if len(c) >= 3 {
+			validContours = append(validContours, c)
+		}
+	}
+	// 嵌套规则按裁剪前的嵌套关系确定方向，整体朝向也按裁剪前的轮廓确定，
+	// 否则裁剪掉决定朝向的轮廓后Positive和Negative的含义会反转
+	validContours, windingRule = resolveNesting(validContours, windingRule)
+	validContours = orientContours(validContours)
+	return tesselateWith(&tesselator{normal: [3]float{0, 0, 1}}, clipContours(validContours, planes), windingRule)
+}
+
+// convexHalfPlanes 返回凸多边形每条边的半平面，法向量为单位长度
+func convexHalfPlanes(polygon Contour) ([]halfPlane, error) {
+	var points Contour
+	for i, v := range polygon {
+		if !isFinite32(v.X) || !isFinite32(v.Y) {
+			return nil, fmt.Errorf("tesselator: invalid clip polygon vertex %v", v)
+		}
+		if i == 0 || v.X != points[len(points)-1].X || v.Y != points[len(points)-1].Y {
+			points = append(points, v)
+		}
+	}
+	for len(points) > 1 && points[0].X == points[len(points)-1].X && points[0].Y == points[len(points)-1].Y {
+		points = points[:len(points)-1]
+	}
+	area := contourSignedArea(points)
+	if len(points) < 3 || area == 0 {
+		return nil, fmt.Errorf("tesselator: clip polygon has no area")
+	}
+
+	var planes []halfPlane
+	extent := 0.0
+	for i, p := range points {
+		q := points[(i+1)%len(points)]
+		if area < 0 {
+			p, q = q, p
+		}
+		dx, dy := float64(q.X)-float64(p.X), float64(q.Y)-float64(p.Y)
+		l := math.Hypot(dx, dy)
+		h := halfPlane{a: dy / l, b: -dx / l}
+		h.c = h.a*float64(p.X) + h.b*float64(p.Y)
+		planes = append(planes, h)
+		extent = math.Max(extent, math.Max(math.Abs(float64(p.X)), math.Abs(float64(p.Y))))
+	}
+	tol := clipTolerance(extent)
+	for _, h := range planes {
+		for _, v := range points {
+			if h.side(v) > tol {
+				return nil, fmt.Errorf("tesselator: clip polygon is not convex")
+			}
+		}
+	}
+	return planes, nil
+}
+
+// clipTolerance 返回坐标量级为extent时判断点在裁剪边界上的容差
+func clipTolerance(extent float64) float64 {
+	return 1e-6 * (1 + extent)
+}
+
+// clipEdge 裁剪结果中的一条有向边，line为其所在的裁剪边界的序号，不在边界上时为-1
+type clipEdge struct {
+	a, b Vertex
+	line int
+}
+
+// cleanClippedContours 消去裁剪结果中沿裁剪边界来回重叠的部分和方向相反的重复边，
+// 再把剩余的边重新连成轮廓。每条边界直线上的边按有向区间的重数相加，只保留净重数，
+// 因此所有点的环绕数保持不变
+func cleanClippedContours(contours []Contour, planes []halfPlane) []Contour {
+	extent := 0.0
+	for _, c := range contours {
+		for _, v := range c {
+			extent = math.Max(extent, math.Max(math.Abs(float64(v.X)), math.Abs(float64(v.Y))))
+		}
+	}
+	tol := clipTolerance(extent)
+
+	var edges []clipEdge
+	onLine := make([][]clipEdge, len(planes))
+	for _, c := range contours {
+		for i, a := range c {
+			b := c[(i+1)%len(c)]
+			if a.X == b.X && a.Y == b.Y {
+				continue
+			}
+			line := -1
+			for k, h := range planes {
+				if math.Abs(h.side(a)) <= tol && math.Abs(h.side(b)) <= tol {
+					line = k
+					break
+				}
+			}
+			if line < 0 {
+				edges = append(edges, clipEdge{a, b, -1})
+			} else {
+				onLine[line] = append(onLine[line], clipEdge{a, b, line})
+			}
+		}
+	}
+	edges = cancelOppositeEdges(edges)
+	for k, h := range planes {
+		edges = append(edges, netLineEdges(onLine[k], h, k)...)
+	}
+	return chainClipEdges(edges)
+}
+
+// cancelOppositeEdges 成对消去方向相反的重复边
+func cancelOppositeEdges(edges []clipEdge) []clipEdge {
+	type key struct{ ax, ay, bx, by float32 }
+	open := map[key][]int{}
+	removed := make([]bool, len(edges))
+	for i, e := range edges {
+		reverse := key{e.b.X, e.b.Y, e.a.X, e.a.Y}
+		if js := open[reverse]; len(js) > 0 {
+			removed[i], removed[js[len(js)-1]] = true, true
+			open[reverse] = js[:len(js)-1]
+			continue
+		}
+		k := key{e.a.X, e.a.Y, e.b.X, e.b.Y}
+		open[k] = append(open[k], i)
+	}
+	result := edges[:0]
+	for i, e := range edges {
+		if !removed[i] {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// netLineEdges 把同一条边界直线上的有向边按区间重数相加，返回净重数对应的边
+func netLineEdges(edges []clipEdge, h halfPlane, line int) []clipEdge {
+	if len(edges) == 0 {
+		return nil
+	}
+	// 沿直线方向的参数
+	param := func(v Vertex) float64 { return -h.b*float64(v.X) + h.a*float64(v.Y) }
+	type point struct {
+		u float64
+		v Vertex
+	}
+	var points []point
+	for _, e := range edges {
+		points = append(points, point{param(e.a), e.a}, point{param(e.b), e.b})
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].u < points[j].u })
+	unique := points[:0]
+	for i, p := range points {
+		if i == 0 || p.u != unique[len(unique)-1].u {
+			unique = append(unique, p)
+		}
+	}
+	find := func(u float64) int {
+		return sort.Search(len(unique), func(i int) bool { return unique[i].u >= u })
+	}
+	count := make([]int, len(unique))
+	for _, e := range edges {
+		i, j := find(param(e.a)), find(param(e.b))
+		if i < j {
+			count[i]++
+			count[j]--
+		} else {
+			count[j]--
+			count[i]++
+		}
+	}
+	var result []clipEdge
+	m := 0
+	for i := 0; i+1 < len(unique); i++ {
+		m += count[i]
+		for n := 0; n < m; n++ {
+			result = append(result, clipEdge{unique[i].v, unique[i+1].v, line})
+		}
+		for n := 0; n < -m; n++ {
+			result = append(result, clipEdge{unique[i+1].v, unique[i].v, line})
+		}
+	}
+	return result
+}
+
+// chainClipEdges 把有向边连成闭合轮廓。一个顶点有多条出边时取相对入边最右转的一条，
+// 使只在顶点处接触的区域分成不同的轮廓；同一条边界直线上的中间顶点被去掉
+func chainClipEdges(edges []clipEdge) []Contour {
+	type key struct{ x, y float32 }
+	outgoing := map[key][]int{}
+	for i, e := range edges {
+		k := key{e.a.X, e.a.Y}
+		outgoing[k] = append(outgoing[k], i)
+	}
+	used := make([]bool, len(edges))
+	var result []Contour
+	for start := range edges {
+		if used[start] {
+			continue
+		}
+		var chain []clipEdge
+		for cur := start; cur >= 0; {
+			used[cur] = true
+			e := edges[cur]
+			chain = append(chain, e)
+			if e.b.X == edges[start].a.X && e.b.Y == edges[start].a.Y {
+				break
+			}
+			next, best := -1, math.Inf(1)
+			dx, dy := float64(e.b.X)-float64(e.a.X), float64(e.b.Y)-float64(e.a.Y)
+			for _, j := range outgoing[key{e.b.X, e.b.Y}] {
+				if used[j] {
+					continue
+				}
+				ox, oy := float64(edges[j].b.X)-float64(edges[j].a.X), float64(edges[j].b.Y)-float64(edges[j].a.Y)
+				if turn := math.Atan2(dx*oy-dy*ox, dx*ox+dy*oy); turn < best {
+					next, best = j, turn
+				}
+			}
+			cur = next
+		}
+
+		var c Contour
+		for i, e := range chain {
+			prev := chain[(i+len(chain)-1)%len(chain)]
+			if e.line >= 0 && e.line == prev.line {
+				continue
+			}
+			c = append(c, e.a)
+		}
+		if len(c) >= 3 {
+			result = append(result, c)
+		}
+	}
+	return result
+}
@@ -0,0 +1,155 @@
+package tesselator
+
+import (
+	"math"
+	"testing"
+)
+
+// TestClipRect 裁剪结果不含沿边界的退化部分，被切开的洞并入外轮廓，被切成几块的轮廓分开
+func TestClipRect(t *testing.T) {
+	contours := []Contour{squareContour(0, 0, 10), reversedContour(squareContour(4, 4, 2))}
+	clipped := ClipRect(contours, Rect{MinX: 5, MinY: -5, MaxX: 15, MaxY: 15})
+	if len(clipped) != 1 {
+		t.Fatalf("expected one contour, got %v", clipped)
+	}
+	if area := contourSignedArea(clipped[0]); math.Abs(area-48) > 1e-6 {
+		t.Errorf("expected area 48, got %g", area)
+	}
+	checkClippedContours(t, clipped)
+
+	// U形被切开两臂
+	u := toContour([]Vector2f{{0, 0}, {6, 0}, {6, 6}, {4, 6}, {4, 2}, {2, 2}, {2, 6}, {0, 6}})
+	clipped = ClipRect([]Contour{u}, Rect{MinX: -1, MinY: 3, MaxX: 7, MaxY: 5})
+	if len(clipped) != 2 {
+		t.Fatalf("expected two contours, got %v", clipped)
+	}
+	for _, c := range clipped {
+		if area := contourSignedArea(c); math.Abs(area-4) > 1e-6 {
+			t.Errorf("expected area 4, got %g for %v", area, c)
+		}
+	}
+	checkClippedContours(t, clipped)
+
+	// 矩形内的轮廓保持不变，矩形外的轮廓和空矩形得到空结果
+	if clipped := ClipRect([]Contour{u}, Rect{MinX: -1, MinY: -1, MaxX: 7, MaxY: 7}); len(clipped) != 1 || len(clipped[0]) != len(u) {
+		t.Errorf("expected the contour unchanged, got %v", clipped)
+	}
+	if clipped := ClipRect([]Contour{u}, Rect{MinX: 10, MinY: 10, MaxX: 20, MaxY: 20}); len(clipped) != 0 {
+		t.Errorf("expected no contours, got %v", clipped)
+	}
+	if clipped := ClipRect([]Contour{u}, Rect{MinX: 1, MaxX: 0}); clipped != nil {
+		t.Errorf("expected nil for an empty rectangle, got %v", clipped)
+	}
+}
+
+// TestClipConvex 裁剪后按各环绕规则剖分都与在同一次扫描中裁剪的结果相同（这些输入裁剪后整体朝向不变）
+func TestClipConvex(t *testing.T) {
+	clip := toContour([]Vector2f{{-8, -6}, {9, -3}, {7, 4}, {0, 9}, {-9, 2}})
+	star := GenerateStar(7, 0, 0, 10, 4)
+	// 自相交的七角星
+	var selfIntersecting Contour
+	for i := 0; i < 7; i++ {
+		selfIntersecting = append(selfIntersecting, star[(i*6)%14])
+	}
+	for _, contours := range [][]Contour{
+		{star, reversedContour(squareContour(-3, -3, 6))},
+		{selfIntersecting},
+	} {
+		clipped, err := ClipConvex(contours, reversedContour(clip))
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkClippedContours(t, clipped)
+		for _, rule := range []WindingRule{WindingRuleOdd, WindingRuleNonzero, WindingRulePositive, WindingRuleAbsGeqTwo} {
+			indices, vertices, err := Tesselate(clipped, rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fusedIndices, fusedVertices, err := TesselateClipped(contours, rule, clip)
+			if err != nil {
+				t.Fatal(err)
+			}
+			area, fused := meshArea(indices, vertices), meshArea(fusedIndices, fusedVertices)
+			if math.Abs(area-fused) > 1e-3 {
+				t.Errorf("rule %v: clipped contours cover %g, fused clipping covers %g", rule, area, fused)
+			}
+			for _, v := range fusedVertices {
+				if !Contains([]Contour{clip}, WindingRuleOdd, v) && !onContour(clip, v) {
+					t.Errorf("vertex %v is outside the clip polygon", v)
+				}
+			}
+		}
+	}
+
+	if _, err := ClipConvex([]Contour{star}, star); err == nil {
+		t.Error("expected an error for a concave clip polygon")
+	}
+	if _, err := ClipConvex([]Contour{star}, toContour([]Vector2f{{0, 0}, {1, 1}, {2, 2}})); err == nil {
+		t.Error("expected an error for a degenerate clip polygon")
+	}
+}
+
+// checkClippedContours 检查轮廓中没有零长度的边和原路返回的尖刺
+func checkClippedContours(t *testing.T, contours []Contour) {
+	t.Helper()
+	for _, c := range contours {
+		for i, v := range c {
+			next, prev := c[(i+1)%len(c)], c[(i+len(c)-1)%len(c)]
+			if v.X == next.X && v.Y == next.Y {
+				t.Errorf("contour %v has a zero-length edge at %v", c, v)
+			}
+			cross := float64(v.X-prev.X)*float64(next.Y-v.Y) - float64(v.Y-prev.Y)*float64(next.X-v.X)
+			dot := float64(v.X-prev.X)*float64(next.X-v.X) + float64(v.Y-prev.Y)*float64(next.Y-v.Y)
+			if math.Abs(cross) < 1e-9 && dot < 0 {
+				t.Errorf("contour %v doubles back at %v", c, v)
+			}
+		}
+	}
+}
+
+// onContour 判断点是否在轮廓的边上
+func onContour(c Contour, p Vertex) bool {
+	for i, a := range c {
+		b := c[(i+1)%len(c)]
+		if segmentDistance(p, a, b) < 1e-4 {
+			return true
+		}
+	}
+	return false
+}
+
+// TestClipOrientation 整体朝向由裁剪前的轮廓确定：裁剪掉决定朝向的大轮廓后，
+// Positive不能把剩下的反向小轮廓当作填充区域；与朝向无关的规则可以直接剖分ClipRect的结果
+func TestClipOrientation(t *testing.T) {
+	contours := []Contour{reversedContour(squareContour(0, 0, 10)), squareContour(20, 20, 1)}
+	clip := squareContour(19, 19, 3)
+	if indices, vertices, _ := Tesselate(contours, WindingRulePositive); math.Abs(meshArea(indices, vertices)-100) > 1e-3 {
+		t.Fatalf("expected the unclipped positive region to be the large square")
+	}
+	if Contains(contours, WindingRulePositive, Vertex{X: 20.5, Y: 20.5}) {
+		t.Fatal("the small square should not be inside the positive region")
+	}
+	indices, vertices, err := TesselateClipped(contours, WindingRulePositive, clip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(indices) != 0 {
+		t.Errorf("expected an empty result with the positive rule, got area %.3f", meshArea(indices, vertices))
+	}
+	// Negative取反向的小正方形；Nesting中两个互不包含的正方形都是外轮廓
+	for _, rule := range []WindingRule{WindingRuleNegative, WindingRuleNesting} {
+		indices, vertices, _ := TesselateClipped(contours, rule, clip)
+		if area := meshArea(indices, vertices); math.Abs(area-1) > 1e-3 {
+			t.Errorf("rule %d: expected the small square, got area %.3f", rule, area)
+		}
+	}
+
+	clipped := ClipRect(contours, Rect{MinX: 19, MinY: 19, MaxX: 22, MaxY: 22})
+	for _, rule := range []WindingRule{WindingRuleOdd, WindingRuleNonzero} {
+		indices, vertices, _ := Tesselate(clipped, rule)
+		fusedIndices, fusedVertices, _ := TesselateClipped(contours, rule, clip)
+		if a, b := meshArea(indices, vertices), meshArea(fusedIndices, fusedVertices); math.Abs(a-1) > 1e-3 || math.Abs(b-1) > 1e-3 {
+			t.Errorf("rule %d: expected area 1, got %.3f and %.3f", rule, a, b)
+		}
+	}
+}
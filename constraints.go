@@ -0,0 +1,76 @@
+package tesselator
+
+import "fmt"
+
+// TesselateWithConstraints 与Tesselate相同，但额外把约束折线（如山脊线、道路、地块分界）
+// 作为边加入三角剖分。约束折线是开放的，不影响环绕数和填充区域；它们在与轮廓和其他约束
+// 相交处被打断，位于填充区域内的部分成为输出三角形的边，区域外的部分被丢弃。
+// 顶点数少于2的约束被忽略
+func TesselateWithConstraints(contours []Contour, constraints []Contour, windingRule WindingRule) ([]int, []Vertex, error) {
+	return tesselateWithExtras(&tesselator{}, contours, constraints, windingRule, nil)
+}
+
+// tesselateWithExtras 在轮廓之外加入约束折线进行三角剖分。t中可以预先设置剖分后插入的点
+// 和边翻转等选项；normal为nil时自动计算法线
+func tesselateWithExtras(t *tesselator, contours []Contour, constraints []Contour, windingRule WindingRule, normal []float) ([]int, []Vertex, error) {
+	validContours := make([]Contour, 0, len(contours))
+	for _, c := range contours {
+		if len(c) >= 3 {
+			validContours = append(validContours, c)
+		}
+	}
+	if len(validContours) == 0 {
+		return []int{}, []Vertex{}, nil
+	}
+	validContours, windingRule = resolveNesting(validContours, windingRule)
+
+	t.keepDegenerateContours = true
+	addContours(t, validContours)
+	for _, c := range constraints {
+		addPolyline(t, c)
+	}
+
+	if !tessTesselate(t, windingRule, elementTypePolygons, 3, 3, normal) {
+		return nil, nil, fmt.Errorf("libtess2: tessTesselate failed")
+	}
+
+	vertices := make([]Vertex, t.vertexCount)
+	for i := range vertices {
+		vertices[i] = Vertex{X: float32(t.vertices[i*3]), Y: float32(t.vertices[i*3+1]), Z: float32(t.vertices[i*3+2])}
+	}
+	elements := make([]int, len(t.elements))
+	for i, e := range t.elements {
+		elements[i] = int(e)
+	}
+	return elements, vertices, nil
+}
+
+// addPolyline 把开放折线作为一串边加入tesselator。边的两侧属于同一个面，
+// 环绕数为0，扫描时只参与求交和剖分，翻转边时保持不变。连续的重复顶点被跳过
+func addPolyline(t *tesselator, polyline Contour) {
+	var e *halfEdge
+	prev := 0
+	for i := 1; i < len(polyline); i++ {
+		a, b := polyline[prev], polyline[i]
+		if a.X == b.X && a.Y == b.Y && a.Z == b.Z {
+			continue
+		}
+		if t.mesh == nil {
+			t.mesh = tessMeshNewMesh()
+		}
+		if e == nil {
+			e = tessMeshMakeEdge(t.mesh)
+			e.Org.coords = [3]float{float(a.X), float(a.Y), float(a.Z)}
+			e.Org.idx = t.vertexIndexCounter
+			t.vertexIndexCounter++
+		} else {
+			e = tessMeshAddEdgeVertex(t.mesh, e)
+		}
+		e.constrained = true
+		e.Sym.constrained = true
+		e.dst().coords = [3]float{float(b.X), float(b.Y), float(b.Z)}
+		e.dst().idx = t.vertexIndexCounter
+		t.vertexIndexCounter++
+		prev = i
+	}
+}
@@ -0,0 +1,100 @@
+package tesselator
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// meshHasEdge 判断三角形中是否有连接a和b的边
+func meshHasEdge(indices []int, vertices []Vertex, a, b Vertex) bool {
+	at := func(i int, v Vertex) bool { return vertices[i].X == v.X && vertices[i].Y == v.Y }
+	for i := 0; i < len(indices); i += 3 {
+		for k := 0; k < 3; k++ {
+			p, q := indices[i+k], indices[i+(k+1)%3]
+			if (at(p, a) && at(q, b)) || (at(p, b) && at(q, a)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// TestTesselateWithConstraints 约束折线成为三角形的边，不改变填充区域
+func TestTesselateWithConstraints(t *testing.T) {
+	square := squareContour(0, 0, 10)
+	hole := reversedContour(squareContour(6, 6, 2))
+	constraints := []Contour{
+		// 穿过轮廓边界的直线，在边界处和与其他约束的交点处打断
+		toContour([]Vector2f{{-5, 5}, {15, 5}}),
+		// 完全位于内部的折线
+		toContour([]Vector2f{{1, 1}, {3, 4}, {4, 1}}),
+		// 穿过洞的直线，洞内的部分被丢弃
+		toContour([]Vector2f{{7, -3}, {7, 12}}),
+		// 与轮廓边重合的约束和退化的约束
+		toContour([]Vector2f{{0, 0}, {10, 0}}),
+		toContour([]Vector2f{{2, 8}}),
+		toContour([]Vector2f{{2, 8}, {2, 8}}),
+	}
+	indices, vertices, err := TesselateWithConstraints([]Contour{square, hole}, constraints, WindingRuleOdd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if area := meshArea(indices, vertices); math.Abs(area-96) > 1e-4 {
+		t.Errorf("expected area 96, got %g", area)
+	}
+	for _, e := range [][2]Vertex{
+		{{X: 0, Y: 5}, {X: 7, Y: 5}},
+		{{X: 7, Y: 5}, {X: 10, Y: 5}},
+		{{X: 1, Y: 1}, {X: 3, Y: 4}},
+		{{X: 3, Y: 4}, {X: 4, Y: 1}},
+		{{X: 7, Y: 0}, {X: 7, Y: 5}},
+		{{X: 7, Y: 5}, {X: 7, Y: 6}},
+		{{X: 7, Y: 8}, {X: 7, Y: 10}},
+	} {
+		if !meshHasEdge(indices, vertices, e[0], e[1]) {
+			t.Errorf("constraint edge %v-%v is missing", e[0], e[1])
+		}
+	}
+	if meshHasEdge(indices, vertices, Vertex{X: 7, Y: 6}, Vertex{X: 7, Y: 8}) {
+		t.Error("constraint piece inside the hole should be discarded")
+	}
+	for _, v := range vertices {
+		if v.X < 0 || v.X > 10 || v.Y < 0 || v.Y > 10 {
+			t.Errorf("vertex %v of a discarded constraint piece is in the output", v)
+		}
+	}
+
+	// 随机约束不改变面积，每个三角形都不跨越水平约束线
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		contours := []Contour{GenerateStar(5+r.Intn(5), 0, 0, 10, 4)}
+		var lines []Contour
+		for k := 0; k < 5; k++ {
+			y := float64(r.Intn(17) - 8)
+			lines = append(lines, toContour([]Vector2f{{-12, y + 0.5}, {12, y + 0.5}}))
+			lines = append(lines, toContour([]Vector2f{{r.Float64()*20 - 10, r.Float64()*20 - 10}, {r.Float64()*20 - 10, r.Float64()*20 - 10}}))
+		}
+		plainIndices, plainVertices, _ := Tesselate(contours, WindingRuleNonzero)
+		indices, vertices, err := TesselateWithConstraints(contours, lines, WindingRuleNonzero)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a, b := meshArea(indices, vertices), meshArea(plainIndices, plainVertices); math.Abs(a-b) > 1e-3 {
+			t.Fatalf("constraints changed the area from %g to %g", b, a)
+		}
+		for j := 0; j < len(indices); j += 3 {
+			for k := 0; k < len(lines); k += 2 {
+				y := lines[k][0].Y
+				above, below := false, false
+				for _, v := range indices[j : j+3] {
+					above = above || vertices[v].Y > y+1e-4
+					below = below || vertices[v].Y < y-1e-4
+				}
+				if above && below {
+					t.Fatalf("triangle %v crosses the constraint y=%g", indices[j:j+3], y)
+				}
+			}
+		}
+	}
+}
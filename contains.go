@@ -0,0 +1,154 @@
+package tesselator
+
+import "math"
+
+// windingEdge 轮廓在XY平面上的一条有向边
+type windingEdge struct {
+	x0, y0, x1, y1 float64
+}
+
+// crossing 返回点(x, y)向右的射线穿过边时对环绕数的贡献：向上穿过为+1，向下穿过为-1。
+// 边在y方向上按半开区间处理，点在边上时不计入
+func (e *windingEdge) crossing(x, y float64) int {
+	side := (e.x1-e.x0)*(y-e.y0) - (x-e.x0)*(e.y1-e.y0)
+	if e.y0 <= y {
+		if e.y1 > y && side > 0 {
+			return 1
+		}
+	} else if e.y1 <= y && side < 0 {
+		return -1
+	}
+	return 0
+}
+
+// windingEdges 取出轮廓在XY平面上的边，并返回环绕数的符号：与Tesselate的朝向检查一致，
+// 所有轮廓的有向面积之和为负时取-1，使逆时针和顺时针的输入得到相同的结果
+func windingEdges(contours []Contour) ([]windingEdge, int) {
+	var edges []windingEdge
+	area := 0.0
+	for _, c := range contours {
+		if len(c) < 3 {
+			continue
+		}
+		area += contourSignedArea(c)
+		for i, v := range c {
+			w := c[(i+1)%len(c)]
+			// 水平边对射线没有贡献，保留它们用于判断点是否在边界上
+			edges = append(edges, windingEdge{float64(v.X), float64(v.Y), float64(w.X), float64(w.Y)})
+		}
+	}
+	if area < 0 {
+		return edges, -1
+	}
+	return edges, 1
+}
+
+// WindingNumber 返回点p（只使用XY坐标）相对于轮廓的环绕数，其符号约定与Tesselate相同：
+// 所有轮廓的有向面积之和非负时逆时针轮廓计为+1，否则整体取反。
+// 顶点数少于3的轮廓被忽略。恰好位于边上的点按其右侧（水平边为上方）紧邻的区域计算，
+// 因此共享一条边的相邻区域不会同时包含该点
+func WindingNumber(contours []Contour, p Vertex) int {
+	edges, sign := windingEdges(contours)
+	x, y := float64(p.X), float64(p.Y)
+	n := 0
+	for i := range edges {
+		n += edges[i].crossing(x, y)
+	}
+	return sign * n
+}
+
+// Contains 判断点p是否位于按环绕规则填充的区域内，与Tesselate对同一输入的判定一致
+func Contains(contours []Contour, windingRule WindingRule, p Vertex) bool {
+	contours, windingRule = resolveNesting(contours, windingRule)
+	return windingRule.isInside(WindingNumber(contours, p))
+}
+
+// PreparedContours 为重复的点查询预先建立边索引的轮廓。
+// 边按y坐标分桶，每次查询只检查与点所在水平带相交的边，结果与WindingNumber和Contains相同
+type PreparedContours struct {
+	windingRule WindingRule
+	sign        int
+	edges       []windingEdge
+	minY, maxY  float64
+	bandHeight  float64
+	bands       [][]int32
+}
+
+// NewPreparedContours 为轮廓建立边索引。索引保存轮廓的副本，之后修改轮廓不影响查询结果
+func NewPreparedContours(contours []Contour, windingRule WindingRule) *PreparedContours {
+	contours, windingRule = resolveNesting(contours, windingRule)
+	edges, sign := windingEdges(contours)
+	p := &PreparedContours{windingRule: windingRule, sign: sign, edges: edges}
+	if len(edges) == 0 {
+		return p
+	}
+
+	p.minY, p.maxY = math.Inf(1), math.Inf(-1)
+	for _, e := range edges {
+		p.minY = math.Min(p.minY, math.Min(e.y0, e.y1))
+		p.maxY = math.Max(p.maxY, math.Max(e.y0, e.y1))
+	}
+	// 水平带的数量与边数相当，每条边平均只落在少数几个带中
+	count := len(edges)
+	p.bandHeight = (p.maxY - p.minY) / float64(count)
+	if p.bandHeight == 0 {
+		// 所有边都是水平的
+		p.bandHeight = 1
+	}
+	p.bands = make([][]int32, count)
+	for i, e := range edges {
+		lo, hi := p.band(math.Min(e.y0, e.y1)), p.band(math.Max(e.y0, e.y1))
+		for b := lo; b <= hi; b++ {
+			p.bands[b] = append(p.bands[b], int32(i))
+		}
+	}
+	return p
+}
+
+// band 返回y所在水平带的序号，超出范围时取最近的带
+func (p *PreparedContours) band(y float64) int {
+	b := int((y - p.minY) / p.bandHeight)
+	if b < 0 {
+		return 0
+	}
+	if b >= len(p.bands) {
+		return len(p.bands) - 1
+	}
+	return b
+}
+
+// WindingNumber 返回点p相对于轮廓的环绕数，含义与包级函数WindingNumber相同
+func (p *PreparedContours) WindingNumber(v Vertex) int {
+	x, y := float64(v.X), float64(v.Y)
+	if len(p.bands) == 0 || y < p.minY || y >= p.maxY {
+		return 0
+	}
+	n := 0
+	for _, i := range p.bands[p.band(y)] {
+		n += p.edges[i].crossing(x, y)
+	}
+	return p.sign * n
+}
+
+// Contains 判断点p是否位于按建立索引时的环绕规则填充的区域内
+func (p *PreparedContours) Contains(v Vertex) bool {
+	return p.windingRule.isInside(p.WindingNumber(v))
+}
+
+// classify 返回点p的环绕数，并判断它是否恰好位于某条边上（此时环绕数为0）
+func (p *PreparedContours) classify(v Vertex) (int, bool) {
+	x, y := float64(v.X), float64(v.Y)
+	if len(p.bands) == 0 || y < p.minY || y > p.maxY {
+		return 0, false
+	}
+	n := 0
+	for _, i := range p.bands[p.band(y)] {
+		e := &p.edges[i]
+		if (e.x1-e.x0)*(y-e.y0)-(x-e.x0)*(e.y1-e.y0) == 0 &&
+			math.Min(e.x0, e.x1) <= x && x <= math.Max(e.x0, e.x1) && math.Min(e.y0, e.y1) <= y && y <= math.Max(e.y0, e.y1) {
+			return 0, true
+		}
+		n += e.crossing(x, y)
+	}
+	return p.sign * n, false
+}
@@ -0,0 +1,90 @@
+package tesselator
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestWindingNumber 测试环绕数的符号约定和各环绕规则的判定
+func TestWindingNumber(t *testing.T) {
+	square := toContour([]Vector2f{{0, 0}, {10, 0}, {10, 10}, {0, 10}})
+	inner := toContour([]Vector2f{{2, 2}, {8, 2}, {8, 8}, {2, 8}})
+	reversed := toContour([]Vector2f{{2, 2}, {2, 8}, {8, 8}, {8, 2}})
+
+	cases := []struct {
+		name     string
+		contours []Contour
+		point    Vertex
+		winding  int
+	}{
+		{"inside", []Contour{square}, Vertex{X: 5, Y: 5}, 1},
+		{"outside", []Contour{square}, Vertex{X: 15, Y: 5}, 0},
+		// 整体为顺时针时符号取反，与Tesselate的朝向检查一致
+		{"clockwise", []Contour{reversed}, Vertex{X: 5, Y: 5}, 1},
+		{"nested same direction", []Contour{square, inner}, Vertex{X: 5, Y: 5}, 2},
+		{"hole", []Contour{square, reversed}, Vertex{X: 5, Y: 5}, 0},
+		{"between", []Contour{square, reversed}, Vertex{X: 1, Y: 5}, 1},
+		// 边界上的点按右侧和上方紧邻的区域判断
+		{"left edge", []Contour{square}, Vertex{X: 0, Y: 5}, 1},
+		{"bottom edge", []Contour{square}, Vertex{X: 5, Y: 0}, 1},
+		{"right edge", []Contour{square}, Vertex{X: 10, Y: 5}, 0},
+		{"top edge", []Contour{square}, Vertex{X: 5, Y: 10}, 0},
+		{"degenerate", []Contour{{{X: 0, Y: 0}, {X: 10, Y: 10}}}, Vertex{X: 5, Y: 5}, 0},
+	}
+	for _, c := range cases {
+		if n := WindingNumber(c.contours, c.point); n != c.winding {
+			t.Errorf("%s: expected winding number %d, got %d", c.name, c.winding, n)
+		}
+		if n := NewPreparedContours(c.contours, WindingRuleOdd).WindingNumber(c.point); n != c.winding {
+			t.Errorf("%s: prepared winding number %d, expected %d", c.name, n, c.winding)
+		}
+	}
+
+	nested := []Contour{square, inner}
+	p := Vertex{X: 5, Y: 5}
+	if Contains(nested, WindingRuleOdd, p) {
+		t.Errorf("odd rule should leave the doubly covered centre empty")
+	}
+	if !Contains(nested, WindingRuleNonzero, p) || !Contains(nested, WindingRuleAbsGeqTwo, p) {
+		t.Errorf("nonzero and abs>=2 rules should fill the doubly covered centre")
+	}
+	if !Contains(nested, WindingRulePositive, p) || Contains(nested, WindingRuleNegative, p) {
+		t.Errorf("positive rule should fill the centre and negative rule should not")
+	}
+}
+
+// TestContainsMatchesTesselate 在随机点上比较Contains与Tesselate的剖分结果，并检查预处理的查询结果相同
+func TestContainsMatchesTesselate(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	shapes := [][]Contour{
+		{GenerateStar(7, 0, 0, 50, 20)},
+		{GenerateRandomPolygon(40, 0, 0, 50)},
+		addPolygonWithHole(),
+		// 自相交且方向相反的重叠轮廓
+		{toContour([]Vector2f{{-40, -40}, {40, 40}, {40, -40}, {-40, 40}}), GenerateRegularPolygon(6, 0, 0, 30)},
+	}
+	rules := []WindingRule{WindingRuleOdd, WindingRuleNonzero, WindingRulePositive, WindingRuleNegative, WindingRuleAbsGeqTwo}
+	for s, contours := range shapes {
+		for _, rule := range rules {
+			indices, vertices, err := Tesselate(contours, rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			prepared := NewPreparedContours(contours, rule)
+			for i := 0; i < 500; i++ {
+				x, y := r.Float64()*120-60, r.Float64()*120-60
+				p := Vertex{X: float32(x), Y: float32(y)}
+				contains := Contains(contours, rule, p)
+				if prepared.Contains(p) != contains || prepared.WindingNumber(p) != WindingNumber(contours, p) {
+					t.Fatalf("shape %d rule %d: prepared query differs at %v", s, rule, p)
+				}
+				if covered := meshCoverage(indices, vertices, float64(p.X), float64(p.Y)) > 0; covered && !contains {
+					t.Errorf("shape %d rule %d: %v is filled by Tesselate but not contained", s, rule, p)
+				} else if !covered && contains && meshCoverage(indices, vertices, float64(p.X)+1e-3, float64(p.Y)+1e-3) == 0 {
+					// 未被覆盖的点只可能是恰好落在三角形边上
+					t.Errorf("shape %d rule %d: %v is contained but not filled by Tesselate", s, rule, p)
+				}
+			}
+		}
+	}
+}
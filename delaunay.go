@@ -0,0 +1,77 @@
+package tesselator
+
+import (
+	"math"
+	"sort"
+)
+
+// ConvexHull 返回点集在XY平面上的凸包，逆时针排列，不包含共线的点和重复的点。
+// 非有限的点被忽略；不同的点少于3个或全部共线时返回的轮廓少于3个顶点
+func ConvexHull(points []Vertex) Contour {
+	vertices := make([]*vertex, 0, len(points))
+	for _, p := range points {
+		if isFinite32(p.X) && isFinite32(p.Y) {
+			vertices = append(vertices, &vertex{coords: [3]float{float(p.X), float(p.Y), float(p.Z)}, s: float(p.X), t: float(p.Y)})
+		}
+	}
+	sort.SliceStable(vertices, func(i, j int) bool {
+		return vertices[i].s < vertices[j].s || (vertices[i].s == vertices[j].s && vertices[i].t < vertices[j].t)
+	})
+	unique := vertices[:0]
+	for i, v := range vertices {
+		if i == 0 || !vertEq(v, vertices[i-1]) {
+			unique = append(unique, v)
+		}
+	}
+	if len(unique) < 3 {
+		return hullContour(unique)
+	}
+
+	// 单调链：先求下半部分再求上半部分，弹出不构成严格左转的顶点
+	hull := make([]*vertex, 0, 2*len(unique))
+	for _, v := range unique {
+		for len(hull) >= 2 && vertCCW(hull[len(hull)-2], v, hull[len(hull)-1]) {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, v)
+	}
+	lower := len(hull) + 1
+	for i := len(unique) - 2; i >= 0; i-- {
+		v := unique[i]
+		for len(hull) >= lower && vertCCW(hull[len(hull)-2], v, hull[len(hull)-1]) {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, v)
+	}
+	return hullContour(hull[:len(hull)-1])
+}
+
+// hullContour 把顶点转换为轮廓
+func hullContour(vertices []*vertex) Contour {
+	c := make(Contour, len(vertices))
+	for i, v := range vertices {
+		c[i] = Vertex{X: float32(v.coords[0]), Y: float32(v.coords[1]), Z: float32(v.coords[2])}
+	}
+	return c
+}
+
+func isFinite32(x float32) bool {
+	return !math.IsNaN(float64(x)) && !math.IsInf(float64(x), 0)
+}
+
+// Delaunay 对点集在XY平面上进行Delaunay三角剖分，点保留自身的Z，输出格式与Tesselate相同。
+// constraints为可选的约束折线，它们的顶点也作为输入点，折线在相交处被打断并作为三角形的边保留，
+// 此时结果为约束Delaunay三角剖分。重复的点只输出一次；点少于3个或全部共线时返回空结果
+func Delaunay(points []Vertex, constraints []Contour) ([]int, []Vertex, error) {
+	all := append([]Vertex(nil), points...)
+	for _, c := range constraints {
+		all = append(all, c...)
+	}
+	hull := ConvexHull(all)
+	if len(hull) < 3 {
+		return []int{}, []Vertex{}, nil
+	}
+	// 先剖分凸包并插入其余的点，再翻转边直到满足空圆性质
+	t := &tesselator{steinerPoints: points, delaunay: true}
+	return tesselateWithExtras(t, []Contour{hull}, constraints, WindingRuleOdd, []float{0, 0, 1})
+}
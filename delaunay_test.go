@@ -0,0 +1,95 @@
+package tesselator
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// TestConvexHull 凸包为逆时针，不含内部、共线和重复的点
+func TestConvexHull(t *testing.T) {
+	var points []Vertex
+	for x := 0; x <= 4; x++ {
+		for y := 0; y <= 4; y++ {
+			points = append(points, Vertex{X: float32(x), Y: float32(y)}, Vertex{X: float32(x), Y: float32(y)})
+		}
+	}
+	points = append(points, Vertex{X: float32(math.NaN()), Y: 1})
+	expected := Contour{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}}
+	if hull := ConvexHull(points); !reflect.DeepEqual(hull, expected) {
+		t.Errorf("expected %v, got %v", expected, hull)
+	}
+	if hull := ConvexHull([]Vertex{{X: 0}, {X: 1}, {X: 2}, {X: 1}}); len(hull) >= 3 {
+		t.Errorf("collinear points should not form a hull, got %v", hull)
+	}
+}
+
+// TestDelaunay 每个三角形的外接圆内没有其他顶点，所有点都是顶点
+func TestDelaunay(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		// 点较多时凸包的初始剖分中有大量狭长三角形
+		n := 200
+		if i == 0 {
+			n = 3000
+		}
+		var points []Vertex
+		for k := 0; k < n; k++ {
+			points = append(points, Vertex{X: float32(r.Float64() * 100), Y: float32(r.Float64() * 100), Z: float32(k)})
+		}
+		indices, vertices, err := Delaunay(points, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(vertices) != len(points) {
+			t.Fatalf("expected %d vertices, got %d", len(points), len(vertices))
+		}
+		hull := ConvexHull(points)
+		if n := len(indices) / 3; n != 2*len(points)-len(hull)-2 {
+			t.Errorf("expected %d triangles, got %d", 2*len(points)-len(hull)-2, n)
+		}
+		checkPointTriangulation(t, indices, vertices, contourSignedArea(hull))
+		for j := 0; j < len(indices); j += 3 {
+			a, b, c := vertices[indices[j]], vertices[indices[j+1]], vertices[indices[j+2]]
+			for _, p := range vertices {
+				if d := circumcircleDistance(a, b, c, p); d < -1e-3 {
+					t.Fatalf("vertex %v is %g inside the circumcircle of %v %v %v", p, -d, a, b, c)
+				}
+			}
+		}
+	}
+
+	// 约束折线成为三角形的边
+	var points []Vertex
+	for x := 0; x <= 10; x++ {
+		for y := 0; y <= 10; y++ {
+			points = append(points, Vertex{X: float32(x), Y: float32(y)})
+		}
+	}
+	constraints := []Contour{toContour([]Vector2f{{0.5, 0.2}, {9.5, 9.9}})}
+	indices, vertices, err := Delaunay(points, constraints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPointTriangulation(t, indices, vertices, 100)
+	// 折线不经过格点，因此整条成为一条边
+	if !meshHasEdge(indices, vertices, constraints[0][0], constraints[0][1]) {
+		t.Error("constraint edge is missing")
+	}
+
+	if indices, _, _ := Delaunay([]Vertex{{X: 0}, {X: 1}}, nil); len(indices) != 0 {
+		t.Errorf("two points should give no triangles, got %v", indices)
+	}
+}
+
+// circumcircleDistance 返回点p到逆时针三角形abc外接圆的距离，在圆内时为负
+func circumcircleDistance(a, b, c, p Vertex) float64 {
+	ax, ay := float64(a.X), float64(a.Y)
+	bx, by := float64(b.X)-ax, float64(b.Y)-ay
+	cx, cy := float64(c.X)-ax, float64(c.Y)-ay
+	d := 2 * (bx*cy - by*cx)
+	ux := (cy*(bx*bx+by*by) - by*(cx*cx+cy*cy)) / d
+	uy := (bx*(cx*cx+cy*cy) - cx*(bx*bx+by*by)) / d
+	return math.Hypot(float64(p.X)-ax-ux, float64(p.Y)-ay-uy) - math.Hypot(ux, uy)
+}
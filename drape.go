@@ -0,0 +1,146 @@
+package tesselator
+
+import (
+	"fmt"
+	"math"
+)
+
+// DrapeOptions Drape的选项
+type DrapeOptions struct {
+	// GridOriginX、GridOriginY、CellWidth和CellHeight描述DEM的网格：网格线位于
+	// GridOriginX+i×CellWidth和GridOriginY+j×CellHeight处。CellWidth或CellHeight不大于0时不插入网格线
+	GridOriginX, GridOriginY float64
+	CellWidth, CellHeight    float64
+	// Diagonals 同时插入每个单元从左下角到右上角的对角线，用于由三角形面片构成的DEM
+	Diagonals bool
+	// MaxEdgeLength 大于0时继续细分，使XY平面上的边长不超过该值
+	MaxEdgeLength float64
+	// NormalOffset 顶点沿法线方向移动的距离，用于避免与地形表面的深度冲突
+	NormalOffset float64
+}
+
+// Drape 把轮廓按环绕规则确定的区域贴合到地形上：先在XY平面上进行三角剖分（输入的Z被忽略），
+// 沿DEM的网格线（和对角线）插入顶点，使每个三角形都位于一个网格单元（面片）之内，
+// 再用heightFn计算每个顶点的Z。opts可以为nil，此时只进行剖分和取高度。
+// 三角形在XY平面上为逆时针，NormalOffset不为0时顶点沿面积加权的顶点法线移动
+func Drape(contours []Contour, windingRule WindingRule, heightFn func(x, y float64) float64, opts *DrapeOptions) ([]int, []Vertex, error) {
+	if heightFn == nil {
+		return nil, nil, fmt.Errorf("tesselator: nil height function")
+	}
+	if opts == nil {
+		opts = &DrapeOptions{}
+	}
+	flat := make([]Contour, len(contours))
+	for i, c := range contours {
+		flat[i] = make(Contour, len(c))
+		for k, v := range c {
+			flat[i][k] = Vertex{X: v.X, Y: v.Y}
+		}
+	}
+	var constraints []Contour
+	if opts.CellWidth > 0 && opts.CellHeight > 0 {
+		constraints = demGridLines(flat, opts)
+	}
+
+	t := &tesselator{}
+	if opts.MaxEdgeLength > 0 {
+		t.refiner, t.maxEdgeLength = euclideanRefiner{}, opts.MaxEdgeLength
+	}
+	indices, vertices, err := tesselateWithExtras(t, flat, constraints, windingRule, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	// 自动确定的法线可能朝下，此时三角形在XY平面上为顺时针
+	area := 0.0
+	for i := 0; i+2 < len(indices); i += 3 {
+		a, b, c := vertices[indices[i]], vertices[indices[i+1]], vertices[indices[i+2]]
+		area += float64(b.X-a.X)*float64(c.Y-a.Y) - float64(c.X-a.X)*float64(b.Y-a.Y)
+	}
+	if area < 0 {
+		for i := 0; i+2 < len(indices); i += 3 {
+			indices[i+1], indices[i+2] = indices[i+2], indices[i+1]
+		}
+	}
+	for i, v := range vertices {
+		vertices[i].Z = float32(heightFn(float64(v.X), float64(v.Y)))
+	}
+	if opts.NormalOffset != 0 {
+		for i, n := range vertexNormals(indices, vertices) {
+			vertices[i].X += float32(float64(n.X) * opts.NormalOffset)
+			vertices[i].Y += float32(float64(n.Y) * opts.NormalOffset)
+			vertices[i].Z += float32(float64(n.Z) * opts.NormalOffset)
+		}
+	}
+	return indices, vertices, nil
+}
+
+// demGridLines 返回覆盖轮廓包围盒的DEM网格线（和对角线）。所有折线都经过同样的网格角点，
+// 网格线的交点因此是共同的顶点，不需要在扫描中求交
+func demGridLines(contours []Contour, opts *DrapeOptions) []Contour {
+	minX, minY, maxX, maxY := computeBounds(contours)
+	if minX > maxX {
+		return nil
+	}
+	i0 := int(math.Floor((float64(minX)-opts.GridOriginX)/opts.CellWidth)) - 1
+	i1 := int(math.Ceil((float64(maxX)-opts.GridOriginX)/opts.CellWidth)) + 1
+	j0 := int(math.Floor((float64(minY)-opts.GridOriginY)/opts.CellHeight)) - 1
+	j1 := int(math.Ceil((float64(maxY)-opts.GridOriginY)/opts.CellHeight)) + 1
+	corner := func(i, j int) Vertex {
+		return Vertex{
+			X: float32(opts.GridOriginX + float64(i)*opts.CellWidth),
+			Y: float32(opts.GridOriginY + float64(j)*opts.CellHeight),
+		}
+	}
+
+	var lines []Contour
+	for i := i0; i <= i1; i++ {
+		var line Contour
+		for j := j0; j <= j1; j++ {
+			line = append(line, corner(i, j))
+		}
+		lines = append(lines, line)
+	}
+	for j := j0; j <= j1; j++ {
+		var line Contour
+		for i := i0; i <= i1; i++ {
+			line = append(line, corner(i, j))
+		}
+		lines = append(lines, line)
+	}
+	if opts.Diagonals {
+		// 对角线上的角点满足i-j=k
+		for k := i0 - j1; k <= i1-j0; k++ {
+			var line Contour
+			for i := maxInt(i0, j0+k); i <= i1 && i-k <= j1; i++ {
+				line = append(line, corner(i, i-k))
+			}
+			if len(line) >= 2 {
+				lines = append(lines, line)
+			}
+		}
+	}
+	return lines
+}
+
+// vertexNormals 返回按相邻三角形面积加权的单位顶点法线，没有相邻三角形的顶点为零向量
+func vertexNormals(indices []int, vertices []Vertex) []Vertex {
+	sums := make([][3]float64, len(vertices))
+	for i := 0; i+2 < len(indices); i += 3 {
+		a, b, c := vertices[indices[i]], vertices[indices[i+1]], vertices[indices[i+2]]
+		ux, uy, uz := float64(b.X-a.X), float64(b.Y-a.Y), float64(b.Z-a.Z)
+		vx, vy, vz := float64(c.X-a.X), float64(c.Y-a.Y), float64(c.Z-a.Z)
+		n := [3]float64{uy*vz - uz*vy, uz*vx - ux*vz, ux*vy - uy*vx}
+		for k := 0; k < 3; k++ {
+			for d := range n {
+				sums[indices[i+k]][d] += n[d]
+			}
+		}
+	}
+	normals := make([]Vertex, len(vertices))
+	for i, s := range sums {
+		if l := math.Sqrt(s[0]*s[0] + s[1]*s[1] + s[2]*s[2]); l > 0 {
+			normals[i] = Vertex{X: float32(s[0] / l), Y: float32(s[1] / l), Z: float32(s[2] / l)}
+		}
+	}
+	return normals
+}
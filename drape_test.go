@@ -0,0 +1,97 @@
+package tesselator
+
+import (
+	"math"
+	"testing"
+)
+
+// TestDrape 插入网格线和对角线后每个三角形都位于一个地形面片内，顶点高度取自高度函数
+func TestDrape(t *testing.T) {
+	const ox, oy, cell = 0.5, 0.25, 1.0
+	// 由网格单元的左下-右上对角线分成两个三角形面片的地形
+	corner := func(i, j int) float64 { return float64(((i*7+j*13)%5+5)%5) * 0.7 }
+	heightFn := func(x, y float64) float64 {
+		u, v := (x-ox)/cell, (y-oy)/cell
+		i, j := int(math.Floor(u)), int(math.Floor(v))
+		fu, fv := u-float64(i), v-float64(j)
+		h00, h11 := corner(i, j), corner(i+1, j+1)
+		if fu >= fv {
+			h10 := corner(i+1, j)
+			return h00 + fu*(h10-h00) + fv*(h11-h10)
+		}
+		h01 := corner(i, j+1)
+		return h00 + fu*(h11-h01) + fv*(h01-h00)
+	}
+	star := GenerateStar(6, 0.1, 0.2, 5, 2.5)
+	contours := []Contour{star, reversedContour(squareContour(-1, -1, 1.5))}
+	plainIndices, plainVertices, _ := Tesselate(contours, WindingRuleOdd)
+	area := meshArea(plainIndices, plainVertices)
+
+	// 三角形重心处的插值高度与地形的差
+	facetError := func(indices []int, vertices []Vertex) float64 {
+		worst := 0.0
+		for i := 0; i < len(indices); i += 3 {
+			a, b, c := vertices[indices[i]], vertices[indices[i+1]], vertices[indices[i+2]]
+			x, y := float64(a.X+b.X+c.X)/3, float64(a.Y+b.Y+c.Y)/3
+			worst = math.Max(worst, math.Abs(float64(a.Z+b.Z+c.Z)/3-heightFn(x, y)))
+		}
+		return worst
+	}
+
+	indices, vertices, err := Drape(contours, WindingRuleOdd, heightFn, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if facetError(indices, vertices) < 0.1 {
+		t.Error("expected triangles spanning several facets without grid lines")
+	}
+
+	opts := &DrapeOptions{GridOriginX: ox, GridOriginY: oy, CellWidth: cell, CellHeight: cell, Diagonals: true}
+	indices, vertices, err = Drape(contours, WindingRuleOdd, heightFn, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := facetError(indices, vertices); e > 1e-4 {
+		t.Errorf("draped triangles deviate %g from the terrain", e)
+	}
+	for _, v := range vertices {
+		if math.Abs(float64(v.Z)-heightFn(float64(v.X), float64(v.Y))) > 1e-5 {
+			t.Errorf("vertex %v is not on the terrain", v)
+		}
+	}
+	flat := make([]Vertex, len(vertices))
+	for i, v := range vertices {
+		flat[i] = Vertex{X: v.X, Y: v.Y}
+	}
+	checkPointTriangulation(t, indices, flat, area)
+
+	// 细分后XY平面上的边长不超过限制
+	opts = &DrapeOptions{MaxEdgeLength: 0.8}
+	indices, vertices, err = Drape(contours, WindingRuleOdd, heightFn, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < len(indices); i++ {
+		a, b := vertices[indices[i]], vertices[indices[i-i%3+(i+1)%3]]
+		if l := math.Hypot(float64(a.X-b.X), float64(a.Y-b.Y)); l > 0.8+1e-4 {
+			t.Errorf("edge %v %v has length %g", a, b, l)
+		}
+	}
+
+	// 平坦地形上沿法线抬高，顺时针输入同样向上
+	for _, c := range [][]Contour{contours, {reversedContour(star), squareContour(-1, -1, 1.5)}} {
+		_, vertices, err = Drape(c, WindingRuleOdd, func(x, y float64) float64 { return 5 }, &DrapeOptions{NormalOffset: 0.5})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, v := range vertices {
+			if math.Abs(float64(v.Z)-5.5) > 1e-5 {
+				t.Errorf("expected Z 5.5, got %v", v)
+			}
+		}
+	}
+
+	if _, _, err := Drape(contours, WindingRuleOdd, nil, nil); err == nil {
+		t.Error("expected an error for a nil height function")
+	}
+}
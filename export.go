@@ -0,0 +1,265 @@
+package tesselator
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// MeshAttributes 导出网格时可选的附加数据，各字段为空时不输出
+type MeshAttributes struct {
+	Normals        []Vertex     // 每个顶点的法线，长度与顶点数相同
+	UVs            [][2]float32 // 每个顶点的纹理坐标，长度与顶点数相同
+	FaceAttributes []uint16     // 每个三角形的属性值，长度与三角形数相同
+}
+
+func (a *MeshAttributes) check(indices []int, vertices []Vertex) error {
+	if len(indices)%3 != 0 {
+		return fmt.Errorf("index count %d is not a multiple of 3", len(indices))
+	}
+	for _, i := range indices {
+		if i < 0 || i >= len(vertices) {
+			return fmt.Errorf("index %d out of range [0, %d)", i, len(vertices))
+		}
+	}
+	if a == nil {
+		return nil
+	}
+	if a.Normals != nil && len(a.Normals) != len(vertices) {
+		return fmt.Errorf("expected %d normals, got %d", len(vertices), len(a.Normals))
+	}
+	if a.UVs != nil && len(a.UVs) != len(vertices) {
+		return fmt.Errorf("expected %d UVs, got %d", len(vertices), len(a.UVs))
+	}
+	if a.FaceAttributes != nil && len(a.FaceAttributes) != len(indices)/3 {
+		return fmt.Errorf("expected %d face attributes, got %d", len(indices)/3, len(a.FaceAttributes))
+	}
+	return nil
+}
+
+func (a *MeshAttributes) normals() []Vertex {
+	if a == nil {
+		return nil
+	}
+	return a.Normals
+}
+
+func (a *MeshAttributes) uvs() [][2]float32 {
+	if a == nil {
+		return nil
+	}
+	return a.UVs
+}
+
+func (a *MeshAttributes) faceAttributes() []uint16 {
+	if a == nil {
+		return nil
+	}
+	return a.FaceAttributes
+}
+
+func formatFloat32(v float32) string {
+	return strconv.FormatFloat(float64(v), 'g', -1, 32)
+}
+
+// WriteOBJ 以Wavefront OBJ格式写出三角网格。
+// 有面属性时，属性值变化处以"g attribute_<值>"开始新的分组。
+func WriteOBJ(w io.Writer, indices []int, vertices []Vertex, attrs *MeshAttributes) error {
+	if err := attrs.check(indices, vertices); err != nil {
+		return fmt.Errorf("obj: %v", err)
+	}
+	normals, uvs, faceAttrs := attrs.normals(), attrs.uvs(), attrs.faceAttributes()
+
+	bw := bufio.NewWriter(w)
+	for _, v := range vertices {
+		fmt.Fprintf(bw, "v %s %s %s\n", formatFloat32(v.X), formatFloat32(v.Y), formatFloat32(v.Z))
+	}
+	for _, uv := range uvs {
+		fmt.Fprintf(bw, "vt %s %s\n", formatFloat32(uv[0]), formatFloat32(uv[1]))
+	}
+	for _, n := range normals {
+		fmt.Fprintf(bw, "vn %s %s %s\n", formatFloat32(n.X), formatFloat32(n.Y), formatFloat32(n.Z))
+	}
+
+	// OBJ的索引从1开始
+	ref := func(i int) string {
+		s := strconv.Itoa(i + 1)
+		switch {
+		case uvs != nil && normals != nil:
+			return s + "/" + s + "/" + s
+		case uvs != nil:
+			return s + "/" + s
+		case normals != nil:
+			return s + "//" + s
+		}
+		return s
+	}
+	for t := 0; t < len(indices)/3; t++ {
+		if faceAttrs != nil && (t == 0 || faceAttrs[t] != faceAttrs[t-1]) {
+			fmt.Fprintf(bw, "g attribute_%d\n", faceAttrs[t])
+		}
+		fmt.Fprintf(bw, "f %s %s %s\n", ref(indices[3*t]), ref(indices[3*t+1]), ref(indices[3*t+2]))
+	}
+	return bw.Flush()
+}
+
+func writePLYHeader(bw *bufio.Writer, format string, vertexCount, faceCount int, attrs *MeshAttributes) {
+	fmt.Fprintf(bw, "ply\nformat %s 1.0\ncomment generated by go-tesselator\n", format)
+	fmt.Fprintf(bw, "element vertex %d\nproperty float x\nproperty float y\nproperty float z\n", vertexCount)
+	if attrs.normals() != nil {
+		fmt.Fprint(bw, "property float nx\nproperty float ny\nproperty float nz\n")
+	}
+	if attrs.uvs() != nil {
+		fmt.Fprint(bw, "property float s\nproperty float t\n")
+	}
+	fmt.Fprintf(bw, "element face %d\nproperty list uchar int vertex_indices\n", faceCount)
+	if attrs.faceAttributes() != nil {
+		fmt.Fprint(bw, "property ushort attribute\n")
+	}
+	fmt.Fprint(bw, "end_header\n")
+}
+
+// WritePLY 以ASCII PLY格式写出三角网格，面属性写为face元素的attribute属性
+func WritePLY(w io.Writer, indices []int, vertices []Vertex, attrs *MeshAttributes) error {
+	if err := attrs.check(indices, vertices); err != nil {
+		return fmt.Errorf("ply: %v", err)
+	}
+	normals, uvs, faceAttrs := attrs.normals(), attrs.uvs(), attrs.faceAttributes()
+
+	bw := bufio.NewWriter(w)
+	writePLYHeader(bw, "ascii", len(vertices), len(indices)/3, attrs)
+	for i, v := range vertices {
+		fmt.Fprintf(bw, "%s %s %s", formatFloat32(v.X), formatFloat32(v.Y), formatFloat32(v.Z))
+		if normals != nil {
+			n := normals[i]
+			fmt.Fprintf(bw, " %s %s %s", formatFloat32(n.X), formatFloat32(n.Y), formatFloat32(n.Z))
+		}
+		if uvs != nil {
+			fmt.Fprintf(bw, " %s %s", formatFloat32(uvs[i][0]), formatFloat32(uvs[i][1]))
+		}
+		bw.WriteByte('\n')
+	}
+	for t := 0; t < len(indices)/3; t++ {
+		fmt.Fprintf(bw, "3 %d %d %d", indices[3*t], indices[3*t+1], indices[3*t+2])
+		if faceAttrs != nil {
+			fmt.Fprintf(bw, " %d", faceAttrs[t])
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// WritePLYBinary 以小端二进制PLY格式写出三角网格
+func WritePLYBinary(w io.Writer, indices []int, vertices []Vertex, attrs *MeshAttributes) error {
+	if err := attrs.check(indices, vertices); err != nil {
+		return fmt.Errorf("ply: %v", err)
+	}
+	normals, uvs, faceAttrs := attrs.normals(), attrs.uvs(), attrs.faceAttributes()
+
+	bw := bufio.NewWriter(w)
+	writePLYHeader(bw, "binary_little_endian", len(vertices), len(indices)/3, attrs)
+	var buf [4]byte
+	putFloat := func(v float32) {
+		binary.LittleEndian.PutUint32(buf[:], math.Float32bits(v))
+		bw.Write(buf[:])
+	}
+	for i, v := range vertices {
+		putFloat(v.X)
+		putFloat(v.Y)
+		putFloat(v.Z)
+		if normals != nil {
+			putFloat(normals[i].X)
+			putFloat(normals[i].Y)
+			putFloat(normals[i].Z)
+		}
+		if uvs != nil {
+			putFloat(uvs[i][0])
+			putFloat(uvs[i][1])
+		}
+	}
+	for t := 0; t < len(indices)/3; t++ {
+		bw.WriteByte(3)
+		for k := 0; k < 3; k++ {
+			binary.LittleEndian.PutUint32(buf[:], uint32(indices[3*t+k]))
+			bw.Write(buf[:])
+		}
+		if faceAttrs != nil {
+			binary.LittleEndian.PutUint16(buf[:2], faceAttrs[t])
+			bw.Write(buf[:2])
+		}
+	}
+	return bw.Flush()
+}
+
+// triangleNormal 按顶点顺序（右手法则）计算三角形的单位法线，退化三角形返回零向量
+func triangleNormal(a, b, c Vertex) Vertex {
+	ux, uy, uz := float64(b.X-a.X), float64(b.Y-a.Y), float64(b.Z-a.Z)
+	vx, vy, vz := float64(c.X-a.X), float64(c.Y-a.Y), float64(c.Z-a.Z)
+	nx, ny, nz := uy*vz-uz*vy, uz*vx-ux*vz, ux*vy-uy*vx
+	l := math.Sqrt(nx*nx + ny*ny + nz*nz)
+	if l == 0 {
+		return Vertex{}
+	}
+	// 加0消除-0，避免文本输出"-0"
+	return Vertex{X: float32(nx/l + 0), Y: float32(ny/l + 0), Z: float32(nz/l + 0)}
+}
+
+// WriteSTL 以ASCII STL格式写出三角网格。STL只有面法线，由三角形顶点顺序计算；
+// attrs中的顶点法线、纹理坐标和面属性在ASCII格式中没有位置，不会写出。
+func WriteSTL(w io.Writer, indices []int, vertices []Vertex, attrs *MeshAttributes) error {
+	if err := attrs.check(indices, vertices); err != nil {
+		return fmt.Errorf("stl: %v", err)
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprint(bw, "solid tesselator\n")
+	for t := 0; t < len(indices); t += 3 {
+		a, b, c := vertices[indices[t]], vertices[indices[t+1]], vertices[indices[t+2]]
+		n := triangleNormal(a, b, c)
+		fmt.Fprintf(bw, "  facet normal %s %s %s\n    outer loop\n",
+			formatFloat32(n.X), formatFloat32(n.Y), formatFloat32(n.Z))
+		for _, v := range [3]Vertex{a, b, c} {
+			fmt.Fprintf(bw, "      vertex %s %s %s\n", formatFloat32(v.X), formatFloat32(v.Y), formatFloat32(v.Z))
+		}
+		fmt.Fprint(bw, "    endloop\n  endfacet\n")
+	}
+	fmt.Fprint(bw, "endsolid tesselator\n")
+	return bw.Flush()
+}
+
+// WriteSTLBinary 以二进制STL格式写出三角网格，面属性写入每个三角形的16位属性字段，
+// 顶点法线和纹理坐标不会写出
+func WriteSTLBinary(w io.Writer, indices []int, vertices []Vertex, attrs *MeshAttributes) error {
+	if err := attrs.check(indices, vertices); err != nil {
+		return fmt.Errorf("stl: %v", err)
+	}
+	faceAttrs := attrs.faceAttributes()
+
+	bw := bufio.NewWriter(w)
+	// 80字节的文件头不能以"solid"开头，否则部分软件会误认为ASCII格式
+	var header [80]byte
+	copy(header[:], "binary STL generated by go-tesselator")
+	bw.Write(header[:])
+
+	var buf [50]byte
+	binary.LittleEndian.PutUint32(buf[:4], uint32(len(indices)/3))
+	bw.Write(buf[:4])
+	for t := 0; t < len(indices)/3; t++ {
+		a, b, c := vertices[indices[3*t]], vertices[indices[3*t+1]], vertices[indices[3*t+2]]
+		n := triangleNormal(a, b, c)
+		for k, v := range [4]Vertex{n, a, b, c} {
+			binary.LittleEndian.PutUint32(buf[12*k:], math.Float32bits(v.X))
+			binary.LittleEndian.PutUint32(buf[12*k+4:], math.Float32bits(v.Y))
+			binary.LittleEndian.PutUint32(buf[12*k+8:], math.Float32bits(v.Z))
+		}
+		var attr uint16
+		if faceAttrs != nil {
+			attr = faceAttrs[t]
+		}
+		binary.LittleEndian.PutUint16(buf[48:], attr)
+		bw.Write(buf[:])
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,155 @@
+package tesselator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+)
+
+// 测试用的单位正方形网格：两个逆时针三角形
+func exportTestMesh(t *testing.T) ([]int, []Vertex) {
+	t.Helper()
+	indices, vertices, err := Tesselate([]Contour{{{X: 0, Y: 0, Z: 1}, {X: 1, Y: 0, Z: 1}, {X: 1, Y: 1, Z: 1}, {X: 0, Y: 1, Z: 1}}}, WindingRuleOdd)
+	if err != nil || len(indices) != 6 {
+		t.Fatalf("unexpected tessellation: %v, %v", indices, err)
+	}
+	return indices, vertices
+}
+
+// TestWriteOBJ 测试OBJ输出
+func TestWriteOBJ(t *testing.T) {
+	indices, vertices := exportTestMesh(t)
+
+	var buf bytes.Buffer
+	if err := WriteOBJ(&buf, indices, vertices, nil); err != nil {
+		t.Fatalf("WriteOBJ returned an error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 6 || !strings.HasPrefix(lines[0], "v ") || !strings.HasSuffix(lines[0], " 1") {
+		t.Fatalf("unexpected OBJ output:\n%s", buf.String())
+	}
+	if !strings.HasPrefix(lines[4], "f ") || strings.Count(lines[4], " ") != 3 {
+		t.Errorf("expected a face line, got %q", lines[4])
+	}
+
+	normals := make([]Vertex, len(vertices))
+	uvs := make([][2]float32, len(vertices))
+	for i, v := range vertices {
+		normals[i] = Vertex{Z: 1}
+		uvs[i] = [2]float32{v.X, v.Y}
+	}
+	buf.Reset()
+	attrs := &MeshAttributes{Normals: normals, UVs: uvs, FaceAttributes: []uint16{3, 5}}
+	if err := WriteOBJ(&buf, indices, vertices, attrs); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"vt ", "vn 0 0 1", "g attribute_3\n", "g attribute_5\n",
+		"f " + objRef(indices[0]) + " " + objRef(indices[1]) + " " + objRef(indices[2]) + "\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("OBJ output is missing %q:\n%s", want, out)
+		}
+	}
+
+	if err := WriteOBJ(&buf, []int{0, 1, 9}, vertices, nil); err == nil {
+		t.Error("expected an error for an out of range index")
+	}
+	if err := WriteOBJ(&buf, indices, vertices, &MeshAttributes{UVs: uvs[:1]}); err == nil {
+		t.Error("expected an error for a short UV list")
+	}
+}
+
+func objRef(i int) string {
+	s := string(rune('1' + i))
+	return s + "/" + s + "/" + s
+}
+
+// TestWritePLY 测试ASCII和二进制PLY输出
+func TestWritePLY(t *testing.T) {
+	indices, vertices := exportTestMesh(t)
+	attrs := &MeshAttributes{FaceAttributes: []uint16{7, 8}}
+
+	var buf bytes.Buffer
+	if err := WritePLY(&buf, indices, vertices, attrs); err != nil {
+		t.Fatalf("WritePLY returned an error: %v", err)
+	}
+	header, body, ok := strings.Cut(buf.String(), "end_header\n")
+	if !ok || !strings.HasPrefix(header, "ply\nformat ascii 1.0\n") {
+		t.Fatalf("unexpected PLY header:\n%s", header)
+	}
+	for _, want := range []string{"element vertex 4\n", "element face 2\n", "property ushort attribute\n"} {
+		if !strings.Contains(header, want) {
+			t.Errorf("PLY header is missing %q", want)
+		}
+	}
+	lines := strings.Split(strings.TrimSpace(body), "\n")
+	if len(lines) != 6 || !strings.HasPrefix(lines[4], "3 ") || !strings.HasSuffix(lines[5], " 8") {
+		t.Errorf("unexpected PLY body:\n%s", body)
+	}
+
+	buf.Reset()
+	attrs.Normals = make([]Vertex, len(vertices))
+	if err := WritePLYBinary(&buf, indices, vertices, attrs); err != nil {
+		t.Fatalf("WritePLYBinary returned an error: %v", err)
+	}
+	header, body, _ = strings.Cut(buf.String(), "end_header\n")
+	if !strings.Contains(header, "format binary_little_endian 1.0\n") || !strings.Contains(header, "property float nx\n") {
+		t.Fatalf("unexpected binary PLY header:\n%s", header)
+	}
+	data := []byte(body)
+	// 每个顶点6个float，每个面1+12+2字节
+	if len(data) != 4*6*4+2*15 {
+		t.Fatalf("unexpected binary PLY body size %d", len(data))
+	}
+	for i, v := range vertices {
+		x := math.Float32frombits(binary.LittleEndian.Uint32(data[i*24:]))
+		z := math.Float32frombits(binary.LittleEndian.Uint32(data[i*24+8:]))
+		if x != v.X || z != v.Z {
+			t.Errorf("vertex %d: expected %v, got x=%v z=%v", i, v, x, z)
+		}
+	}
+	face := data[4*24+15:]
+	if face[0] != 3 || int(binary.LittleEndian.Uint32(face[1:])) != indices[3] || binary.LittleEndian.Uint16(face[13:]) != 8 {
+		t.Errorf("unexpected second face record %v", face)
+	}
+}
+
+// TestWriteSTL 测试ASCII和二进制STL输出
+func TestWriteSTL(t *testing.T) {
+	indices, vertices := exportTestMesh(t)
+
+	var buf bytes.Buffer
+	if err := WriteSTL(&buf, indices, vertices, nil); err != nil {
+		t.Fatalf("WriteSTL returned an error: %v", err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "solid ") || !strings.HasSuffix(out, "endsolid tesselator\n") {
+		t.Errorf("unexpected STL framing:\n%s", out)
+	}
+	if n := strings.Count(out, "facet normal 0 0 1\n"); n != 2 {
+		t.Errorf("expected 2 upward facets, got %d:\n%s", n, out)
+	}
+	if n := strings.Count(out, "vertex "); n != 6 {
+		t.Errorf("expected 6 vertex lines, got %d", n)
+	}
+
+	buf.Reset()
+	if err := WriteSTLBinary(&buf, indices, vertices, &MeshAttributes{FaceAttributes: []uint16{1, 2}}); err != nil {
+		t.Fatalf("WriteSTLBinary returned an error: %v", err)
+	}
+	data := buf.Bytes()
+	if len(data) != 84+2*50 || bytes.HasPrefix(data, []byte("solid")) {
+		t.Fatalf("unexpected binary STL size %d", len(data))
+	}
+	if n := binary.LittleEndian.Uint32(data[80:]); n != 2 {
+		t.Errorf("expected 2 triangles, got %d", n)
+	}
+	tri := data[84+50:]
+	nz := math.Float32frombits(binary.LittleEndian.Uint32(tri[8:]))
+	vx := math.Float32frombits(binary.LittleEndian.Uint32(tri[12:]))
+	if nz != 1 || vx != vertices[indices[3]].X || binary.LittleEndian.Uint16(tri[48:]) != 2 {
+		t.Errorf("unexpected second triangle record %v", tri)
+	}
+}
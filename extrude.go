@@ -0,0 +1,161 @@
+package tesselator
+
+// Extrude 把轮廓按环绕规则确定的区域拉伸为底面在bottomZ、顶面在topZ的封闭棱柱。
+// 顶面和底面由三角剖分得到，侧面沿着扫描得到的内外分界边生成（包括洞的内壁），
+// 因此自相交的轮廓也能得到正确的侧面。返回的网格是水密的：顶点在各个面之间共享，
+// 三角形从外部看为逆时针（法线朝外）。topZ小于bottomZ时两者互换。
+func Extrude(contours []Contour, windingRule WindingRule, bottomZ, topZ float32) ([]int, []Vertex, error) {
+	if topZ < bottomZ {
+		bottomZ, topZ = topZ, bottomZ
+	}
+	return extrude(contours, windingRule, bottomZ, func(v Vertex) float32 { return topZ })
+}
+
+// ExtrudeVertexHeights 与Extrude相同，但顶面高度取自每个顶点的Z坐标，
+// 用于屋顶高度逐点变化的情况。扫描产生的交点的高度由相交边插值得到。
+// 顶点的Z应不小于bottomZ。
+func ExtrudeVertexHeights(contours []Contour, windingRule WindingRule, bottomZ float32) ([]int, []Vertex, error) {
+	return extrude(contours, windingRule, bottomZ, func(v Vertex) float32 { return v.Z })
+}
+
+func extrude(contours []Contour, windingRule WindingRule, bottomZ float32, top func(Vertex) float32) ([]int, []Vertex, error) {
+	capIndices, capVertices, boundary, err := tesselateWithBoundary(contours, windingRule)
+	if err != nil {
+		return nil, nil, err
+	}
+	capIndices = removeMirroredTriangles(capIndices)
+	corners, copies := splitPinchVertices(capIndices)
+
+	// 顶点[0,m)为底面，[m,2m)为顶面
+	m := len(copies)
+	vertices := make([]Vertex, 2*m)
+	for i, c := range copies {
+		v := capVertices[c]
+		vertices[i] = Vertex{X: v.X, Y: v.Y, Z: bottomZ}
+		vertices[m+i] = Vertex{X: v.X, Y: v.Y, Z: top(v)}
+	}
+
+	indices := make([]int, 0, 2*len(corners)+6*len(boundary))
+	// 顶面保持逆时针，底面反向使法线朝下
+	for i := 0; i < len(corners); i += 3 {
+		indices = append(indices, m+corners[i], m+corners[i+1], m+corners[i+2])
+	}
+	for i := 0; i < len(corners); i += 3 {
+		indices = append(indices, corners[i], corners[i+2], corners[i+1])
+	}
+
+	// 分界边a->b的左侧为内部，侧面朝右。侧面使用拥有该有向边的三角形的顶点副本
+	owner := make(map[[2]int]int, len(capIndices))
+	for i := 0; i < len(capIndices); i += 3 {
+		for k := 0; k < 3; k++ {
+			owner[[2]int{capIndices[i+k], capIndices[i+(k+1)%3]}] = i + k
+		}
+	}
+	for _, e := range boundary {
+		c, ok := owner[e]
+		if !ok {
+			continue
+		}
+		a, b := corners[c], corners[c-c%3+(c%3+1)%3]
+		indices = append(indices, a, b, m+b, a, m+b, m+a)
+	}
+	return indices, vertices, nil
+}
+
+// removeMirroredTriangles 删除顶点相同而方向相反的成对三角形。
+// 轮廓中重合的共线边会在剖分中产生这样面积为0的两个面，它们自成一个封闭的
+// 扁平区域，会让公共边被多于两个三角形使用。
+func removeMirroredTriangles(indices []int) []int {
+	key := func(a, b, c int) [3]int {
+		// 旋转到最小的顶点在前，保留方向
+		switch {
+		case b < a && b < c:
+			a, b, c = b, c, a
+		case c < a && c < b:
+			a, b, c = c, a, b
+		}
+		return [3]int{a, b, c}
+	}
+	seen := map[[3]int]int{}
+	removed := map[int]bool{}
+	for i := 0; i < len(indices); i += 3 {
+		a, b, c := indices[i], indices[i+1], indices[i+2]
+		if j, ok := seen[key(a, c, b)]; ok {
+			delete(seen, key(a, c, b))
+			removed[i], removed[j] = true, true
+			continue
+		}
+		seen[key(a, b, c)] = i
+	}
+	if len(removed) == 0 {
+		return indices
+	}
+	result := make([]int, 0, len(indices)-3*len(removed))
+	for i := 0; i < len(indices); i += 3 {
+		if !removed[i] {
+			result = append(result, indices[i:i+3]...)
+		}
+	}
+	return result
+}
+
+// splitPinchVertices 把多个区域只在一个顶点处相接（如自相交轮廓的交点）的顶点
+// 按其周围相连的三角形扇拆分为多个副本，使拉伸后的侧面不共用竖直边，网格保持流形。
+// 返回每个三角形角对应的副本编号，以及每个副本对应的原顶点。
+func splitPinchVertices(indices []int) ([]int, []int) {
+	parent := make([]int, len(indices))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+	union := func(a, b int) {
+		parent[find(a)] = find(b)
+	}
+
+	// 相邻三角形在公共边两个端点处的角属于同一个扇
+	edges := make(map[[2]int]int, len(indices))
+	for i := 0; i < len(indices); i += 3 {
+		for k := 0; k < 3; k++ {
+			a, b := i+k, i+(k+1)%3
+			va, vb := indices[a], indices[b]
+			if o, ok := edges[[2]int{vb, va}]; ok {
+				oa, ob := o, o-o%3+(o%3+1)%3 // 对方的边为vb->va
+				union(a, ob)
+				union(b, oa)
+			}
+			edges[[2]int{va, vb}] = a
+		}
+	}
+
+	corners := make([]int, len(indices))
+	copyOf := map[int]int{}
+	var copies []int
+	for i, v := range indices {
+		root := find(i)
+		c, ok := copyOf[root]
+		if !ok {
+			c = len(copies)
+			copyOf[root] = c
+			copies = append(copies, v)
+		}
+		corners[i] = c
+	}
+	return corners, copies
+}
+
+// FaceNormals 按三角形顶点顺序（右手法则）计算每个三角形的单位法线，
+// 结果的长度为三角形个数。退化三角形的法线为零向量。
+func FaceNormals(indices []int, vertices []Vertex) []Vertex {
+	normals := make([]Vertex, len(indices)/3)
+	for t := range normals {
+		normals[t] = triangleNormal(vertices[indices[3*t]], vertices[indices[3*t+1]], vertices[indices[3*t+2]])
+	}
+	return normals
+}
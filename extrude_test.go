@@ -0,0 +1,139 @@
+package tesselator
+
+import (
+	"math"
+	"testing"
+)
+
+// meshVolume 用散度定理计算封闭网格的有向体积，法线朝外时为正
+func meshVolume(indices []int, vertices []Vertex) float64 {
+	volume := 0.0
+	for i := 0; i+2 < len(indices); i += 3 {
+		a, b, c := vertices[indices[i]], vertices[indices[i+1]], vertices[indices[i+2]]
+		volume += (float64(a.X)*(float64(b.Y)*float64(c.Z)-float64(b.Z)*float64(c.Y)) -
+			float64(a.Y)*(float64(b.X)*float64(c.Z)-float64(b.Z)*float64(c.X)) +
+			float64(a.Z)*(float64(b.X)*float64(c.Y)-float64(b.Y)*float64(c.X))) / 6
+	}
+	return volume
+}
+
+// checkWatertight 检查每条有向边恰好出现一次，且其反向边也恰好出现一次
+func checkWatertight(t *testing.T, indices []int) {
+	t.Helper()
+	edges := map[[2]int]int{}
+	for i := 0; i+2 < len(indices); i += 3 {
+		for k := 0; k < 3; k++ {
+			edges[[2]int{indices[i+k], indices[i+(k+1)%3]}]++
+		}
+	}
+	for e, n := range edges {
+		if n != 1 {
+			t.Fatalf("directed edge %v used %d times", e, n)
+		}
+		if edges[[2]int{e[1], e[0]}] != 1 {
+			t.Fatalf("edge %v has no opposite edge", e)
+		}
+	}
+}
+
+// TestExtrude 测试带洞多边形和自相交多边形的拉伸
+func TestExtrude(t *testing.T) {
+	cases := []struct {
+		name     string
+		contours []Contour
+		rule     WindingRule
+		area     float64
+	}{
+		{"square", []Contour{toContour([]Vector2f{{0, 0}, {10, 0}, {10, 10}, {0, 10}})}, WindingRuleOdd, 100},
+		{"hole", addPolygonWithHole(), WindingRuleOdd, 0},
+		// 自相交的蝴蝶结，交点处产生新顶点
+		{"bowtie", []Contour{toContour([]Vector2f{{0, 0}, {4, 4}, {4, 0}, {0, 4}})}, WindingRuleNonzero, 8},
+		// 重叠的两个方块，非零规则取并集
+		{"overlap", []Contour{
+			toContour([]Vector2f{{0, 0}, {4, 0}, {4, 4}, {0, 4}}),
+			toContour([]Vector2f{{2, 2}, {6, 2}, {6, 6}, {2, 6}}),
+		}, WindingRuleNonzero, 28},
+		// 顺时针输入时Positive规则与Tesselate一样按总体朝向解释
+		{"clockwise", []Contour{toContour([]Vector2f{{0, 0}, {0, 5}, {5, 5}, {5, 0}})}, WindingRulePositive, 25},
+		// 重合的共线边会产生成对的零面积三角形
+		{"collinear", []Contour{
+			{{X: 8, Y: 1}, {X: 0, Y: 4}, {X: 8, Y: 7}, {X: 5, Y: 9}, {X: 4, Y: 2}, {X: 3, Y: 6}},
+			{{X: 7, Y: 7}, {X: 5, Y: 7}, {X: 0, Y: 5}, {X: 6, Y: 7}},
+		}, WindingRuleOdd, 0},
+	}
+	for _, c := range cases {
+		indices, vertices, err := Extrude(c.contours, c.rule, 1, 4)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		checkWatertight(t, indices)
+
+		area := c.area
+		if area == 0 {
+			capIndices, capVertices, _ := Tesselate(c.contours, c.rule)
+			area = meshArea(capIndices, capVertices)
+		}
+		if v := meshVolume(indices, vertices); math.Abs(v-3*area) > 1e-3*area {
+			t.Errorf("%s: expected volume %.3f, got %.3f", c.name, 3*area, v)
+		}
+
+		// 法线：顶面朝上、底面朝下、侧面水平
+		normals := FaceNormals(indices, vertices)
+		up, down := 0.0, 0.0
+		for i, n := range normals {
+			if n == (Vertex{}) {
+				// 共线顶点处可能产生面积为0的三角形
+				continue
+			}
+			a, b, cc := vertices[indices[3*i]], vertices[indices[3*i+1]], vertices[indices[3*i+2]]
+			switch {
+			case a.Z == 4 && b.Z == 4 && cc.Z == 4:
+				if n.Z != 1 {
+					t.Fatalf("%s: top face normal %v", c.name, n)
+				}
+				up++
+			case a.Z == 1 && b.Z == 1 && cc.Z == 1:
+				if n.Z != -1 {
+					t.Fatalf("%s: bottom face normal %v", c.name, n)
+				}
+				down++
+			default:
+				if math.Abs(float64(n.Z)) > 1e-6 {
+					t.Fatalf("%s: wall normal %v is not horizontal", c.name, n)
+				}
+			}
+		}
+		if up == 0 || up != down {
+			t.Errorf("%s: expected matching caps, got %v top and %v bottom triangles", c.name, up, down)
+		}
+	}
+
+	// 顶面和底面高度颠倒时自动交换
+	indices, vertices, _ := Extrude([]Contour{toContour([]Vector2f{{0, 0}, {1, 0}, {1, 1}})}, WindingRuleOdd, 2, 0)
+	if v := meshVolume(indices, vertices); math.Abs(v-1) > 1e-6 {
+		t.Errorf("swapped heights: expected volume 1, got %.3f", v)
+	}
+	if indices, vertices, err := Extrude(nil, WindingRuleOdd, 0, 1); err != nil || len(indices) != 0 || len(vertices) != 0 {
+		t.Errorf("empty input should produce an empty mesh")
+	}
+}
+
+// TestExtrudeVertexHeights 测试逐顶点高度的拉伸
+func TestExtrudeVertexHeights(t *testing.T) {
+	// 高度沿x线性变化的斜屋顶：z = 2 + x
+	contour := Contour{{X: 0, Y: 0, Z: 2}, {X: 4, Y: 0, Z: 6}, {X: 4, Y: 3, Z: 6}, {X: 0, Y: 3, Z: 2}}
+	indices, vertices, err := ExtrudeVertexHeights([]Contour{contour}, WindingRuleOdd, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkWatertight(t, indices)
+	// 体积 = 底面积 × 平均高度 = 12 × 4
+	if v := meshVolume(indices, vertices); math.Abs(v-48) > 1e-3 {
+		t.Errorf("expected volume 48, got %.3f", v)
+	}
+	for _, v := range vertices {
+		if v.Z != 0 && math.Abs(float64(v.Z-(2+v.X))) > 1e-5 {
+			t.Errorf("top vertex %v does not follow its input height", v)
+		}
+	}
+}
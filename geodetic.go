@@ -0,0 +1,239 @@
+package tesselator
+
+import (
+	"fmt"
+	"math"
+)
+
+// WGS84椭球的长半轴（米）和扁率
+const (
+	wgs84SemiMajorAxis = 6378137.0
+	wgs84Flattening    = 1 / 298.257223563
+	wgs84SemiMinorAxis = wgs84SemiMajorAxis * (1 - wgs84Flattening)
+	wgs84Eccentricity2 = wgs84Flattening * (2 - wgs84Flattening)
+)
+
+// DefaultGeodeticGranularity TesselateGeodetic默认的最大角度（弧度），即1度
+const DefaultGeodeticGranularity = math.Pi / 180
+
+// GeodeticToECEF 把WGS84经度、纬度（度）和椭球高（米）转换为地心地固坐标（米）
+func GeodeticToECEF(lon, lat, height float64) [3]float64 {
+	sinLon, cosLon := math.Sincos(lon * math.Pi / 180)
+	sinLat, cosLat := math.Sincos(lat * math.Pi / 180)
+	n := wgs84SemiMajorAxis / math.Sqrt(1-wgs84Eccentricity2*sinLat*sinLat)
+	return [3]float64{
+		(n + height) * cosLat * cosLon,
+		(n + height) * cosLat * sinLon,
+		(n*(1-wgs84Eccentricity2) + height) * sinLat,
+	}
+}
+
+// ECEFToGeodetic 把地心地固坐标（米）转换为WGS84经度、纬度（度）和椭球高（米）
+func ECEFToGeodetic(p [3]float64) (lon, lat, height float64) {
+	r := math.Hypot(p[0], p[1])
+	phi := math.Atan2(p[2], r*(1-wgs84Eccentricity2))
+	for i := 0; i < 10; i++ {
+		sin := math.Sin(phi)
+		n := wgs84SemiMajorAxis / math.Sqrt(1-wgs84Eccentricity2*sin*sin)
+		next := math.Atan2(p[2]+wgs84Eccentricity2*n*sin, r)
+		if math.Abs(next-phi) < 1e-15 {
+			phi = next
+			break
+		}
+		phi = next
+	}
+	sin, cos := math.Sincos(phi)
+	n := wgs84SemiMajorAxis / math.Sqrt(1-wgs84Eccentricity2*sin*sin)
+	if math.Abs(cos) > 1e-3 {
+		height = r/cos - n
+	} else {
+		// 两极附近用z计算高度
+		height = p[2]/sin - n*(1-wgs84Eccentricity2)
+	}
+	return math.Atan2(p[1], p[0]) * 180 / math.Pi, phi * 180 / math.Pi, height
+}
+
+func dot3(a, b [3]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+// tangentPlane 椭球面上一点的切平面，坐标轴为东、北、天方向
+type tangentPlane struct {
+	origin          [3]float64
+	east, north, up [3]float64
+}
+
+func newTangentPlane(lon, lat float64) *tangentPlane {
+	sinLon, cosLon := math.Sincos(lon * math.Pi / 180)
+	sinLat, cosLat := math.Sincos(lat * math.Pi / 180)
+	return &tangentPlane{
+		origin: GeodeticToECEF(lon, lat, 0),
+		east:   [3]float64{-sinLon, cosLon, 0},
+		north:  [3]float64{-sinLat * cosLon, -sinLat * sinLon, cosLat},
+		up:     [3]float64{cosLat * cosLon, cosLat * sinLon, sinLat},
+	}
+}
+
+// project 返回点在切平面上的正射投影坐标，取整到毫米，
+// 使±180度经线上或极点处只因三角函数舍入而不同的点重合
+func (t *tangentPlane) project(p [3]float64) (float64, float64) {
+	d := [3]float64{p[0] - t.origin[0], p[1] - t.origin[1], p[2] - t.origin[2]}
+	return math.Round(dot3(d, t.east)*1000) / 1000, math.Round(dot3(d, t.north)*1000) / 1000
+}
+
+// unproject 返回椭球面上正射投影到切平面(x, y)处、位于切点一侧的点。
+// 超出椭球轮廓的位置取轮廓上最近的点
+func (t *tangentPlane) unproject(x, y float64) [3]float64 {
+	var q [3]float64
+	for i := range q {
+		q[i] = t.origin[i] + x*t.east[i] + y*t.north[i]
+	}
+	// 求解 |q + s·up| 位于椭球面上的较大的s
+	a2, b2 := wgs84SemiMajorAxis*wgs84SemiMajorAxis, wgs84SemiMinorAxis*wgs84SemiMinorAxis
+	qa := (t.up[0]*t.up[0]+t.up[1]*t.up[1])/a2 + t.up[2]*t.up[2]/b2
+	qb := 2 * ((q[0]*t.up[0]+q[1]*t.up[1])/a2 + q[2]*t.up[2]/b2)
+	qc := (q[0]*q[0]+q[1]*q[1])/a2 + q[2]*q[2]/b2 - 1
+	s := (-qb + math.Sqrt(math.Max(0, qb*qb-4*qa*qc))) / (2 * qa)
+	return [3]float64{q[0] + s*t.up[0], q[1] + s*t.up[1], q[2] + s*t.up[2]}
+}
+
+// geodeticRefiner 按地心角细分网格，新顶点沿弦的中点投影回椭球面，高度取两端的平均值。
+// 网格顶点的坐标为切平面上的(东, 北, 高度)，idx是points中的序号
+type geodeticRefiner struct {
+	plane  *tangentPlane
+	points [][3]float64 // 经度、纬度（度）和高度
+	ecef   [][3]float64 // 高度为0时的地心地固坐标
+}
+
+// add 记录一个点并返回其序号
+func (r *geodeticRefiner) add(lon, lat, height float64) index {
+	r.points = append(r.points, [3]float64{lon, lat, height})
+	r.ecef = append(r.ecef, GeodeticToECEF(lon, lat, 0))
+	return index(len(r.points) - 1)
+}
+
+// resolve 返回顶点在points中的序号，扫描中产生的交点按切平面坐标求出其位置
+func (r *geodeticRefiner) resolve(v *vertex) index {
+	if v.idx < 0 || int(v.idx) >= len(r.points) {
+		lon, lat, _ := ECEFToGeodetic(r.plane.unproject(float64(v.coords[0]), float64(v.coords[1])))
+		v.idx = r.add(lon, lat, float64(v.coords[2]))
+	}
+	return v.idx
+}
+
+func (r *geodeticRefiner) length(a, b *vertex) float64 {
+	p, q := r.ecef[r.resolve(a)], r.ecef[r.resolve(b)]
+	cross := [3]float64{p[1]*q[2] - p[2]*q[1], p[2]*q[0] - p[0]*q[2], p[0]*q[1] - p[1]*q[0]}
+	return math.Atan2(math.Sqrt(dot3(cross, cross)), dot3(p, q))
+}
+
+func (r *geodeticRefiner) split(a, b, v *vertex) {
+	i, j := r.resolve(a), r.resolve(b)
+	p, q := r.ecef[i], r.ecef[j]
+	lon, lat, _ := ECEFToGeodetic([3]float64{(p[0] + q[0]) / 2, (p[1] + q[1]) / 2, (p[2] + q[2]) / 2})
+	v.idx = r.add(lon, lat, (r.points[i][2]+r.points[j][2])/2)
+	x, y := r.plane.project(r.ecef[v.idx])
+	v.coords = [3]float{float(x), float(y), float(r.points[v.idx][2])}
+	v.s, v.t = v.coords[0], v.coords[1]
+}
+
+// TesselateGeodetic 对WGS84经纬度多边形进行三角剖分：轮廓顶点的X、Y为经度和纬度（度），Z为椭球高（米），
+// 边为两端点之间的大地线（大椭圆弧）。轮廓在所有顶点中心处的切平面上正射投影后剖分，
+// 三角形的边再不断二分，直到每条边两端的地心角不超过granularity（弧度，不大于0时使用
+// DefaultGeodeticGranularity），新顶点投影回椭球面。返回的顶点为地心地固坐标（米），
+// 三角形从椭球外侧看为逆时针。由于在三维空间中剖分，跨越180度经线的多边形和包含极点的多边形
+// （包括沿180度经线和极点闭合的写法）都能正确处理；多边形不能超出以中心为顶点的半球
+func TesselateGeodetic(contours []Contour, windingRule WindingRule, granularity float64) ([]int, [][3]float64, error) {
+	if granularity <= 0 {
+		granularity = DefaultGeodeticGranularity
+	}
+	var valid []Contour
+	var center [3]float64
+	for _, c := range contours {
+		if len(c) < 3 {
+			continue
+		}
+		for _, v := range c {
+			if !isFinite32(v.X) || !isFinite32(v.Y) || !isFinite32(v.Z) || math.Abs(float64(v.Y)) > 90 {
+				return nil, nil, fmt.Errorf("tesselator: invalid geodetic coordinate %v", v)
+			}
+			p := GeodeticToECEF(float64(v.X), float64(v.Y), 0)
+			for i := range center {
+				center[i] += p[i] / wgs84SemiMajorAxis
+			}
+		}
+		valid = append(valid, c)
+	}
+	if len(valid) == 0 {
+		return []int{}, [][3]float64{}, nil
+	}
+	if math.Sqrt(dot3(center, center)) < 1e-9*float64(len(valid)) {
+		return nil, nil, fmt.Errorf("tesselator: geodetic polygon has no center")
+	}
+	lon, lat, _ := ECEFToGeodetic(center)
+	r := &geodeticRefiner{plane: newTangentPlane(lon, lat)}
+
+	// 投影到切平面，顶点必须位于切点所在的半球内
+	planar := make([]Contour, len(valid))
+	for i, c := range valid {
+		planar[i] = make(Contour, len(c))
+		for k, v := range c {
+			p := GeodeticToECEF(float64(v.X), float64(v.Y), 0)
+			if dot3(p, r.plane.up) <= dot3(r.plane.origin, r.plane.up)-wgs84SemiMajorAxis {
+				return nil, nil, fmt.Errorf("tesselator: geodetic polygon spans more than a hemisphere")
+			}
+			x, y := r.plane.project(p)
+			planar[i][k] = Vertex{X: float32(x), Y: float32(y), Z: v.Z}
+		}
+	}
+	// 与Tesselate自动确定法线时一样，使有向面积之和非负；WindingRuleNesting按嵌套深度确定方向
+	reversed := make([]bool, len(planar))
+	if windingRule == WindingRuleNesting {
+		reversed, windingRule = nestingReversals(planar)
+	} else {
+		area := 0.0
+		for _, c := range planar {
+			area += contourSignedArea(c)
+		}
+		for i := range reversed {
+			reversed[i] = area < 0
+		}
+	}
+	// 按最终的顶点顺序记录经纬度，使网格顶点的序号对应points中的点
+	for i, c := range valid {
+		if reversed[i] {
+			reverseContour(planar[i])
+		}
+		for k := range c {
+			v := c[k]
+			if reversed[i] {
+				v = c[len(c)-1-k]
+			}
+			r.add(float64(v.X), float64(v.Y), float64(v.Z))
+		}
+	}
+
+	// 共线的顶点（例如经过极点的经线）会产生零面积三角形，细分前先翻转为Delaunay三角剖分
+	t := &tesselator{delaunay: true, refiner: r, maxEdgeLength: granularity}
+	addContours(t, planar)
+	if !tessTesselate(t, windingRule, elementTypePolygons, 3, 3, []float{0, 0, 1}) {
+		return nil, nil, fmt.Errorf("libtess2: tessTesselate failed")
+	}
+
+	positions := make([][3]float64, t.vertexCount)
+	for i := range positions {
+		idx := t.vertexIndices[i]
+		if idx < 0 || int(idx) >= len(r.points) {
+			v := &vertex{idx: undef}
+			v.coords = [3]float{t.vertices[i*3], t.vertices[i*3+1], t.vertices[i*3+2]}
+			idx = r.resolve(v)
+		}
+		p := r.points[idx]
+		positions[i] = GeodeticToECEF(p[0], p[1], p[2])
+	}
+	elements := make([]int, len(t.elements))
+	for i, e := range t.elements {
+		elements[i] = int(e)
+	}
+	return elements, positions, nil
+}
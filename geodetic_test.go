@@ -0,0 +1,131 @@
+package tesselator
+
+import (
+	"math"
+	"testing"
+)
+
+// TestGeodeticECEF 经纬度与地心地固坐标互相转换
+func TestGeodeticECEF(t *testing.T) {
+	for _, p := range [][3]float64{{0, 0, 0}, {116.4, 39.9, 50}, {-179.5, -89.9, 1000}, {30, 90, 10}} {
+		lon, lat, height := ECEFToGeodetic(GeodeticToECEF(p[0], p[1], p[2]))
+		if p[1] != 90 && math.Abs(lon-p[0]) > 1e-9 || math.Abs(lat-p[1]) > 1e-9 || math.Abs(height-p[2]) > 1e-6 {
+			t.Errorf("expected %v, got %v %v %v", p, lon, lat, height)
+		}
+	}
+	if p := GeodeticToECEF(90, 0, 0); math.Abs(p[1]-wgs84SemiMajorAxis) > 1e-6 {
+		t.Errorf("expected semi-major axis, got %v", p)
+	}
+}
+
+// TestTesselateGeodetic 顶点位于椭球面上，边不超过指定角度，跨越180度经线和包含极点的多边形面积正确
+func TestTesselateGeodetic(t *testing.T) {
+	granularity := 0.1 * math.Pi / 180
+	square := func(x, y, size float32) []Contour {
+		return []Contour{{{X: x, Y: y}, {X: x + size, Y: y}, {X: x + size, Y: y + size}, {X: x, Y: y + size}}}
+	}
+	indices, positions, err := TesselateGeodetic(square(0, 0, 1), WindingRuleOdd, granularity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 1度×1度的球面矩形面积约为a²·Δλ·Δsinφ
+	expected := wgs84SemiMajorAxis * wgs84SemiMajorAxis * math.Pi / 180 * math.Sin(math.Pi/180)
+	if area := checkGeodeticMesh(t, indices, positions, granularity); math.Abs(area-expected) > expected*0.01 {
+		t.Errorf("expected area %g, got %g", expected, area)
+	}
+	for _, p := range positions {
+		if _, _, height := ECEFToGeodetic(p); math.Abs(height) > 1e-3 {
+			t.Errorf("vertex %v is %g above the ellipsoid", p, height)
+		}
+	}
+
+	// 跨越180度经线的多边形与同样大小的普通多边形面积相同
+	expected = geodeticArea(t, square(-1, 10, 2), granularity)
+	if area := geodeticArea(t, square(179, 10, 2), granularity); math.Abs(area-expected) > expected*1e-4 {
+		t.Errorf("expected area %g across the antimeridian, got %g", expected, area)
+	}
+	wrapped := []Contour{{{X: 179, Y: 10}, {X: -179, Y: 10}, {X: -179, Y: 12}, {X: 179, Y: 12}}}
+	if area := geodeticArea(t, wrapped, granularity); math.Abs(area-expected) > expected*1e-4 {
+		t.Errorf("expected area %g across the antimeridian, got %g", expected, area)
+	}
+
+	// WindingRuleNesting调整轮廓方向后，顶点仍对应原来的经纬度：顺时针的外轮廓和逆时针的洞
+	outer := reversedContour(square(0, 10, 1)[0])
+	hole := square(0.4, 10.4, 0.2)[0]
+	expected = geodeticArea(t, []Contour{outer, hole}, granularity)
+	indices, positions, err = TesselateGeodetic([]Contour{outer, hole}, WindingRuleNesting, granularity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if area := checkGeodeticMesh(t, indices, positions, granularity); math.Abs(area-expected) > expected*1e-4 {
+		t.Errorf("expected area %g with the nesting rule, got %g", expected, area)
+	}
+	// 约为1度×1度减去0.2度×0.2度的球面矩形
+	if approx := 0.96 * wgs84SemiMajorAxis * wgs84SemiMajorAxis * math.Pi / 180 * (math.Sin(11*math.Pi/180) - math.Sin(10*math.Pi/180)); math.Abs(expected-approx) > approx*0.01 {
+		t.Errorf("expected area about %g, got %g", approx, expected)
+	}
+
+	// 包含极点的环，既可以直接围绕极点，也可以沿180度经线和极点闭合
+	granularity = 0.5 * math.Pi / 180
+	var ring, cut Contour
+	for lon := -180; lon < 180; lon += 5 {
+		ring = append(ring, Vertex{X: float32(lon), Y: 80})
+	}
+	for lon := -180; lon <= 180; lon += 5 {
+		cut = append(cut, Vertex{X: float32(lon), Y: 80})
+	}
+	cut = append(cut, Vertex{X: 180, Y: 90}, Vertex{X: -180, Y: 90})
+	expected = geodeticArea(t, []Contour{ring}, granularity)
+	// 极冠的面积约为2πa²(1-sin80°)
+	if cap := 2 * math.Pi * wgs84SemiMajorAxis * wgs84SemiMajorAxis * (1 - math.Sin(80*math.Pi/180)); math.Abs(expected-cap) > cap*0.02 {
+		t.Errorf("expected polar cap area %g, got %g", cap, expected)
+	}
+	if area := geodeticArea(t, []Contour{cut}, granularity); math.Abs(area-expected) > expected*1e-3 {
+		t.Errorf("expected area %g around the pole, got %g", expected, area)
+	}
+	// 顺时针输入与逆时针输入结果相同
+	if area := geodeticArea(t, []Contour{reversedContour(ring)}, granularity); math.Abs(area-expected) > expected*1e-3 {
+		t.Errorf("expected area %g for the reversed ring, got %g", expected, area)
+	}
+
+	// 超出半球的多边形
+	if _, _, err := TesselateGeodetic([]Contour{{{X: 0, Y: 0}, {X: 120, Y: 0}, {X: -120, Y: 0}}}, WindingRuleOdd, granularity); err == nil {
+		t.Error("expected an error for a polygon spanning more than a hemisphere")
+	}
+	if _, _, err := TesselateGeodetic([]Contour{{{X: 0, Y: 0}, {X: 1, Y: 91}, {X: 1, Y: 0}}}, WindingRuleOdd, granularity); err == nil {
+		t.Error("expected an error for an invalid latitude")
+	}
+}
+
+func geodeticArea(t *testing.T, contours []Contour, granularity float64) float64 {
+	t.Helper()
+	indices, positions, err := TesselateGeodetic(contours, WindingRuleOdd, granularity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return checkGeodeticMesh(t, indices, positions, granularity)
+}
+
+// checkGeodeticMesh 检查三角形朝向椭球外侧且边不超过指定角度，返回三角形的面积之和
+func checkGeodeticMesh(t *testing.T, indices []int, positions [][3]float64, granularity float64) float64 {
+	t.Helper()
+	total := 0.0
+	for i := 0; i < len(indices); i += 3 {
+		a, b, c := positions[indices[i]], positions[indices[i+1]], positions[indices[i+2]]
+		u := [3]float64{b[0] - a[0], b[1] - a[1], b[2] - a[2]}
+		v := [3]float64{c[0] - a[0], c[1] - a[1], c[2] - a[2]}
+		n := [3]float64{u[1]*v[2] - u[2]*v[1], u[2]*v[0] - u[0]*v[2], u[0]*v[1] - u[1]*v[0]}
+		if dot3(n, a) < 0 {
+			t.Errorf("triangle %v %v %v faces inward", a, b, c)
+		}
+		total += math.Sqrt(dot3(n, n)) / 2
+		for k, p := range [][3]float64{a, b, c} {
+			q := [][3]float64{b, c, a}[k]
+			angle := math.Acos(math.Min(1, dot3(p, q)/math.Sqrt(dot3(p, p)*dot3(q, q))))
+			if angle > granularity*1.01 {
+				t.Fatalf("edge %v %v spans %g radians", p, q, angle)
+			}
+		}
+	}
+	return total
+}
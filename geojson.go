@@ -0,0 +1,248 @@
+package tesselator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// GeoJSONGeometry GeoJSON几何对象。坐标保持原始JSON，按Type在需要时解析
+type GeoJSONGeometry struct {
+	Type        string             `json:"type"`
+	Coordinates json.RawMessage    `json:"coordinates,omitempty"`
+	Geometries  []*GeoJSONGeometry `json:"geometries,omitempty"`
+}
+
+// GeoJSONFeature GeoJSON要素
+type GeoJSONFeature struct {
+	Type       string                 `json:"type"`
+	ID         interface{}            `json:"id,omitempty"`
+	Geometry   *GeoJSONGeometry       `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// GeoJSONFeatureCollection GeoJSON要素集合
+type GeoJSONFeatureCollection struct {
+	Type     string            `json:"type"`
+	Features []*GeoJSONFeature `json:"features"`
+}
+
+// TesselatedFeature 一个要素的三角剖分结果，保留要素的ID和属性
+type TesselatedFeature struct {
+	ID         interface{}
+	Properties map[string]interface{}
+	Indices    []int
+	Vertices   []Vertex
+}
+
+// DecodeGeoJSON 解析GeoJSON文本，可以是FeatureCollection、Feature或者单独的几何对象。
+// 单独的几何对象被包装为没有ID和属性的要素。
+func DecodeGeoJSON(data []byte) ([]*GeoJSONFeature, error) {
+	var head struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, fmt.Errorf("geojson: %v", err)
+	}
+
+	switch head.Type {
+	case "FeatureCollection":
+		var fc GeoJSONFeatureCollection
+		if err := json.Unmarshal(data, &fc); err != nil {
+			return nil, fmt.Errorf("geojson: %v", err)
+		}
+		return fc.Features, nil
+	case "Feature":
+		var f GeoJSONFeature
+		if err := json.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("geojson: %v", err)
+		}
+		return []*GeoJSONFeature{&f}, nil
+	case "":
+		return nil, fmt.Errorf("geojson: missing type")
+	}
+
+	var g GeoJSONGeometry
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, fmt.Errorf("geojson: %v", err)
+	}
+	return []*GeoJSONFeature{{Type: "Feature", Geometry: &g}}, nil
+}
+
+// Contours 将Polygon、MultiPolygon和GeometryCollection转换为轮廓，
+// 每个环（外环和洞）对应一个轮廓，并去掉环末尾与起点重复的闭合点。
+// Point、LineString等没有面积的几何类型不产生轮廓。
+func (g *GeoJSONGeometry) Contours() ([]Contour, error) {
+	if g == nil {
+		return nil, nil
+	}
+
+	switch g.Type {
+	case "Polygon":
+		var rings [][][]float64
+		if err := json.Unmarshal(g.Coordinates, &rings); err != nil {
+			return nil, fmt.Errorf("geojson: invalid Polygon coordinates: %v", err)
+		}
+		return geoJSONRings(rings)
+	case "MultiPolygon":
+		var polygons [][][][]float64
+		if err := json.Unmarshal(g.Coordinates, &polygons); err != nil {
+			return nil, fmt.Errorf("geojson: invalid MultiPolygon coordinates: %v", err)
+		}
+		var contours []Contour
+		for _, rings := range polygons {
+			cs, err := geoJSONRings(rings)
+			if err != nil {
+				return nil, err
+			}
+			contours = append(contours, cs...)
+		}
+		return contours, nil
+	case "GeometryCollection":
+		var contours []Contour
+		for _, child := range g.Geometries {
+			cs, err := child.Contours()
+			if err != nil {
+				return nil, err
+			}
+			contours = append(contours, cs...)
+		}
+		return contours, nil
+	case "Point", "MultiPoint", "LineString", "MultiLineString":
+		return nil, nil
+	}
+	return nil, fmt.Errorf("geojson: unsupported geometry type %q", g.Type)
+}
+
+func geoJSONRings(rings [][][]float64) ([]Contour, error) {
+	contours := make([]Contour, 0, len(rings))
+	for _, ring := range rings {
+		c := make(Contour, 0, len(ring))
+		for _, pos := range ring {
+			if len(pos) < 2 {
+				return nil, fmt.Errorf("geojson: position needs at least 2 coordinates, got %d", len(pos))
+			}
+			v := Vertex{X: float32(pos[0]), Y: float32(pos[1])}
+			if len(pos) > 2 {
+				v.Z = float32(pos[2])
+			}
+			c = append(c, v)
+		}
+		if n := len(c); n > 1 && c[0] == c[n-1] {
+			c = c[:n-1]
+		}
+		contours = append(contours, c)
+	}
+	return contours, nil
+}
+
+// TesselateFeatures 逐个要素进行三角剖分，结果与输入要素一一对应。
+// 没有面状几何的要素得到空的三角形列表。
+func TesselateFeatures(features []*GeoJSONFeature, windingRule WindingRule) ([]TesselatedFeature, error) {
+	result := make([]TesselatedFeature, 0, len(features))
+	for i, f := range features {
+		contours, err := f.Geometry.Contours()
+		if err != nil {
+			return nil, fmt.Errorf("feature %d: %v", i, err)
+		}
+		indices, vertices, err := Tesselate(contours, windingRule)
+		if err != nil {
+			return nil, fmt.Errorf("feature %d: %v", i, err)
+		}
+		result = append(result, TesselatedFeature{
+			ID:         f.ID,
+			Properties: f.Properties,
+			Indices:    indices,
+			Vertices:   vertices,
+		})
+	}
+	return result, nil
+}
+
+// Geometry 将三角形输出为GeoJSON MultiPolygon，每个三角形是一个闭合的逆时针外环。
+// 只有当顶点带有非零Z时才输出第三个坐标。
+func (f *TesselatedFeature) Geometry() *GeoJSONGeometry {
+	hasZ := false
+	for _, v := range f.Vertices {
+		if v.Z != 0 {
+			hasZ = true
+			break
+		}
+	}
+	position := func(v Vertex) []float32 {
+		if hasZ {
+			return []float32{v.X, v.Y, v.Z}
+		}
+		return []float32{v.X, v.Y}
+	}
+
+	polygons := make([][][][]float32, 0, len(f.Indices)/3)
+	for i := 0; i+2 < len(f.Indices); i += 3 {
+		a, b, c := f.Vertices[f.Indices[i]], f.Vertices[f.Indices[i+1]], f.Vertices[f.Indices[i+2]]
+		// RFC 7946要求外环逆时针
+		if (b.X-a.X)*(c.Y-a.Y)-(b.Y-a.Y)*(c.X-a.X) < 0 {
+			b, c = c, b
+		}
+		ring := [][]float32{position(a), position(b), position(c), position(a)}
+		polygons = append(polygons, [][][]float32{ring})
+	}
+
+	// 坐标为float32，json会按32位精度输出
+	coords, _ := json.Marshal(polygons)
+	return &GeoJSONGeometry{Type: "MultiPolygon", Coordinates: coords}
+}
+
+// Feature 将三角剖分结果转换为带有原ID和属性的GeoJSON要素
+func (f *TesselatedFeature) Feature() *GeoJSONFeature {
+	return &GeoJSONFeature{
+		Type:       "Feature",
+		ID:         f.ID,
+		Geometry:   f.Geometry(),
+		Properties: f.Properties,
+	}
+}
+
+// WriteGeoJSON 将三角剖分结果写为FeatureCollection，每个要素的几何为三角形组成的MultiPolygon
+func WriteGeoJSON(w io.Writer, features []TesselatedFeature) error {
+	fc := GeoJSONFeatureCollection{
+		Type:     "FeatureCollection",
+		Features: make([]*GeoJSONFeature, len(features)),
+	}
+	for i := range features {
+		fc.Features[i] = features[i].Feature()
+	}
+	return json.NewEncoder(w).Encode(&fc)
+}
+
+// FeatureBuffers 按要素ID索引三角剖分结果的顶点和索引缓冲区。
+// 字符串ID直接作为键，数字ID格式化为字符串，没有ID的要素使用其在列表中的位置。
+// 两个要素得到相同的键时（重复的ID，或没有ID的要素的位置与另一要素的ID相同）返回错误，
+// 而不是覆盖前一个要素的缓冲区
+func FeatureBuffers(features []TesselatedFeature) (map[string]TesselatedFeature, error) {
+	buffers := make(map[string]TesselatedFeature, len(features))
+	first := make(map[string]int, len(features))
+	for i, f := range features {
+		key := featureKey(f.ID, i)
+		if j, ok := first[key]; ok {
+			return nil, fmt.Errorf("geojson: features %d and %d have the same key %q", j, i, key)
+		}
+		first[key] = i
+		buffers[key] = f
+	}
+	return buffers, nil
+}
+
+func featureKey(id interface{}, i int) string {
+	switch v := id.(type) {
+	case nil:
+		return strconv.Itoa(i)
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case json.Number:
+		return v.String()
+	}
+	return fmt.Sprint(id)
+}
@@ -0,0 +1,187 @@
+package tesselator
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+const testFeatureCollection = `{
+  "type": "FeatureCollection",
+  "features": [
+    {
+      "type": "Feature",
+      "id": "frame",
+      "properties": {"name": "square with hole", "height": 12.5},
+      "geometry": {
+        "type": "Polygon",
+        "coordinates": [
+          [[0,0],[10,0],[10,10],[0,10],[0,0]],
+          [[2,2],[2,8],[8,8],[8,2],[2,2]]
+        ]
+      }
+    },
+    {
+      "type": "Feature",
+      "id": 7,
+      "properties": {"name": "two triangles"},
+      "geometry": {
+        "type": "MultiPolygon",
+        "coordinates": [
+          [[[20,0,1],[24,0,1],[20,4,1],[20,0,1]]],
+          [[[30,0,1],[34,0,1],[30,4,1],[30,0,1]]]
+        ]
+      }
+    },
+    {
+      "type": "Feature",
+      "properties": null,
+      "geometry": {
+        "type": "GeometryCollection",
+        "geometries": [
+          {"type": "Point", "coordinates": [5, 5]},
+          {"type": "Polygon", "coordinates": [[[0,20],[5,20],[5,25],[0,25],[0,20]]]}
+        ]
+      }
+    }
+  ]
+}`
+
+// TestGeoJSONContours 测试几何对象到轮廓的转换
+func TestGeoJSONContours(t *testing.T) {
+	features, err := DecodeGeoJSON([]byte(testFeatureCollection))
+	if err != nil {
+		t.Fatalf("DecodeGeoJSON returned an error: %v", err)
+	}
+	if len(features) != 3 {
+		t.Fatalf("expected 3 features, got %d", len(features))
+	}
+
+	contours, err := features[0].Geometry.Contours()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 闭合点被去掉
+	if len(contours) != 2 || len(contours[0]) != 4 || len(contours[1]) != 4 {
+		t.Fatalf("unexpected polygon contours %v", contours)
+	}
+
+	contours, _ = features[1].Geometry.Contours()
+	if len(contours) != 2 || contours[0][0].Z != 1 {
+		t.Errorf("unexpected multipolygon contours %v", contours)
+	}
+
+	contours, _ = features[2].Geometry.Contours()
+	if len(contours) != 1 {
+		t.Errorf("geometry collection should yield 1 contour, got %d", len(contours))
+	}
+
+	// 单独的几何对象
+	features, err = DecodeGeoJSON([]byte(`{"type":"Polygon","coordinates":[[[0,0],[1,0],[0,1]]]}`))
+	if err != nil || len(features) != 1 || features[0].Geometry.Type != "Polygon" {
+		t.Errorf("bare geometry should decode to one feature, got %v, %v", features, err)
+	}
+
+	for _, doc := range []string{
+		`{"type":"Polygon","coordinates":[[[0],[1,0],[0,1]]]}`,
+		`{"type":"Polygon","coordinates":[0,1]}`,
+		`{"type":"Circle","coordinates":[]}`,
+	} {
+		features, err := DecodeGeoJSON([]byte(doc))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := features[0].Geometry.Contours(); err == nil {
+			t.Errorf("%s: expected an error", doc)
+		}
+	}
+	if _, err := DecodeGeoJSON([]byte(`{"coordinates":[]}`)); err == nil {
+		t.Error("expected an error for missing type")
+	}
+}
+
+// TestTesselateFeatures 测试逐要素三角剖分以及两种输出形式
+func TestTesselateFeatures(t *testing.T) {
+	features, err := DecodeGeoJSON([]byte(testFeatureCollection))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := TesselateFeatures(features, WindingRuleOdd)
+	if err != nil {
+		t.Fatalf("TesselateFeatures returned an error: %v", err)
+	}
+
+	areas := []float64{64, 16, 25}
+	for i, f := range result {
+		if a := meshArea(f.Indices, f.Vertices); math.Abs(a-areas[i]) > 1e-3 {
+			t.Errorf("feature %d: expected area %.1f, got %.3f", i, areas[i], a)
+		}
+	}
+	if result[0].Properties["name"] != "square with hole" {
+		t.Errorf("properties not preserved: %v", result[0].Properties)
+	}
+
+	buffers, err := FeatureBuffers(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := buffers["frame"]; !ok {
+		t.Error("missing buffers for string id")
+	}
+	if b, ok := buffers["7"]; !ok || b.Properties["name"] != "two triangles" {
+		t.Error("missing buffers for numeric id")
+	}
+	if _, ok := buffers["2"]; !ok {
+		t.Error("feature without id should be keyed by its position")
+	}
+	// 重复的ID以及与没有ID的要素的位置相同的ID
+	for _, ids := range [][]interface{}{{"frame", "frame"}, {"1", nil}, {"7", float64(7)}} {
+		dup := []TesselatedFeature{{ID: ids[0]}, {ID: ids[1]}}
+		if _, err := FeatureBuffers(dup); err == nil {
+			t.Errorf("expected an error for ids %v", ids)
+		}
+	}
+
+	// 写出后重新读取，三角形作为MultiPolygon应得到相同的面积
+	var buf bytes.Buffer
+	if err := WriteGeoJSON(&buf, result); err != nil {
+		t.Fatalf("WriteGeoJSON returned an error: %v", err)
+	}
+	decoded, err := DecodeGeoJSON(buf.Bytes())
+	if err != nil {
+		t.Fatalf("failed to decode written GeoJSON: %v", err)
+	}
+	if len(decoded) != 3 || decoded[0].ID != "frame" || decoded[1].ID != float64(7) {
+		t.Fatalf("ids not preserved in output")
+	}
+	if decoded[0].Properties["height"] != 12.5 {
+		t.Errorf("properties not preserved in output: %v", decoded[0].Properties)
+	}
+	for i, f := range decoded {
+		if f.Geometry.Type != "MultiPolygon" {
+			t.Fatalf("feature %d: expected MultiPolygon, got %s", i, f.Geometry.Type)
+		}
+		contours, err := f.Geometry.Contours()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(contours) != len(result[i].Indices)/3 {
+			t.Errorf("feature %d: expected %d triangles, got %d", i, len(result[i].Indices)/3, len(contours))
+		}
+		area := 0.0
+		for _, c := range contours {
+			// 每个三角形都是逆时针的
+			a := contourSignedArea(c)
+			if a <= 0 {
+				t.Errorf("feature %d: triangle %v is not counter-clockwise", i, c)
+			}
+			area += a
+		}
+		if math.Abs(area-areas[i]) > 1e-3 {
+			t.Errorf("feature %d: expected area %.1f, got %.3f", i, areas[i], area)
+		}
+		if i == 1 && contours[0][0].Z != 1 {
+			t.Errorf("z coordinate not written")
+		}
+	}
+}
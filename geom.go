@@ -226,3 +226,40 @@ func edgeIntersect(o1 *vertex, d1 *vertex, o2 *vertex, d2 *vertex, v *vertex) {
 		v.t = interpolate(z1, o2.t, z2, d2.t)
 	}
 }
+
+// inCircle returns a positive value if v lies inside the circle through
+// v0, v1 and v2 (given in CCW order), negative if outside and zero if on it.
+func inCircle(v, v0, v1, v2 *vertex) float64 {
+	adx, ady := float64(v0.s)-float64(v.s), float64(v0.t)-float64(v.t)
+	bdx, bdy := float64(v1.s)-float64(v.s), float64(v1.t)-float64(v.t)
+	cdx, cdy := float64(v2.s)-float64(v.s), float64(v2.t)-float64(v.t)
+
+	abdet := adx*bdy - bdx*ady
+	bcdet := bdx*cdy - cdx*bdy
+	cadet := cdx*ady - adx*cdy
+
+	alift := adx*adx + ady*ady
+	blift := bdx*bdx + bdy*bdy
+	clift := cdx*cdx + cdy*cdy
+
+	return alift*bcdet + blift*cadet + clift*abdet
+}
+
+// edgeIsLocallyDelaunay reports whether the vertex opposite to e in its
+// right triangle lies outside the circumcircle of its left triangle.
+func edgeIsLocallyDelaunay(e *halfEdge) bool {
+	return inCircle(e.Sym.Lnext.Lnext.Org, e.Lnext.Org, e.Lnext.Lnext.Org, e.Org) < 0
+}
+
+// edgeFlipIsConvex reports whether the quadrilateral formed by the two
+// triangles adjacent to e is strictly convex, so that flipping e keeps
+// both triangles CCW.  The in-circle test alone is not robust enough to
+// guarantee this for nearly degenerate triangles.
+func edgeFlipIsConvex(e *halfEdge) bool {
+	aOrg, bOrg := e.Org, e.dst()
+	aOpp, bOpp := e.Lnext.dst(), e.Sym.Lnext.dst()
+	orient := func(u, v, w *vertex) float64 {
+		return (float64(v.s)-float64(u.s))*(float64(w.t)-float64(u.t)) - (float64(v.t)-float64(u.t))*(float64(w.s)-float64(u.s))
+	}
+	return orient(bOpp, aOpp, aOrg) > 0 && orient(aOpp, bOpp, bOrg) > 0
+}
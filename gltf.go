@@ -0,0 +1,351 @@
+package tesselator
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+)
+
+// GLTFPrimitive glTF网格中的一个图元。Normals、UVs和Colors可为空，
+// 不为空时长度必须与Vertices相同
+type GLTFPrimitive struct {
+	Indices  []int
+	Vertices []Vertex
+	Normals  []Vertex
+	UVs      [][2]float32
+	Colors   [][4]float32 // 线性RGBA，取值范围[0, 1]
+}
+
+// GLBOptions GLB输出选项
+type GLBOptions struct {
+	// ZUp 输入坐标以Z轴向上（如带高度的地图数据）。glTF约定Y轴向上，
+	// 设置后在根节点上加一个绕X轴-90度的旋转，顶点数据本身不变
+	ZUp bool
+}
+
+// MergeGLTFPrimitives 将多个图元合并为一个，用于按批次输出。
+// 只有所有图元都带有某种属性时，合并结果才保留该属性
+func MergeGLTFPrimitives(primitives []GLTFPrimitive) GLTFPrimitive {
+	var merged GLTFPrimitive
+	hasNormals, hasUVs, hasColors := true, true, true
+	for _, p := range primitives {
+		hasNormals = hasNormals && p.Normals != nil
+		hasUVs = hasUVs && p.UVs != nil
+		hasColors = hasColors && p.Colors != nil
+	}
+	for _, p := range primitives {
+		base := len(merged.Vertices)
+		for _, i := range p.Indices {
+			merged.Indices = append(merged.Indices, base+i)
+		}
+		merged.Vertices = append(merged.Vertices, p.Vertices...)
+		if hasNormals {
+			merged.Normals = append(merged.Normals, p.Normals...)
+		}
+		if hasUVs {
+			merged.UVs = append(merged.UVs, p.UVs...)
+		}
+		if hasColors {
+			merged.Colors = append(merged.Colors, p.Colors...)
+		}
+	}
+	return merged
+}
+
+func (p *GLTFPrimitive) check() error {
+	attrs := &MeshAttributes{Normals: p.Normals, UVs: p.UVs}
+	if err := attrs.check(p.Indices, p.Vertices); err != nil {
+		return err
+	}
+	if p.Colors != nil && len(p.Colors) != len(p.Vertices) {
+		return fmt.Errorf("expected %d colors, got %d", len(p.Vertices), len(p.Colors))
+	}
+	return nil
+}
+
+// glTF常量
+const (
+	gltfComponentUnsignedShort = 5123
+	gltfComponentUnsignedInt   = 5125
+	gltfComponentFloat         = 5126
+
+	gltfTargetArrayBuffer        = 34962
+	gltfTargetElementArrayBuffer = 34963
+
+	glbMagic     = 0x46546C67 // "glTF"
+	glbChunkJSON = 0x4E4F534A // "JSON"
+	glbChunkBIN  = 0x004E4942 // "BIN\0"
+)
+
+type gltfAsset struct {
+	Version   string `json:"version"`
+	Generator string `json:"generator,omitempty"`
+}
+
+type gltfScene struct {
+	Nodes []int `json:"nodes"`
+}
+
+type gltfNode struct {
+	Mesh     *int      `json:"mesh,omitempty"`
+	Rotation []float64 `json:"rotation,omitempty"`
+}
+
+type gltfPrimitive struct {
+	Attributes map[string]int         `json:"attributes"`
+	Indices    *int                   `json:"indices,omitempty"`
+	Mode       int                    `json:"mode"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+type gltfMesh struct {
+	Primitives []gltfPrimitive `json:"primitives"`
+}
+
+type gltfAccessor struct {
+	BufferView    int       `json:"bufferView"`
+	ComponentType int       `json:"componentType"`
+	Count         int       `json:"count"`
+	Type          string    `json:"type"`
+	Min           []float64 `json:"min,omitempty"`
+	Max           []float64 `json:"max,omitempty"`
+}
+
+type gltfBufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset"`
+	ByteLength int `json:"byteLength"`
+	Target     int `json:"target,omitempty"`
+}
+
+type gltfBuffer struct {
+	ByteLength int `json:"byteLength"`
+}
+
+type gltfDocument struct {
+	Asset          gltfAsset              `json:"asset"`
+	ExtensionsUsed []string               `json:"extensionsUsed,omitempty"`
+	Scene          *int                   `json:"scene,omitempty"`
+	Scenes         []gltfScene            `json:"scenes,omitempty"`
+	Nodes          []gltfNode             `json:"nodes,omitempty"`
+	Meshes         []gltfMesh             `json:"meshes,omitempty"`
+	Accessors      []gltfAccessor         `json:"accessors,omitempty"`
+	BufferViews    []gltfBufferView       `json:"bufferViews,omitempty"`
+	Buffers        []gltfBuffer           `json:"buffers,omitempty"`
+	Extensions     map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// glbBuilder 组装glTF JSON和二进制缓冲区
+type glbBuilder struct {
+	doc gltfDocument
+	bin []byte
+}
+
+func newGLBBuilder() *glbBuilder {
+	return &glbBuilder{doc: gltfDocument{
+		Asset: gltfAsset{Version: "2.0", Generator: "go-tesselator"},
+	}}
+}
+
+// addBufferView 把数据追加到二进制缓冲区，返回bufferView索引。
+// 按8字节对齐，以满足结构元数据中64位数值的对齐要求
+func (b *glbBuilder) addBufferView(data []byte, target int) int {
+	for len(b.bin)%8 != 0 {
+		b.bin = append(b.bin, 0)
+	}
+	b.doc.BufferViews = append(b.doc.BufferViews, gltfBufferView{
+		ByteOffset: len(b.bin),
+		ByteLength: len(data),
+		Target:     target,
+	})
+	b.bin = append(b.bin, data...)
+	return len(b.doc.BufferViews) - 1
+}
+
+func (b *glbBuilder) addAccessor(a gltfAccessor, data []byte, target int) int {
+	a.BufferView = b.addBufferView(data, target)
+	b.doc.Accessors = append(b.doc.Accessors, a)
+	return len(b.doc.Accessors) - 1
+}
+
+// addFloats 添加每个元素n个分量的浮点访问器，withBounds时计算min/max
+func (b *glbBuilder) addFloats(values []float32, n int, typ string, withBounds bool) int {
+	data := make([]byte, 4*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(data[4*i:], math.Float32bits(v))
+	}
+	a := gltfAccessor{ComponentType: gltfComponentFloat, Count: len(values) / n, Type: typ}
+	if withBounds {
+		a.Min = make([]float64, n)
+		a.Max = make([]float64, n)
+		for k := 0; k < n; k++ {
+			a.Min[k] = math.Inf(1)
+			a.Max[k] = math.Inf(-1)
+		}
+		for i, v := range values {
+			a.Min[i%n] = math.Min(a.Min[i%n], float64(v))
+			a.Max[i%n] = math.Max(a.Max[i%n], float64(v))
+		}
+	}
+	return b.addAccessor(a, data, gltfTargetArrayBuffer)
+}
+
+// addIndices 添加索引访问器，最大索引小于65535时使用uint16，否则使用uint32
+// （glTF不允许索引取到所选类型的最大值）
+func (b *glbBuilder) addIndices(indices []int) int {
+	minIdx, maxIdx := math.MaxInt32, 0
+	for _, i := range indices {
+		if i < minIdx {
+			minIdx = i
+		}
+		if i > maxIdx {
+			maxIdx = i
+		}
+	}
+	a := gltfAccessor{
+		Count: len(indices),
+		Type:  "SCALAR",
+		Min:   []float64{float64(minIdx)},
+		Max:   []float64{float64(maxIdx)},
+	}
+	var data []byte
+	if maxIdx < math.MaxUint16 {
+		a.ComponentType = gltfComponentUnsignedShort
+		data = make([]byte, 2*len(indices))
+		for k, i := range indices {
+			binary.LittleEndian.PutUint16(data[2*k:], uint16(i))
+		}
+	} else {
+		a.ComponentType = gltfComponentUnsignedInt
+		data = make([]byte, 4*len(indices))
+		for k, i := range indices {
+			binary.LittleEndian.PutUint32(data[4*k:], uint32(i))
+		}
+	}
+	return b.addAccessor(a, data, gltfTargetElementArrayBuffer)
+}
+
+// addPrimitive 写入图元的顶点属性和索引
+func (b *glbBuilder) addPrimitive(p *GLTFPrimitive) gltfPrimitive {
+	positions := make([]float32, 0, 3*len(p.Vertices))
+	for _, v := range p.Vertices {
+		positions = append(positions, v.X, v.Y, v.Z)
+	}
+	prim := gltfPrimitive{
+		Attributes: map[string]int{"POSITION": b.addFloats(positions, 3, "VEC3", true)},
+		Mode:       4, // TRIANGLES
+	}
+	if p.Normals != nil {
+		normals := make([]float32, 0, 3*len(p.Normals))
+		for _, n := range p.Normals {
+			normals = append(normals, n.X, n.Y, n.Z)
+		}
+		prim.Attributes["NORMAL"] = b.addFloats(normals, 3, "VEC3", false)
+	}
+	if p.UVs != nil {
+		uvs := make([]float32, 0, 2*len(p.UVs))
+		for _, uv := range p.UVs {
+			uvs = append(uvs, uv[0], uv[1])
+		}
+		prim.Attributes["TEXCOORD_0"] = b.addFloats(uvs, 2, "VEC2", false)
+	}
+	if p.Colors != nil {
+		colors := make([]float32, 0, 4*len(p.Colors))
+		for _, c := range p.Colors {
+			colors = append(colors, c[0], c[1], c[2], c[3])
+		}
+		prim.Attributes["COLOR_0"] = b.addFloats(colors, 4, "VEC4", false)
+	}
+	indices := b.addIndices(p.Indices)
+	prim.Indices = &indices
+	return prim
+}
+
+// addMesh 添加包含给定图元的网格和引用它的场景根节点
+func (b *glbBuilder) addMesh(prims []gltfPrimitive, opts *GLBOptions) {
+	if len(prims) == 0 {
+		return
+	}
+	b.doc.Meshes = append(b.doc.Meshes, gltfMesh{Primitives: prims})
+	mesh := len(b.doc.Meshes) - 1
+	node := gltfNode{Mesh: &mesh}
+	if opts != nil && opts.ZUp {
+		node.Rotation = []float64{-math.Sqrt2 / 2, 0, 0, math.Sqrt2 / 2}
+	}
+	b.doc.Nodes = append(b.doc.Nodes, node)
+	scene := 0
+	b.doc.Scene = &scene
+	b.doc.Scenes = []gltfScene{{Nodes: []int{len(b.doc.Nodes) - 1}}}
+}
+
+// encode 写出GLB：12字节文件头、JSON块和BIN块。
+// 3D Tiles要求内嵌的GLB起止于8字节边界，因此总长度总是补齐到8的倍数
+func (b *glbBuilder) encode(w io.Writer) error {
+	for len(b.bin)%4 != 0 {
+		b.bin = append(b.bin, 0)
+	}
+	if len(b.bin) > 0 {
+		b.doc.Buffers = []gltfBuffer{{ByteLength: len(b.bin)}}
+	}
+	js, err := json.Marshal(&b.doc)
+	if err != nil {
+		return err
+	}
+	for len(js)%4 != 0 {
+		js = append(js, ' ')
+	}
+
+	total := 12 + 8 + len(js)
+	if len(b.bin) > 0 {
+		total += 8 + len(b.bin)
+	}
+	if total%8 != 0 {
+		js = append(js, "    "...)
+		total += 4
+	}
+	header := make([]byte, 0, 20)
+	header = binary.LittleEndian.AppendUint32(header, glbMagic)
+	header = binary.LittleEndian.AppendUint32(header, 2)
+	header = binary.LittleEndian.AppendUint32(header, uint32(total))
+	header = binary.LittleEndian.AppendUint32(header, uint32(len(js)))
+	header = binary.LittleEndian.AppendUint32(header, glbChunkJSON)
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if _, err := w.Write(js); err != nil {
+		return err
+	}
+	if len(b.bin) == 0 {
+		return nil
+	}
+	var chunk [8]byte
+	binary.LittleEndian.PutUint32(chunk[:], uint32(len(b.bin)))
+	binary.LittleEndian.PutUint32(chunk[4:], glbChunkBIN)
+	if _, err := w.Write(chunk[:]); err != nil {
+		return err
+	}
+	_, err = w.Write(b.bin)
+	return err
+}
+
+// WriteGLB 将图元写为二进制glTF 2.0（GLB）。每个GLTFPrimitive对应网格中的一个图元，
+// 例如每个多边形一个图元；需要按批次输出时先用MergeGLTFPrimitives合并。
+// 没有三角形的图元会被跳过。opts可为nil
+func WriteGLB(w io.Writer, primitives []GLTFPrimitive, opts *GLBOptions) error {
+	b := newGLBBuilder()
+	var prims []gltfPrimitive
+	for i := range primitives {
+		p := &primitives[i]
+		if err := p.check(); err != nil {
+			return fmt.Errorf("glb: primitive %d: %v", i, err)
+		}
+		if len(p.Indices) == 0 {
+			continue
+		}
+		prims = append(prims, b.addPrimitive(p))
+	}
+	b.addMesh(prims, opts)
+	return b.encode(w)
+}
@@ -0,0 +1,286 @@
+package tesselator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+// glbPrimitiveData 从GLB中读回的图元数据
+type glbPrimitiveData struct {
+	Indices       []int
+	Positions     [][]float64
+	Attributes    map[string][][]float64
+	IndexCompType int
+}
+
+// parseGLB 解析GLB并做结构校验：文件头、块、bufferView范围和对齐、
+// 访问器长度、min/max以及索引范围。返回文档和每个图元的数据
+func parseGLB(t *testing.T, data []byte) (*gltfDocument, [][]glbPrimitiveData) {
+	t.Helper()
+	le := binary.LittleEndian
+	if len(data) < 20 || le.Uint32(data) != glbMagic || le.Uint32(data[4:]) != 2 {
+		t.Fatalf("invalid GLB header")
+	}
+	if int(le.Uint32(data[8:])) != len(data) {
+		t.Fatalf("GLB length %d does not match data size %d", le.Uint32(data[8:]), len(data))
+	}
+	jsonLen := int(le.Uint32(data[12:]))
+	if le.Uint32(data[16:]) != glbChunkJSON || jsonLen%4 != 0 || 20+jsonLen > len(data) {
+		t.Fatalf("invalid JSON chunk")
+	}
+	var doc gltfDocument
+	if err := json.Unmarshal(data[20:20+jsonLen], &doc); err != nil {
+		t.Fatalf("invalid glTF JSON: %v", err)
+	}
+	var raw map[string]interface{}
+	json.Unmarshal(data[20:20+jsonLen], &raw)
+	if asset, _ := raw["asset"].(map[string]interface{}); asset["version"] != "2.0" {
+		t.Fatalf("missing asset version")
+	}
+
+	var bin []byte
+	if rest := data[20+jsonLen:]; len(rest) > 0 {
+		if len(rest) < 8 || le.Uint32(rest[4:]) != glbChunkBIN {
+			t.Fatalf("invalid BIN chunk")
+		}
+		n := int(le.Uint32(rest))
+		if n%4 != 0 || 8+n != len(rest) {
+			t.Fatalf("invalid BIN chunk length %d", n)
+		}
+		bin = rest[8:]
+	}
+	if len(doc.Buffers) > 1 || (len(doc.Buffers) == 1) != (bin != nil) {
+		t.Fatalf("buffers do not match the BIN chunk")
+	}
+	if bin != nil && doc.Buffers[0].ByteLength > len(bin) {
+		t.Fatalf("buffer length %d exceeds BIN chunk", doc.Buffers[0].ByteLength)
+	}
+	for i, bv := range doc.BufferViews {
+		if bv.Buffer != 0 || bv.ByteOffset%4 != 0 || bv.ByteOffset+bv.ByteLength > len(bin) {
+			t.Fatalf("bufferView %d out of range or misaligned: %+v", i, bv)
+		}
+	}
+
+	components := map[string]int{"SCALAR": 1, "VEC2": 2, "VEC3": 3, "VEC4": 4}
+	readAccessor := func(idx int) [][]float64 {
+		if idx < 0 || idx >= len(doc.Accessors) {
+			t.Fatalf("accessor %d out of range", idx)
+		}
+		a := doc.Accessors[idx]
+		n := components[a.Type]
+		size := map[int]int{gltfComponentUnsignedShort: 2, gltfComponentUnsignedInt: 4, gltfComponentFloat: 4, 5121: 1}[a.ComponentType]
+		if n == 0 || size == 0 || a.Count < 1 {
+			t.Fatalf("accessor %d: invalid type %s/%d or count %d", idx, a.Type, a.ComponentType, a.Count)
+		}
+		bv := doc.BufferViews[a.BufferView]
+		if bv.ByteLength != a.Count*n*size {
+			t.Fatalf("accessor %d: bufferView length %d, expected %d", idx, bv.ByteLength, a.Count*n*size)
+		}
+		values := make([][]float64, a.Count)
+		buf := bin[bv.ByteOffset:]
+		for i := range values {
+			values[i] = make([]float64, n)
+			for k := 0; k < n; k++ {
+				off := (i*n + k) * size
+				switch a.ComponentType {
+				case gltfComponentFloat:
+					values[i][k] = float64(math.Float32frombits(le.Uint32(buf[off:])))
+				case gltfComponentUnsignedInt:
+					values[i][k] = float64(le.Uint32(buf[off:]))
+				case gltfComponentUnsignedShort:
+					values[i][k] = float64(le.Uint16(buf[off:]))
+				default:
+					values[i][k] = float64(buf[off])
+				}
+			}
+		}
+		if a.Min != nil || a.Max != nil {
+			if len(a.Min) != n || len(a.Max) != n {
+				t.Fatalf("accessor %d: min/max must have %d components", idx, n)
+			}
+			for k := 0; k < n; k++ {
+				lo, hi := math.Inf(1), math.Inf(-1)
+				for _, v := range values {
+					lo, hi = math.Min(lo, v[k]), math.Max(hi, v[k])
+				}
+				if lo != a.Min[k] || hi != a.Max[k] {
+					t.Fatalf("accessor %d: min/max [%v, %v] do not match data [%v, %v]", idx, a.Min[k], a.Max[k], lo, hi)
+				}
+			}
+		}
+		return values
+	}
+
+	if doc.Scene != nil && (*doc.Scene >= len(doc.Scenes)) {
+		t.Fatalf("scene index out of range")
+	}
+	for _, s := range doc.Scenes {
+		for _, n := range s.Nodes {
+			if n >= len(doc.Nodes) {
+				t.Fatalf("node index %d out of range", n)
+			}
+		}
+	}
+	meshes := make([][]glbPrimitiveData, len(doc.Meshes))
+	for m, mesh := range doc.Meshes {
+		if len(mesh.Primitives) == 0 {
+			t.Fatalf("mesh %d has no primitives", m)
+		}
+		for _, p := range mesh.Primitives {
+			posIdx, ok := p.Attributes["POSITION"]
+			if !ok || p.Mode != 4 || p.Indices == nil {
+				t.Fatalf("mesh %d: primitive needs POSITION, indices and mode 4", m)
+			}
+			if a := doc.Accessors[posIdx]; a.Min == nil || a.Type != "VEC3" || a.ComponentType != gltfComponentFloat {
+				t.Fatalf("mesh %d: POSITION must be a float VEC3 with min/max", m)
+			}
+			d := glbPrimitiveData{Attributes: map[string][][]float64{}}
+			d.Positions = readAccessor(posIdx)
+			for name, idx := range p.Attributes {
+				values := readAccessor(idx)
+				if len(values) != len(d.Positions) {
+					t.Fatalf("mesh %d: attribute %s has %d elements, expected %d", m, name, len(values), len(d.Positions))
+				}
+				d.Attributes[name] = values
+			}
+			ia := doc.Accessors[*p.Indices]
+			if ia.Type != "SCALAR" || doc.BufferViews[ia.BufferView].Target != gltfTargetElementArrayBuffer {
+				t.Fatalf("mesh %d: invalid index accessor", m)
+			}
+			d.IndexCompType = ia.ComponentType
+			limit := float64(math.MaxUint16)
+			if ia.ComponentType == gltfComponentUnsignedInt {
+				limit = math.MaxUint32
+			}
+			for _, v := range readAccessor(*p.Indices) {
+				if v[0] >= float64(len(d.Positions)) || v[0] >= limit {
+					t.Fatalf("mesh %d: index %v out of range", m, v[0])
+				}
+				d.Indices = append(d.Indices, int(v[0]))
+			}
+			if len(d.Indices)%3 != 0 {
+				t.Fatalf("mesh %d: index count %d is not a multiple of 3", m, len(d.Indices))
+			}
+			meshes[m] = append(meshes[m], d)
+		}
+	}
+	return &doc, meshes
+}
+
+// TestWriteGLBPerPolygon 测试每个多边形一个图元的输出和往返
+func TestWriteGLBPerPolygon(t *testing.T) {
+	var prims []GLTFPrimitive
+	for i, c := range []Contour{
+		{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}},
+		GenerateRegularPolygon(7, 30, 5, 5),
+	} {
+		indices, vertices, err := Tesselate([]Contour{c}, WindingRuleOdd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := GLTFPrimitive{Indices: indices, Vertices: vertices}
+		if i == 0 {
+			for _, v := range vertices {
+				p.Normals = append(p.Normals, Vertex{Z: 1})
+				p.UVs = append(p.UVs, [2]float32{v.X / 10, v.Y / 10})
+				p.Colors = append(p.Colors, [4]float32{1, 0, 0, 1})
+			}
+		}
+		prims = append(prims, p)
+	}
+	// 空图元被跳过
+	prims = append(prims, GLTFPrimitive{})
+
+	var buf bytes.Buffer
+	if err := WriteGLB(&buf, prims, &GLBOptions{ZUp: true}); err != nil {
+		t.Fatalf("WriteGLB returned an error: %v", err)
+	}
+	doc, meshes := parseGLB(t, buf.Bytes())
+	if len(meshes) != 1 || len(meshes[0]) != 2 {
+		t.Fatalf("expected one mesh with 2 primitives, got %v", len(meshes))
+	}
+	if len(doc.Nodes) != 1 || len(doc.Nodes[0].Rotation) != 4 {
+		t.Errorf("Z-up option should add a root rotation")
+	}
+
+	for i, d := range meshes[0] {
+		p := prims[i]
+		if d.IndexCompType != gltfComponentUnsignedShort {
+			t.Errorf("primitive %d: small meshes should use uint16 indices", i)
+		}
+		if len(d.Indices) != len(p.Indices) {
+			t.Fatalf("primitive %d: expected %d indices, got %d", i, len(p.Indices), len(d.Indices))
+		}
+		for k, idx := range d.Indices {
+			v, w := p.Vertices[p.Indices[k]], d.Positions[idx]
+			if float64(v.X) != w[0] || float64(v.Y) != w[1] || float64(v.Z) != w[2] {
+				t.Fatalf("primitive %d: vertex mismatch %v vs %v", i, v, w)
+			}
+		}
+	}
+	first := meshes[0][0].Attributes
+	for _, name := range []string{"NORMAL", "TEXCOORD_0", "COLOR_0"} {
+		if _, ok := first[name]; !ok {
+			t.Errorf("missing attribute %s", name)
+		}
+	}
+	if _, ok := meshes[0][1].Attributes["NORMAL"]; ok {
+		t.Errorf("second primitive should not have normals")
+	}
+}
+
+// TestWriteGLBBatch 测试合并批次和uint32索引的自动选择
+func TestWriteGLBBatch(t *testing.T) {
+	// 超过65535个顶点的条带网格
+	const n = 40000
+	var big GLTFPrimitive
+	for i := 0; i < n; i++ {
+		big.Vertices = append(big.Vertices, Vertex{X: float32(i), Y: 0}, Vertex{X: float32(i), Y: 1})
+		if i > 0 {
+			a, b, c, d := 2*i-2, 2*i-1, 2*i, 2*i+1
+			big.Indices = append(big.Indices, a, c, b, b, c, d)
+		}
+	}
+	small := GLTFPrimitive{Indices: []int{0, 1, 2}, Vertices: []Vertex{{X: -1, Y: -1, Z: 2}, {X: -0.5, Y: -1, Z: 2}, {X: -1, Y: -0.5, Z: 3}}}
+	merged := MergeGLTFPrimitives([]GLTFPrimitive{small, big})
+
+	var buf bytes.Buffer
+	if err := WriteGLB(&buf, []GLTFPrimitive{merged}, nil); err != nil {
+		t.Fatalf("WriteGLB returned an error: %v", err)
+	}
+	doc, meshes := parseGLB(t, buf.Bytes())
+	if len(meshes) != 1 || len(meshes[0]) != 1 {
+		t.Fatalf("expected a single batched primitive")
+	}
+	d := meshes[0][0]
+	if d.IndexCompType != gltfComponentUnsignedInt {
+		t.Errorf("large meshes should use uint32 indices")
+	}
+	if len(d.Positions) != 2*n+3 || len(d.Indices) != 3+len(big.Indices) {
+		t.Errorf("unexpected merged sizes: %d vertices, %d indices", len(d.Positions), len(d.Indices))
+	}
+	if d.Indices[3] != big.Indices[0]+3 {
+		t.Errorf("merged indices should be offset by the preceding vertices")
+	}
+	pos := doc.Accessors[doc.Meshes[0].Primitives[0].Attributes["POSITION"]]
+	if pos.Min[0] != -1 || pos.Max[0] != n-1 || pos.Min[2] != 0 || pos.Max[2] != 3 {
+		t.Errorf("unexpected position bounds %v %v", pos.Min, pos.Max)
+	}
+
+	// 没有任何三角形时仍输出合法的GLB
+	buf.Reset()
+	if err := WriteGLB(&buf, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, meshes := parseGLB(t, buf.Bytes()); len(meshes) != 0 {
+		t.Errorf("expected no meshes")
+	}
+
+	bad := GLTFPrimitive{Indices: []int{0, 1, 2}, Vertices: small.Vertices, Normals: []Vertex{{}}}
+	if err := WriteGLB(&buf, []GLTFPrimitive{bad}, nil); err == nil {
+		t.Error("expected an error for mismatched normals")
+	}
+}
@@ -0,0 +1,119 @@
+package tesselator
+
+import "math"
+
+// Vertex64 双精度顶点，用于UTM、ECEF、Web墨卡托等量级很大的坐标
+type Vertex64 struct {
+	X float64
+	Y float64
+	Z float64
+}
+
+// Contour64 双精度顶点构成的轮廓
+type Contour64 []Vertex64
+
+// LocalFrame 局部坐标系：局部坐标 = (世界坐标 - Origin) × Scale。
+// 绘制RTC（相对中心）网格时，把Origin作为模型平移、1/Scale作为模型缩放
+type LocalFrame struct {
+	Origin Vertex64
+	Scale  float64
+}
+
+// ToLocal 把世界坐标转换为局部坐标
+func (f LocalFrame) ToLocal(v Vertex64) Vertex {
+	return Vertex{
+		X: float32((v.X - f.Origin.X) * f.Scale),
+		Y: float32((v.Y - f.Origin.Y) * f.Scale),
+		Z: float32((v.Z - f.Origin.Z) * f.Scale),
+	}
+}
+
+// ToWorld 把局部坐标转换为世界坐标
+func (f LocalFrame) ToWorld(v Vertex) Vertex64 {
+	return Vertex64{
+		X: f.Origin.X + float64(v.X)/f.Scale,
+		Y: f.Origin.Y + float64(v.Y)/f.Scale,
+		Z: f.Origin.Z + float64(v.Z)/f.Scale,
+	}
+}
+
+// LocalOptions TesselateLocal的选项
+type LocalOptions struct {
+	// Origin 局部坐标系的原点，为nil时使用所有有效轮廓顶点包围盒的中心
+	Origin *Vertex64
+	// Normalize 把局部坐标缩放到[-1, 1]之内，否则Scale为1
+	Normalize bool
+}
+
+// NewLocalFrame 按选项为轮廓确定局部坐标系，opts可以为nil
+func NewLocalFrame(contours []Contour64, opts *LocalOptions) LocalFrame {
+	if opts == nil {
+		opts = &LocalOptions{}
+	}
+	lo := Vertex64{X: math.Inf(1), Y: math.Inf(1), Z: math.Inf(1)}
+	hi := Vertex64{X: math.Inf(-1), Y: math.Inf(-1), Z: math.Inf(-1)}
+	for _, c := range contours {
+		if len(c) < 3 {
+			continue
+		}
+		for _, v := range c {
+			lo = Vertex64{X: math.Min(lo.X, v.X), Y: math.Min(lo.Y, v.Y), Z: math.Min(lo.Z, v.Z)}
+			hi = Vertex64{X: math.Max(hi.X, v.X), Y: math.Max(hi.Y, v.Y), Z: math.Max(hi.Z, v.Z)}
+		}
+	}
+
+	frame := LocalFrame{Scale: 1}
+	if opts.Origin != nil {
+		frame.Origin = *opts.Origin
+	} else if lo.X <= hi.X {
+		frame.Origin = Vertex64{X: (lo.X + hi.X) / 2, Y: (lo.Y + hi.Y) / 2, Z: (lo.Z + hi.Z) / 2}
+	}
+	if opts.Normalize && lo.X <= hi.X {
+		extent := 0.0
+		for _, d := range []float64{
+			lo.X - frame.Origin.X, hi.X - frame.Origin.X,
+			lo.Y - frame.Origin.Y, hi.Y - frame.Origin.Y,
+			lo.Z - frame.Origin.Z, hi.Z - frame.Origin.Z,
+		} {
+			extent = math.Max(extent, math.Abs(d))
+		}
+		if extent > 0 && !math.IsInf(extent, 0) {
+			frame.Scale = 1 / extent
+		}
+	}
+	return frame
+}
+
+// TesselateLocal 对量级很大的双精度坐标进行三角剖分。轮廓先减去局部坐标系的原点
+// （默认为包围盒中心）再交给Tesselate，因此单精度的内部坐标只需表示相对原点的偏移。
+// 返回三角形索引、世界坐标（双精度）、局部坐标以及局部坐标系：
+// 来自输入的顶点保留原始的双精度坐标，交点由局部坐标换算回世界坐标
+func TesselateLocal(contours []Contour64, windingRule WindingRule, opts *LocalOptions) ([]int, []Vertex64, []Vertex, LocalFrame, error) {
+	frame := NewLocalFrame(contours, opts)
+	local := make([]Contour, len(contours))
+	// 局部坐标相同的输入顶点在剖分中也会合并，取第一个即可
+	world := map[Vertex]Vertex64{}
+	for i, c := range contours {
+		local[i] = make(Contour, len(c))
+		for k, v := range c {
+			local[i][k] = frame.ToLocal(v)
+			if _, ok := world[local[i][k]]; !ok {
+				world[local[i][k]] = v
+			}
+		}
+	}
+
+	indices, vertices, err := Tesselate(local, windingRule)
+	if err != nil {
+		return nil, nil, nil, frame, err
+	}
+	positions := make([]Vertex64, len(vertices))
+	for i, v := range vertices {
+		if p, ok := world[v]; ok {
+			positions[i] = p
+		} else {
+			positions[i] = frame.ToWorld(v)
+		}
+	}
+	return indices, positions, vertices, frame, nil
+}
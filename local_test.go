@@ -0,0 +1,73 @@
+package tesselator
+
+import (
+	"math"
+	"testing"
+)
+
+// TestTesselateLocal 大坐标在局部坐标系中剖分，输出保持双精度
+func TestTesselateLocal(t *testing.T) {
+	// UTM坐标，单精度在此量级的间隔为0.5米
+	x0, y0 := 500000.25, 4500000.25
+	square := func(x, y, size float64) Contour64 {
+		return Contour64{{X: x0 + x, Y: y0 + y, Z: 10}, {X: x0 + x + size, Y: y0 + y, Z: 10}, {X: x0 + x + size, Y: y0 + y + size, Z: 10}, {X: x0 + x, Y: y0 + y + size, Z: 10}}
+	}
+	hole := square(0.4, 0.4, 0.2)
+	for i, j := 0, len(hole)-1; i < j; i, j = i+1, j-1 {
+		hole[i], hole[j] = hole[j], hole[i]
+	}
+	contours := []Contour64{square(0, 0, 1), hole}
+
+	indices, positions, local, frame, err := TesselateLocal(contours, WindingRuleOdd, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (Vertex64{X: x0 + 0.5, Y: y0 + 0.5, Z: 10}); frame.Origin != expected || frame.Scale != 1 {
+		t.Errorf("expected origin %v and scale 1, got %v", expected, frame)
+	}
+	if len(positions) != 8 || len(local) != 8 {
+		t.Fatalf("expected 8 vertices, got %d", len(positions))
+	}
+	for _, p := range positions {
+		found := false
+		for _, c := range contours {
+			for _, v := range c {
+				found = found || v == p
+			}
+		}
+		if !found {
+			t.Errorf("vertex %v is not an input vertex", p)
+		}
+	}
+	if area := worldArea(indices, positions); math.Abs(area-0.96) > 1e-9 {
+		t.Errorf("expected area 0.96, got %g", area)
+	}
+
+	// 自相交产生的交点换算回世界坐标
+	bowtie := Contour64{{X: x0, Y: y0}, {X: x0 + 2, Y: y0 + 2}, {X: x0 + 2, Y: y0}, {X: x0, Y: y0 + 2}}
+	origin := Vertex64{X: x0, Y: y0}
+	_, positions, _, frame, err = TesselateLocal([]Contour64{bowtie}, WindingRuleOdd, &LocalOptions{Origin: &origin, Normalize: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if frame.Origin != origin || frame.Scale != 0.5 {
+		t.Errorf("expected origin %v and scale 0.5, got %v", origin, frame)
+	}
+	found := false
+	for _, p := range positions {
+		found = found || math.Hypot(p.X-x0-1, p.Y-y0-1) < 1e-6
+	}
+	if !found {
+		t.Errorf("intersection point is missing from %v", positions)
+	}
+}
+
+// worldArea 返回双精度三角形在XY平面上的面积之和
+func worldArea(indices []int, positions []Vertex64) float64 {
+	total := 0.0
+	for i := 0; i < len(indices); i += 3 {
+		a, b, c := positions[indices[i]], positions[indices[i+1]], positions[indices[i+2]]
+		total += math.Abs((b.X-a.X)*(c.Y-a.Y)-(c.X-a.X)*(b.Y-a.Y)) / 2
+	}
+	return total
+}
@@ -119,6 +119,9 @@ type halfEdge struct {
 	// change in winding number when crossing
 	// from the right face to the left face
 	winding int
+
+	constrained bool // edge of a constraint polyline, never flipped
+	mark        bool // used by the edge flip algorithm
 }
 
 // The mesh structure is similar in spirit, notation, and operations
@@ -548,6 +551,8 @@ func tessMeshSplitEdge(mesh *mesh, eOrg *halfEdge) *halfEdge {
 	eNew.setRFace(eOrg.rFace())
 	eNew.winding = eOrg.winding // copy old winding information
 	eNew.Sym.winding = eOrg.Sym.winding
+	eNew.constrained = eOrg.constrained
+	eNew.Sym.constrained = eOrg.Sym.constrained
 
 	return eNew
 }
@@ -594,6 +599,88 @@ func tessMeshConnect(mesh *mesh, eOrg *halfEdge, eDst *halfEdge) *halfEdge {
 	return eNew
 }
 
+// tessMeshFlipEdge replaces the diagonal of the quadrilateral formed by
+// the two triangles adjacent to edge with the other diagonal.  Both faces
+// must be triangles; edge and edge.Sym are reused for the new diagonal.
+func tessMeshFlipEdge(mesh *mesh, edge *halfEdge) {
+	a0 := edge
+	a1 := a0.Lnext
+	a2 := a1.Lnext
+	b0 := edge.Sym
+	b1 := b0.Lnext
+	b2 := b1.Lnext
+
+	aOrg := a0.Org
+	aOpp := a2.Org
+	bOrg := b0.Org
+	bOpp := b2.Org
+
+	fa := a0.Lface
+	fb := b0.Lface
+
+	assert(a2.Lnext == a0)
+	assert(b2.Lnext == b0)
+
+	a0.Org = bOpp
+	a0.Onext = b1.Sym
+	b0.Org = aOpp
+	b0.Onext = a1.Sym
+	a2.Onext = b0
+	b2.Onext = a0
+	b1.Onext = a2.Sym
+	a1.Onext = b2.Sym
+
+	a0.Lnext = a2
+	a2.Lnext = b1
+	b1.Lnext = a0
+
+	b0.Lnext = b2
+	b2.Lnext = a1
+	a1.Lnext = b0
+
+	a1.Lface = fb
+	b1.Lface = fa
+
+	fa.anEdge = a0
+	fb.anEdge = b0
+
+	if aOrg.anEdge == a0 {
+		aOrg.anEdge = b1
+	}
+	if bOrg.anEdge == b0 {
+		bOrg.anEdge = a1
+	}
+
+	assert(a0.Lnext.Onext.Sym == a0)
+	assert(a0.Onext.Sym.Lnext == a0)
+	assert(a0.Org.anEdge.Org == a0.Org)
+
+	assert(a1.Lnext.Onext.Sym == a1)
+	assert(a1.Onext.Sym.Lnext == a1)
+	assert(a1.Org.anEdge.Org == a1.Org)
+
+	assert(a2.Lnext.Onext.Sym == a2)
+	assert(a2.Onext.Sym.Lnext == a2)
+	assert(a2.Org.anEdge.Org == a2.Org)
+
+	assert(b0.Lnext.Onext.Sym == b0)
+	assert(b0.Onext.Sym.Lnext == b0)
+	assert(b0.Org.anEdge.Org == b0.Org)
+
+	assert(b1.Lnext.Onext.Sym == b1)
+	assert(b1.Onext.Sym.Lnext == b1)
+	assert(b1.Org.anEdge.Org == b1.Org)
+
+	assert(b2.Lnext.Onext.Sym == b2)
+	assert(b2.Onext.Sym.Lnext == b2)
+	assert(b2.Org.anEdge.Org == b2.Org)
+
+	assert(aOrg.anEdge.Org == aOrg)
+	assert(bOrg.anEdge.Org == bOrg)
+
+	assert(a0.oPrev().Onext.Org == a0.Org)
+}
+
 // tessMeshZapFace destroys a face and removes it from the
 // global face list.  All edges of fZap will have a nil pointer as their
 // left face.  Any edges which also have a nil pointer as their right face
@@ -0,0 +1,224 @@
+package tesselator
+
+import "math"
+
+// PolygonNode 轮廓嵌套树的节点：一个外轮廓及其洞，Children为位于这些洞中的岛屿。
+// 外轮廓在XY平面上为逆时针，洞为顺时针
+type PolygonNode struct {
+	Outer    Contour
+	Holes    []Contour
+	Children []*PolygonNode
+}
+
+// Contours 返回外轮廓和洞，可直接作为Tesselate等函数的输入（不包括子节点）
+func (n *PolygonNode) Contours() []Contour {
+	return append([]Contour{n.Outer}, n.Holes...)
+}
+
+// BoundaryTree 按环绕规则求出区域的边界，并组织为嵌套树：返回最外层的多边形，
+// 每个多边形记录属于它的洞，洞中的岛屿作为其子节点。自相交和重叠在求边界时已经消除，
+// 只使用XY坐标
+func BoundaryTree(contours []Contour, windingRule WindingRule) ([]*PolygonNode, error) {
+	boundary, err := boundaryContours(contours, windingRule)
+	if err != nil {
+		return nil, err
+	}
+	areas := make([]float64, len(boundary))
+	for i, c := range boundary {
+		areas[i] = contourSignedArea(c)
+	}
+	parents := contourParents(boundary, areas)
+
+	// 外轮廓（面积为正）建立节点，洞挂到直接包含它的外轮廓上，
+	// 外轮廓的父轮廓是洞时成为该洞所属节点的子节点
+	nodes := make([]*PolygonNode, len(boundary))
+	for i, c := range boundary {
+		if areas[i] > 0 {
+			nodes[i] = &PolygonNode{Outer: c}
+		}
+	}
+	var roots []*PolygonNode
+	for i, c := range boundary {
+		p := parents[i]
+		if areas[i] > 0 {
+			if p >= 0 && areas[p] < 0 && nodes[parents[p]] != nil {
+				owner := nodes[parents[p]]
+				owner.Children = append(owner.Children, nodes[i])
+			} else {
+				roots = append(roots, nodes[i])
+			}
+		} else if p >= 0 && nodes[p] != nil {
+			nodes[p].Holes = append(nodes[p].Holes, c)
+		}
+	}
+	return roots, nil
+}
+
+// contourParents 返回每个轮廓的父轮廓：包含它的轮廓中面积绝对值最小的一个，没有时为-1
+func contourParents(contours []Contour, areas []float64) []int {
+	rings := newNestingRings(contours)
+	parents := make([]int, len(contours))
+	for i := range contours {
+		parents[i] = -1
+		for j := range contours {
+			if i == j || math.Abs(areas[j]) <= math.Abs(areas[i]) {
+				continue
+			}
+			if p := parents[i]; p >= 0 && math.Abs(areas[p]) <= math.Abs(areas[j]) {
+				continue
+			}
+			if rings.inside(i, j) {
+				parents[i] = j
+			}
+		}
+	}
+	return parents
+}
+
+// nestingRings 判断轮廓之间的包含关系，每个轮廓的边索引在第一次需要时建立
+type nestingRings struct {
+	contours []Contour
+	bounds   [][4]float32
+	prepared []*PreparedContours
+}
+
+func newNestingRings(contours []Contour) *nestingRings {
+	r := &nestingRings{contours: contours, bounds: make([][4]float32, len(contours)), prepared: make([]*PreparedContours, len(contours))}
+	for i, c := range contours {
+		b := [4]float32{float32(math.Inf(1)), float32(math.Inf(1)), float32(math.Inf(-1)), float32(math.Inf(-1))}
+		for _, v := range c {
+			b[0], b[1] = min32(b[0], v.X), min32(b[1], v.Y)
+			b[2], b[3] = max32(b[2], v.X), max32(b[3], v.Y)
+		}
+		r.bounds[i] = b
+	}
+	return r
+}
+
+// inside 判断轮廓i是否位于轮廓j内部：i的顶点和边的中点除去落在j边界上的以外，
+// 全部在j内部且至少有一个。因此两个轮廓可以共享顶点和边，部分重叠或完全重合时返回false
+func (r *nestingRings) inside(i, j int) bool {
+	bi, bj := r.bounds[i], r.bounds[j]
+	if bi[0] < bj[0] || bi[1] < bj[1] || bi[2] > bj[2] || bi[3] > bj[3] {
+		return false
+	}
+	if r.prepared[j] == nil {
+		r.prepared[j] = NewPreparedContours([]Contour{r.contours[j]}, WindingRuleNonzero)
+	}
+	outer := r.prepared[j]
+	inner := r.contours[i]
+	n := len(inner)
+	found := false
+	for k := 0; k < 2*n; k++ {
+		p := inner[k/2]
+		if k%2 == 1 {
+			q := inner[(k/2+1)%n]
+			p = Vertex{X: (p.X + q.X) / 2, Y: (p.Y + q.Y) / 2}
+		}
+		winding, onBoundary := outer.classify(p)
+		if onBoundary {
+			continue
+		}
+		if winding == 0 {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+func min32(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max32(a, b float32) float32 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// resolveNesting 在环绕规则为WindingRuleNesting时按嵌套深度重新确定轮廓方向，
+// 并返回扫描实际使用的环绕规则；其他规则原样返回
+func resolveNesting(contours []Contour, windingRule WindingRule) ([]Contour, WindingRule) {
+	if windingRule != WindingRuleNesting {
+		return contours, windingRule
+	}
+	reversed, windingRule := nestingReversals(contours)
+	result := append([]Contour(nil), contours...)
+	for i, r := range reversed {
+		if r {
+			result[i] = append(Contour(nil), contours[i]...)
+			reverseContour(result[i])
+		}
+	}
+	return result, windingRule
+}
+
+// nestingReversals 按嵌套深度判断每个轮廓是否需要反向（少于3个顶点的轮廓不反向），
+// 并返回扫描实际使用的环绕规则
+func nestingReversals(contours []Contour) ([]bool, WindingRule) {
+	// 在法线的主轴平面上判断嵌套和方向，使竖直的多边形也能使用
+	var normal [3]float64
+	for _, c := range contours {
+		for i, v := range c {
+			w := c[(i+1)%len(c)]
+			normal[0] += float64(v.Y-w.Y) * float64(v.Z+w.Z)
+			normal[1] += float64(v.Z-w.Z) * float64(v.X+w.X)
+			normal[2] += float64(v.X-w.X) * float64(v.Y+w.Y)
+		}
+	}
+	axis := 2
+	if math.Abs(normal[0]) > math.Abs(normal[axis]) {
+		axis = 0
+	}
+	if math.Abs(normal[1]) > math.Abs(normal[axis]) {
+		axis = 1
+	}
+	project := func(v Vertex) Vertex {
+		p := [3]float32{v.X, v.Y, v.Z}
+		return Vertex{X: p[(axis+1)%3], Y: p[(axis+2)%3]}
+	}
+
+	var valid []int
+	var planar []Contour
+	for i, c := range contours {
+		if len(c) < 3 {
+			continue
+		}
+		p := make(Contour, len(c))
+		for k, v := range c {
+			p[k] = project(v)
+		}
+		valid = append(valid, i)
+		planar = append(planar, p)
+	}
+
+	// 深度为偶数的轮廓是外轮廓，调整为逆时针；奇数为洞，调整为顺时针
+	rings := newNestingRings(planar)
+	reversed := make([]bool, len(contours))
+	total := 0.0
+	for i, c := range planar {
+		depth := 0
+		for j := range planar {
+			if i != j && rings.inside(i, j) {
+				depth++
+			}
+		}
+		area := contourSignedArea(c)
+		if (depth%2 == 0) != (area > 0) {
+			reversed[valid[i]] = true
+			area = -area
+		}
+		total += area
+	}
+	// 扫描前的朝向检查使有向面积之和非负：洞的面积之和超过外轮廓时（如重叠的洞）
+	// 外轮廓的环绕数为负
+	if total < 0 {
+		return reversed, WindingRuleNegative
+	}
+	return reversed, WindingRulePositive
+}
@@ -0,0 +1,146 @@
+package tesselator
+
+import (
+	"math"
+	"testing"
+)
+
+// squareContour 以(x, y)为左下角的逆时针正方形
+func squareContour(x, y, size float64) Contour {
+	return toContour([]Vector2f{{x, y}, {x + size, y}, {x + size, y + size}, {x, y + size}})
+}
+
+// TestBoundaryTree 测试外轮廓、洞、洞中岛屿及其方向
+func TestBoundaryTree(t *testing.T) {
+	// 外轮廓 > 洞 > 岛屿 > 岛屿中的洞，另有一个独立的多边形；全部按顺时针输入
+	contours := []Contour{
+		squareContour(0, 0, 100), squareContour(10, 10, 80), squareContour(20, 20, 60), squareContour(30, 30, 40),
+		squareContour(200, 0, 10),
+	}
+	for _, c := range contours {
+		reverseContour(c)
+	}
+	roots, err := BoundaryTree(contours, WindingRuleOdd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roots) != 2 {
+		t.Fatalf("expected 2 root polygons, got %d", len(roots))
+	}
+	var big, small *PolygonNode
+	for _, r := range roots {
+		if math.Abs(contourSignedArea(r.Outer)-10000) < 1e-3 {
+			big = r
+		} else {
+			small = r
+		}
+	}
+	if big == nil || small == nil || len(small.Holes) != 0 || len(small.Children) != 0 {
+		t.Fatalf("unexpected roots %+v", roots)
+	}
+	if len(big.Holes) != 1 || math.Abs(contourSignedArea(big.Holes[0])+6400) > 1e-3 {
+		t.Fatalf("outer square should have the 80x80 hole, got %v", big.Holes)
+	}
+	if len(big.Children) != 1 {
+		t.Fatalf("the hole should contain one island, got %d", len(big.Children))
+	}
+	island := big.Children[0]
+	if math.Abs(contourSignedArea(island.Outer)-3600) > 1e-3 || len(island.Holes) != 1 ||
+		math.Abs(contourSignedArea(island.Holes[0])+1600) > 1e-3 || len(island.Children) != 0 {
+		t.Errorf("unexpected island %+v", island)
+	}
+
+	// 节点的轮廓可以单独剖分
+	indices, vertices, err := Tesselate(big.Contours(), WindingRuleOdd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a := meshArea(indices, vertices); math.Abs(a-3600) > 1e-3 {
+		t.Errorf("outer polygon with its hole should cover 3600, got %.4f", a)
+	}
+}
+
+// TestBoundaryTreeWindingRule 同一输入在不同环绕规则下得到不同的嵌套关系
+func TestBoundaryTreeWindingRule(t *testing.T) {
+	contours := []Contour{squareContour(0, 0, 10), squareContour(2, 2, 6)}
+
+	roots, err := BoundaryTree(contours, WindingRuleNonzero)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roots) != 1 || len(roots[0].Holes) != 0 {
+		t.Errorf("nonzero rule should fill the inner square, got %+v", roots)
+	}
+
+	roots, _ = BoundaryTree(contours, WindingRuleOdd)
+	if len(roots) != 1 || len(roots[0].Holes) != 1 {
+		t.Errorf("odd rule should turn the inner square into a hole, got %+v", roots)
+	}
+
+	// 只有两层覆盖的部分被保留
+	roots, _ = BoundaryTree(contours, WindingRuleAbsGeqTwo)
+	if len(roots) != 1 || math.Abs(contourSignedArea(roots[0].Outer)-36) > 1e-3 {
+		t.Errorf("abs>=2 rule should keep only the inner square, got %+v", roots)
+	}
+
+	// 自相交的蝴蝶结分成两个三角形
+	bowtie := []Contour{toContour([]Vector2f{{0, 0}, {4, 4}, {4, 0}, {0, 4}})}
+	roots, _ = BoundaryTree(bowtie, WindingRuleNonzero)
+	if len(roots) != 2 {
+		t.Errorf("bowtie should resolve into two polygons, got %d", len(roots))
+	}
+	for _, r := range roots {
+		if contourSignedArea(r.Outer) <= 0 {
+			t.Errorf("outer contour %v is not counter-clockwise", r.Outer)
+		}
+	}
+
+	if roots, err := BoundaryTree(nil, WindingRuleOdd); err != nil || len(roots) != 0 {
+		t.Errorf("empty input should produce an empty tree")
+	}
+}
+
+// TestWindingRuleNesting 嵌套规则的结果不依赖轮廓方向
+func TestWindingRuleNesting(t *testing.T) {
+	cases := []struct {
+		name     string
+		contours []Contour
+		area     float64
+	}{
+		// 外轮廓 > 洞 > 岛屿
+		{"island in hole", []Contour{squareContour(0, 0, 100), squareContour(10, 10, 80), squareContour(20, 20, 60)}, 10000 - 6400 + 3600},
+		// 部分重叠的两个外轮廓合并
+		{"overlapping shells", []Contour{squareContour(0, 0, 10), squareContour(5, 5, 10)}, 175},
+		// 部分重叠的两个洞都不被填充
+		{"overlapping holes", []Contour{squareContour(0, 0, 100), squareContour(10, 10, 30), squareContour(30, 30, 30)}, 10000 - 1700},
+		// 洞与外轮廓共享一条边
+		{"hole touching outer", []Contour{squareContour(0, 0, 10), squareContour(0, 0, 5)}, 75},
+	}
+	for _, c := range cases {
+		// 每个轮廓分别取两种方向，结果都应相同
+		for mask := 0; mask < 1<<len(c.contours); mask++ {
+			contours := make([]Contour, len(c.contours))
+			for i, contour := range c.contours {
+				contours[i] = append(Contour(nil), contour...)
+				if mask&(1<<i) != 0 {
+					reverseContour(contours[i])
+				}
+			}
+			indices, vertices, err := Tesselate(contours, WindingRuleNesting)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if a := meshArea(indices, vertices); math.Abs(a-c.area) > 1e-3 {
+				t.Errorf("%s (orientation mask %b): expected area %v, got %.4f", c.name, mask, c.area, a)
+			}
+
+			prepared := NewPreparedContours(contours, WindingRuleNesting)
+			for _, p := range []Vertex{{X: 1, Y: 2}, {X: 7, Y: 2}, {X: 12, Y: 13}, {X: 25, Y: 26}, {X: 35, Y: 36}, {X: 50, Y: 51}, {X: 95, Y: 96}} {
+				want := meshCoverage(indices, vertices, float64(p.X), float64(p.Y)) > 0
+				if Contains(contours, WindingRuleNesting, p) != want || prepared.Contains(p) != want {
+					t.Errorf("%s (orientation mask %b): containment of %v disagrees with the tessellation", c.name, mask, p)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,311 @@
+package tesselator
+
+import (
+	"math"
+)
+
+// DefaultFlattenTolerance 按屏幕像素计的默认曲线展平误差
+const DefaultFlattenTolerance = 0.25
+
+type pathSegmentKind int
+
+const (
+	pathSegmentLine pathSegmentKind = iota
+	pathSegmentQuad
+	pathSegmentCubic
+	pathSegmentArc
+)
+
+// pathSegment 路径中的一段，终点总是pts[len-1]
+type pathSegment struct {
+	kind pathSegmentKind
+	pts  [3][2]float64
+
+	// 椭圆弧参数（SVG端点参数化）
+	rx, ry   float64
+	rotation float64 // x轴旋转角，单位为弧度
+	largeArc bool
+	sweep    bool
+}
+
+func (s *pathSegment) end() [2]float64 {
+	switch s.kind {
+	case pathSegmentQuad:
+		return s.pts[1]
+	case pathSegmentCubic:
+		return s.pts[2]
+	}
+	return s.pts[0]
+}
+
+type subpath struct {
+	start    [2]float64
+	segments []pathSegment
+	closed   bool
+}
+
+// Path 由直线、二次/三次贝塞尔曲线和椭圆弧组成的矢量路径。
+// 曲线在Flatten时按给定误差自适应地展平为轮廓，零值即可直接使用。
+type Path struct {
+	subpaths []subpath
+	current  [2]float64
+	open     bool // 最后一个子路径是否还能继续追加线段
+}
+
+// NewPath 创建一个空路径
+func NewPath() *Path {
+	return &Path{}
+}
+
+// MoveTo 以(x, y)为起点开始一个新的子路径
+func (p *Path) MoveTo(x, y float32) {
+	p.moveTo([2]float64{float64(x), float64(y)})
+}
+
+// LineTo 添加一条到(x, y)的直线
+func (p *Path) LineTo(x, y float32) {
+	p.add(pathSegment{kind: pathSegmentLine, pts: [3][2]float64{{float64(x), float64(y)}}})
+}
+
+// QuadTo 添加一条以(cx, cy)为控制点、终点为(x, y)的二次贝塞尔曲线
+func (p *Path) QuadTo(cx, cy, x, y float32) {
+	p.add(pathSegment{kind: pathSegmentQuad, pts: [3][2]float64{
+		{float64(cx), float64(cy)},
+		{float64(x), float64(y)},
+	}})
+}
+
+// CubicTo 添加一条三次贝塞尔曲线
+func (p *Path) CubicTo(c1x, c1y, c2x, c2y, x, y float32) {
+	p.add(pathSegment{kind: pathSegmentCubic, pts: [3][2]float64{
+		{float64(c1x), float64(c1y)},
+		{float64(c2x), float64(c2y)},
+		{float64(x), float64(y)},
+	}})
+}
+
+// ArcTo 添加一段到(x, y)的椭圆弧，参数含义与SVG路径的A命令相同，
+// xAxisRotation以角度为单位。半径为0时退化为直线。
+func (p *Path) ArcTo(rx, ry, xAxisRotation float32, largeArc, sweep bool, x, y float32) {
+	p.add(pathSegment{
+		kind:     pathSegmentArc,
+		pts:      [3][2]float64{{float64(x), float64(y)}},
+		rx:       math.Abs(float64(rx)),
+		ry:       math.Abs(float64(ry)),
+		rotation: float64(xAxisRotation) * math.Pi / 180,
+		largeArc: largeArc,
+		sweep:    sweep,
+	})
+}
+
+// Close 闭合当前子路径，当前点回到子路径起点
+func (p *Path) Close() {
+	if !p.open {
+		return
+	}
+	sp := &p.subpaths[len(p.subpaths)-1]
+	sp.closed = true
+	p.current = sp.start
+	p.open = false
+}
+
+// CurrentPoint 返回当前点
+func (p *Path) CurrentPoint() (x, y float32) {
+	return float32(p.current[0]), float32(p.current[1])
+}
+
+func (p *Path) moveTo(pt [2]float64) {
+	// 连续的MoveTo只保留最后一个
+	if p.open && len(p.subpaths[len(p.subpaths)-1].segments) == 0 {
+		p.subpaths = p.subpaths[:len(p.subpaths)-1]
+	}
+	p.subpaths = append(p.subpaths, subpath{start: pt})
+	p.current = pt
+	p.open = true
+}
+
+func (p *Path) add(seg pathSegment) {
+	if !p.open {
+		// 没有MoveTo或者刚刚Close：从当前点开始新的子路径
+		p.moveTo(p.current)
+	}
+	sp := &p.subpaths[len(p.subpaths)-1]
+	sp.segments = append(sp.segments, seg)
+	p.current = seg.end()
+}
+
+// Flatten 将路径展平为轮廓，每个子路径对应一个轮廓。
+// tolerance为曲线与折线之间允许的最大距离，<=0时使用DefaultFlattenTolerance。
+func (p *Path) Flatten(tolerance float32) []Contour {
+	tol := float64(tolerance)
+	if tol <= 0 {
+		tol = DefaultFlattenTolerance
+	}
+
+	contours := make([]Contour, 0, len(p.subpaths))
+	for _, sp := range p.subpaths {
+		f := flattener{tolerance: tol}
+		f.point(sp.start)
+		cur := sp.start
+		for i := range sp.segments {
+			seg := &sp.segments[i]
+			switch seg.kind {
+			case pathSegmentLine:
+				f.point(seg.pts[0])
+			case pathSegmentQuad:
+				f.quad(cur, seg.pts[0], seg.pts[1])
+			case pathSegmentCubic:
+				f.cubic(cur, seg.pts[0], seg.pts[1], seg.pts[2])
+			case pathSegmentArc:
+				f.arc(cur, seg)
+			}
+			cur = seg.end()
+		}
+		c := f.contour
+		if len(c) > 1 && c[0].X == c[len(c)-1].X && c[0].Y == c[len(c)-1].Y {
+			c = c[:len(c)-1]
+		}
+		if len(c) > 0 {
+			contours = append(contours, c)
+		}
+	}
+	return contours
+}
+
+// FlattenForScale 按屏幕缩放比例展平路径，使误差在屏幕上不超过DefaultFlattenTolerance个像素
+func (p *Path) FlattenForScale(scale float32) []Contour {
+	if scale <= 0 {
+		scale = 1
+	}
+	return p.Flatten(DefaultFlattenTolerance / scale)
+}
+
+// Tesselate 按给定误差展平路径并使用指定的环绕规则进行三角剖分
+func (p *Path) Tesselate(windingRule WindingRule, tolerance float32) ([]int, []Vertex, error) {
+	return Tesselate(p.Flatten(tolerance), windingRule)
+}
+
+type flattener struct {
+	tolerance float64
+	contour   Contour
+}
+
+func (f *flattener) point(pt [2]float64) {
+	v := Vertex{X: float32(pt[0]), Y: float32(pt[1])}
+	if n := len(f.contour); n > 0 && f.contour[n-1] == v {
+		return
+	}
+	f.contour = append(f.contour, v)
+}
+
+// 根据Wang公式计算贝塞尔曲线在给定误差下需要的分段数
+func (f *flattener) bezierSteps(degree float64, m float64) int {
+	n := int(math.Ceil(math.Sqrt(degree * (degree - 1) / 8 * m / f.tolerance)))
+	if n < 1 {
+		n = 1
+	}
+	if n > 1000 {
+		n = 1000
+	}
+	return n
+}
+
+func (f *flattener) quad(p0, p1, p2 [2]float64) {
+	m := math.Hypot(p0[0]-2*p1[0]+p2[0], p0[1]-2*p1[1]+p2[1])
+	n := f.bezierSteps(2, m)
+	for i := 1; i <= n; i++ {
+		t := float64(i) / float64(n)
+		u := 1 - t
+		f.point([2]float64{
+			u*u*p0[0] + 2*u*t*p1[0] + t*t*p2[0],
+			u*u*p0[1] + 2*u*t*p1[1] + t*t*p2[1],
+		})
+	}
+}
+
+func (f *flattener) cubic(p0, p1, p2, p3 [2]float64) {
+	m := math.Max(
+		math.Hypot(p0[0]-2*p1[0]+p2[0], p0[1]-2*p1[1]+p2[1]),
+		math.Hypot(p1[0]-2*p2[0]+p3[0], p1[1]-2*p2[1]+p3[1]),
+	)
+	n := f.bezierSteps(3, m)
+	for i := 1; i <= n; i++ {
+		t := float64(i) / float64(n)
+		u := 1 - t
+		a, b, c, d := u*u*u, 3*u*u*t, 3*u*t*t, t*t*t
+		f.point([2]float64{
+			a*p0[0] + b*p1[0] + c*p2[0] + d*p3[0],
+			a*p0[1] + b*p1[1] + c*p2[1] + d*p3[1],
+		})
+	}
+}
+
+// arc 展平SVG端点参数化的椭圆弧，参见SVG 1.1规范附录F.6.5
+func (f *flattener) arc(p0 [2]float64, seg *pathSegment) {
+	p1 := seg.pts[0]
+	rx, ry := seg.rx, seg.ry
+	if p0 == p1 {
+		return
+	}
+	if rx == 0 || ry == 0 {
+		f.point(p1)
+		return
+	}
+
+	cosPhi, sinPhi := math.Cos(seg.rotation), math.Sin(seg.rotation)
+	dx := (p0[0] - p1[0]) / 2
+	dy := (p0[1] - p1[1]) / 2
+	x1 := cosPhi*dx + sinPhi*dy
+	y1 := -sinPhi*dx + cosPhi*dy
+
+	// 半径不足以连接两个端点时按比例放大
+	lambda := x1*x1/(rx*rx) + y1*y1/(ry*ry)
+	if lambda > 1 {
+		s := math.Sqrt(lambda)
+		rx *= s
+		ry *= s
+	}
+
+	num := rx*rx*ry*ry - rx*rx*y1*y1 - ry*ry*x1*x1
+	den := rx*rx*y1*y1 + ry*ry*x1*x1
+	coef := 0.0
+	if num > 0 && den > 0 {
+		coef = math.Sqrt(num / den)
+	}
+	if seg.largeArc == seg.sweep {
+		coef = -coef
+	}
+	cx1 := coef * rx * y1 / ry
+	cy1 := -coef * ry * x1 / rx
+	cx := cosPhi*cx1 - sinPhi*cy1 + (p0[0]+p1[0])/2
+	cy := sinPhi*cx1 + cosPhi*cy1 + (p0[1]+p1[1])/2
+
+	theta1 := math.Atan2((y1-cy1)/ry, (x1-cx1)/rx)
+	theta2 := math.Atan2((-y1-cy1)/ry, (-x1-cx1)/rx)
+	delta := theta2 - theta1
+	if seg.sweep && delta < 0 {
+		delta += 2 * math.Pi
+	} else if !seg.sweep && delta > 0 {
+		delta -= 2 * math.Pi
+	}
+
+	r := math.Max(rx, ry)
+	step := math.Pi / 2
+	if f.tolerance < r {
+		step = math.Min(step, 2*math.Acos(1-f.tolerance/r))
+	}
+	n := int(math.Ceil(math.Abs(delta) / step))
+	if n < 1 {
+		n = 1
+	}
+	for i := 1; i < n; i++ {
+		a := theta1 + delta*float64(i)/float64(n)
+		ex, ey := rx*math.Cos(a), ry*math.Sin(a)
+		f.point([2]float64{
+			cosPhi*ex - sinPhi*ey + cx,
+			sinPhi*ex + cosPhi*ey + cy,
+		})
+	}
+	// 终点精确落在指定位置
+	f.point(p1)
+}
@@ -0,0 +1,161 @@
+package tesselator
+
+import (
+	"math"
+	"testing"
+)
+
+// TestPathLines 测试只有直线的路径
+func TestPathLines(t *testing.T) {
+	p := NewPath()
+	p.MoveTo(0, 0)
+	p.LineTo(10, 0)
+	p.LineTo(10, 10)
+	p.LineTo(0, 10)
+	p.Close()
+
+	contours := p.Flatten(0.1)
+	if len(contours) != 1 || len(contours[0]) != 4 {
+		t.Fatalf("expected one contour with 4 vertices, got %v", contours)
+	}
+	indices, vertices, err := p.Tesselate(WindingRuleOdd, 0.1)
+	if err != nil {
+		t.Fatalf("Tesselate returned an error: %v", err)
+	}
+	if area := meshArea(indices, vertices); math.Abs(area-100) > 1e-3 {
+		t.Errorf("expected area 100, got %.3f", area)
+	}
+}
+
+// TestPathArcCircle 测试两段半圆弧组成的圆及其展平误差
+func TestPathArcCircle(t *testing.T) {
+	const r = 50.0
+	for _, tol := range []float32{1, 0.1, 0.01} {
+		p := NewPath()
+		p.MoveTo(-r, 0)
+		p.ArcTo(r, r, 0, false, true, r, 0)
+		p.ArcTo(r, r, 0, false, true, -r, 0)
+		p.Close()
+
+		contours := p.Flatten(tol)
+		if len(contours) != 1 {
+			t.Fatalf("expected one contour, got %d", len(contours))
+		}
+		c := contours[0]
+		for i := range c {
+			a, b := c[i], c[(i+1)%len(c)]
+			// 顶点在圆上，弦中点到圆的距离不超过误差
+			if d := math.Abs(math.Hypot(float64(a.X), float64(a.Y)) - r); d > 1e-3 {
+				t.Fatalf("vertex %v is off the circle by %f", a, d)
+			}
+			mx, my := float64(a.X+b.X)/2, float64(a.Y+b.Y)/2
+			if d := r - math.Hypot(mx, my); d > float64(tol)+1e-3 {
+				t.Fatalf("tolerance %.2f: chord deviates by %f", tol, d)
+			}
+		}
+
+		indices, vertices, err := Tesselate(contours, WindingRuleNonzero)
+		if err != nil {
+			t.Fatalf("Tesselate returned an error: %v", err)
+		}
+		area := meshArea(indices, vertices)
+		if math.Abs(area-math.Pi*r*r) > 2*math.Pi*r*float64(tol) {
+			t.Errorf("tolerance %.2f: circle area %.2f too far from %.2f", tol, area, math.Pi*r*r)
+		}
+	}
+}
+
+// TestPathArcRadiusCorrection 测试半径过小时按SVG规则放大
+func TestPathArcRadiusCorrection(t *testing.T) {
+	p := NewPath()
+	p.MoveTo(0, 0)
+	p.ArcTo(1, 1, 0, false, true, 10, 0)
+	c := p.Flatten(0.01)[0]
+	// 放大后的半径为5，弧顶点离x轴的距离为5
+	maxDist := float32(0)
+	for _, v := range c {
+		if d := float32(math.Abs(float64(v.Y))); d > maxDist {
+			maxDist = d
+		}
+	}
+	if math.Abs(float64(maxDist)-5) > 0.02 {
+		t.Errorf("expected arc apex at distance 5, got %.3f", maxDist)
+	}
+}
+
+// TestPathBezier 测试贝塞尔曲线的展平
+func TestPathBezier(t *testing.T) {
+	// y = x^2 在 [0,1] 上的抛物线对应二次贝塞尔 (0,0) (0.5,0) (1,1)
+	p := NewPath()
+	p.MoveTo(0, 0)
+	p.QuadTo(0.5, 0, 1, 1)
+	p.LineTo(1, 0)
+	p.Close()
+	indices, vertices, err := p.Tesselate(WindingRuleOdd, 0.0001)
+	if err != nil {
+		t.Fatalf("Tesselate returned an error: %v", err)
+	}
+	if area := meshArea(indices, vertices); math.Abs(area-1.0/3) > 1e-3 {
+		t.Errorf("expected area 1/3 under parabola, got %.5f", area)
+	}
+
+	// 更小的误差产生更多的顶点
+	coarse := NewPath()
+	coarse.MoveTo(0, 0)
+	coarse.CubicTo(0, 100, 100, 100, 100, 0)
+	n1 := len(coarse.Flatten(1)[0])
+	n2 := len(coarse.Flatten(0.01)[0])
+	if n2 <= n1 {
+		t.Errorf("finer tolerance should produce more vertices: %d vs %d", n1, n2)
+	}
+	if n := len(coarse.FlattenForScale(4)[0]); n <= n1 {
+		t.Errorf("higher screen scale should produce more vertices: %d vs %d", n, n1)
+	}
+
+	// 三次曲线的端点
+	c := coarse.Flatten(1)[0]
+	last := c[len(c)-1]
+	if last.X != 100 || last.Y != 0 {
+		t.Errorf("cubic should end at (100, 0), got %v", last)
+	}
+}
+
+// TestPathSubpaths 测试多个子路径和环绕规则
+func TestPathSubpaths(t *testing.T) {
+	p := NewPath()
+	p.MoveTo(0, 0)
+	p.LineTo(10, 0)
+	p.LineTo(10, 10)
+	p.LineTo(0, 10)
+	p.Close()
+	// 同向的内部方块
+	p.MoveTo(2, 2)
+	p.LineTo(8, 2)
+	p.LineTo(8, 8)
+	p.LineTo(2, 8)
+	p.Close()
+
+	indices, vertices, _ := p.Tesselate(WindingRuleOdd, 0)
+	if area := meshArea(indices, vertices); math.Abs(area-64) > 1e-3 {
+		t.Errorf("odd rule: expected area 64, got %.3f", area)
+	}
+	indices, vertices, _ = p.Tesselate(WindingRuleNonzero, 0)
+	if area := meshArea(indices, vertices); math.Abs(area-100) > 1e-3 {
+		t.Errorf("nonzero rule: expected area 100, got %.3f", area)
+	}
+
+	// Close之后的绘制命令从子路径起点开始新的子路径
+	q := NewPath()
+	q.MoveTo(0, 0)
+	q.LineTo(1, 0)
+	q.LineTo(1, 1)
+	q.Close()
+	q.LineTo(-1, 0)
+	q.LineTo(-1, -1)
+	if n := len(q.Flatten(0)); n != 2 {
+		t.Errorf("expected 2 subpaths, got %d", n)
+	}
+	if x, y := q.CurrentPoint(); x != -1 || y != -1 {
+		t.Errorf("unexpected current point (%v, %v)", x, y)
+	}
+}
@@ -108,11 +108,10 @@ func (p *pq) delete(key *vertex) {
 	if !exists {
 		return
 	}
+	// heap.Remove已经把末尾元素移到idx处，该槽位仍在使用，不能放入自由列表
 	heap.Remove(p, idx)
 	// 从索引映射中删除
 	delete(p.index, key)
-	// 将槽位添加到自由列表
-	p.freeList = append(p.freeList, idx)
 }
 
 func (p *pq) minimum() *vertex {
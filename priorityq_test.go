@@ -158,26 +158,29 @@ func TestPriorityQ(t *testing.T) {
 			t.Errorf("Expected freeList to be empty, got %d elements", len(pq.freeList))
 		}
 
-		// 删除一个元素，这应该会向自由列表添加一个索引
+		// 删除一个元素后，heap.Remove已把其他元素移入它的槽位，自由列表中不能有仍在使用的槽位
 		pq.delete(v2)
-
-		// 检查自由列表不为空
-		if len(pq.freeList) == 0 {
-			t.Error("Expected freeList to contain elements after deletion")
+		for _, idx := range pq.freeList {
+			if idx < len(pq.data) {
+				t.Errorf("Expected freeList to hold only unused slots, got slot %d of %d", idx, len(pq.data))
+			}
 		}
 
-		// 插入新元素，应该重用自由列表中的槽位
+		// 插入新元素，不能覆盖仍在队列中的元素
 		pq.insert(v4)
+		for v, idx := range pq.index {
+			if pq.data[idx] != v {
+				t.Errorf("Expected slot %d to hold %v, got %v", idx, v, pq.data[idx])
+			}
+		}
 
-		// 验证队列状态
-		vertices := make([]*vertex, 0, 2)
+		// 验证队列中恰好是v1、v3和v4
+		remaining := map[*vertex]bool{}
 		for !pq.isEmpty() {
-			vertices = append(vertices, pq.extractMin())
+			remaining[pq.extractMin()] = true
 		}
-
-		// 验证我们得到了正确的元素数量
-		if len(vertices) != 2 {
-			t.Errorf("Expected 2 vertices, got %d", len(vertices))
+		if len(remaining) != 3 || !remaining[v1] || !remaining[v3] || !remaining[v4] {
+			t.Errorf("Expected v1, v3 and v4 to remain, got %d vertices", len(remaining))
 		}
 	})
 
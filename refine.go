@@ -0,0 +1,102 @@
+package tesselator
+
+import (
+	"fmt"
+	"math"
+)
+
+// edgeRefiner 为网格细分提供边的长度度量和新顶点的位置
+type edgeRefiner interface {
+	// length 返回a和b之间的边的长度
+	length(a, b *vertex) float64
+	// split 设置拆分a和b之间的边时新顶点v的坐标和编号
+	split(a, b, v *vertex)
+}
+
+// tessMeshRefineEdges 对内部三角形反复进行最长边二分，直到所有边的长度都不超过limit。
+// 拆分一条边时同时拆分两侧的三角形，因此网格始终保持协调，不会产生T形顶点。
+// 中线不长于三角形另外两边中较长的一条，所以边长单调减小，细分必然结束
+func tessMeshRefineEdges(mesh *mesh, refiner edgeRefiner, limit float64) {
+	var stack []*face
+	for f := mesh.fHead.next; f != &mesh.fHead; f = f.next {
+		if f.inside {
+			stack = append(stack, f)
+		}
+	}
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		var longest *halfEdge
+		length := limit
+		e := f.anEdge
+		for {
+			if l := refiner.length(e.Org, e.dst()); l > length {
+				longest, length = e, l
+			}
+			e = e.Lnext
+			if e == f.anEdge {
+				break
+			}
+		}
+		if longest == nil {
+			continue
+		}
+
+		// 拆分边X->Y得到X->P和P->Y，再把P与两侧三角形的对顶点相连
+		e = longest
+		var neighbour *face
+		if r := e.rFace(); r != nil && r.inside {
+			neighbour = r
+		}
+		a, b := e.Org, e.dst()
+		eNew := tessMeshSplitEdge(mesh, e)
+		v := eNew.Org
+		refiner.split(a, b, v)
+		tessMeshConnect(mesh, e, e.Lnext.Lnext.Lnext)
+		if neighbour != nil {
+			s := eNew.Sym
+			tessMeshConnect(mesh, s, s.Lnext.Lnext.Lnext)
+		}
+
+		e = v.anEdge
+		for {
+			if e.Lface.inside {
+				stack = append(stack, e.Lface)
+			}
+			e = e.Onext
+			if e == v.anEdge {
+				break
+			}
+		}
+	}
+}
+
+// euclideanRefiner 按三维欧氏距离细分，新顶点取边的中点
+type euclideanRefiner struct{}
+
+func (euclideanRefiner) length(a, b *vertex) float64 {
+	dx := float64(a.coords[0]) - float64(b.coords[0])
+	dy := float64(a.coords[1]) - float64(b.coords[1])
+	dz := float64(a.coords[2]) - float64(b.coords[2])
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
+
+func (euclideanRefiner) split(a, b, v *vertex) {
+	for i := range v.coords {
+		v.coords[i] = (a.coords[i] + b.coords[i]) / 2
+	}
+	v.s, v.t = (a.s+b.s)/2, (a.t+b.t)/2
+	v.idx = undef
+}
+
+// TesselateMaxEdgeLength 与Tesselate相同，但在网格上反复二分三角形的最长边，
+// 直到所有边的三维长度都不超过maxEdgeLength。相邻三角形同时拆分共享的边，
+// 因此结果中没有T形顶点；新顶点的Z沿边线性插值。maxEdgeLength必须为正数
+func TesselateMaxEdgeLength(contours []Contour, windingRule WindingRule, maxEdgeLength float32) ([]int, []Vertex, error) {
+	if !(maxEdgeLength > 0) || math.IsInf(float64(maxEdgeLength), 0) {
+		return nil, nil, fmt.Errorf("tesselator: invalid maximum edge length %v", maxEdgeLength)
+	}
+	t := &tesselator{refiner: euclideanRefiner{}, maxEdgeLength: float64(maxEdgeLength)}
+	return tesselateWith(t, contours, windingRule)
+}
@@ -0,0 +1,61 @@
+package tesselator
+
+import (
+	"math"
+	"testing"
+)
+
+// TestTesselateMaxEdgeLength 所有边不超过最大长度，面积不变，没有T形顶点
+func TestTesselateMaxEdgeLength(t *testing.T) {
+	contours := []Contour{squareContour(0, 0, 10), reversedContour(squareContour(3, 4, 2))}
+	// Z = X + 2Y 的斜面
+	for _, c := range contours {
+		for i := range c {
+			c[i].Z = c[i].X + 2*c[i].Y
+		}
+	}
+	indices, vertices, err := TesselateMaxEdgeLength(contours, WindingRuleOdd, 1.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPointTriangulation(t, indices, vertices, 96)
+
+	edges := map[[2]int]int{}
+	for i := 0; i < len(indices); i += 3 {
+		for k := 0; k < 3; k++ {
+			a, b := indices[i+k], indices[i+(k+1)%3]
+			p, q := vertices[a], vertices[b]
+			if l := math.Sqrt(float64((p.X-q.X)*(p.X-q.X) + (p.Y-q.Y)*(p.Y-q.Y) + (p.Z-q.Z)*(p.Z-q.Z))); l > 1.5+1e-4 {
+				t.Errorf("edge %v %v has length %g", p, q, l)
+			}
+			if a > b {
+				a, b = b, a
+			}
+			edges[[2]int{a, b}]++
+		}
+	}
+	for _, v := range vertices {
+		if math.Abs(float64(v.Z-v.X-2*v.Y)) > 1e-4 {
+			t.Errorf("vertex %v is not on the input plane", v)
+		}
+	}
+	// 只属于一个三角形的边都在外轮廓或洞上，其余的边恰好属于两个三角形
+	perimeter := 0.0
+	for e, n := range edges {
+		p, q := vertices[e[0]], vertices[e[1]]
+		switch n {
+		case 1:
+			perimeter += math.Hypot(float64(p.X-q.X), float64(p.Y-q.Y))
+		case 2:
+		default:
+			t.Errorf("edge %v %v is shared by %d triangles", p, q, n)
+		}
+	}
+	if math.Abs(perimeter-48) > 1e-3 {
+		t.Errorf("expected boundary length 48, got %g", perimeter)
+	}
+
+	if _, _, err := TesselateMaxEdgeLength(contours, WindingRuleOdd, 0); err == nil {
+		t.Error("expected an error for a zero edge length")
+	}
+}
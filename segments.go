@@ -0,0 +1,184 @@
+package tesselator
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Segment 两个端点之间的线段，求交时只使用XY坐标
+type Segment struct {
+	A, B Vertex
+}
+
+// SegmentIntersection 两条或更多线段相交或接触的位置，Segments为经过该点的线段在输入中的序号（升序）
+type SegmentIntersection struct {
+	Point    Vertex
+	Segments []int
+}
+
+// NodedSegment 在交点处打断后的一段线段，Source为它所属的输入线段的序号
+type NodedSegment struct {
+	Segment
+	Source int
+}
+
+// SegmentIntersections 求出线段之间所有的交点，包括交叉、端点落在其他线段上、共享端点
+// 以及共线重叠部分的两端。交叉点由扫描线求出，再找出经过每个点的所有线段：
+// 与线段的距离不超过坐标范围的百万分之一即视为经过。结果按X、Y排序
+func SegmentIntersections(segments []Segment) ([]SegmentIntersection, error) {
+	points, err := segmentNodes(segments)
+	if err != nil {
+		return nil, err
+	}
+	var result []SegmentIntersection
+	for _, n := range points {
+		if len(n.Segments) >= 2 {
+			result = append(result, n)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i].Point, result[j].Point
+		if a.X != b.X {
+			return a.X < b.X
+		}
+		return a.Y < b.Y
+	})
+	return result, nil
+}
+
+// NodeSegments 在所有交点处打断线段，返回的各段按输入线段的顺序排列，
+// 每条线段内从A到B排列。相交的线段在交点处使用完全相同的XY坐标，Z按所在线段线性插值。
+// 共线重叠的线段各自保留重叠的部分，长度为0的线段不产生输出
+func NodeSegments(segments []Segment) ([]NodedSegment, error) {
+	intersections, err := SegmentIntersections(segments)
+	if err != nil {
+		return nil, err
+	}
+	splits := make([][]Vertex, len(segments))
+	for _, x := range intersections {
+		for _, i := range x.Segments {
+			splits[i] = append(splits[i], x.Point)
+		}
+	}
+
+	var result []NodedSegment
+	for i, s := range segments {
+		dx, dy := float64(s.B.X-s.A.X), float64(s.B.Y-s.A.Y)
+		length := dx*dx + dy*dy
+		if length == 0 {
+			continue
+		}
+		param := func(v Vertex) float64 {
+			return (float64(v.X-s.A.X)*dx + float64(v.Y-s.A.Y)*dy) / length
+		}
+		points := splits[i]
+		sort.Slice(points, func(x, y int) bool { return param(points[x]) < param(points[y]) })
+
+		prev := s.A
+		for _, p := range append(points, s.B) {
+			if p.X == prev.X && p.Y == prev.Y {
+				continue
+			}
+			if p != s.B {
+				p.Z = s.A.Z + (s.B.Z-s.A.Z)*float32(math.Max(0, math.Min(1, param(p))))
+			}
+			result = append(result, NodedSegment{Segment{prev, p}, i})
+			prev = p
+		}
+	}
+	return result, nil
+}
+
+// segmentNodes 返回所有线段端点和扫描中记录的交点，以及经过每个点的线段。
+// 相距不超过容差的点合并为一个，优先保留线段的端点
+func segmentNodes(segments []Segment) ([]SegmentIntersection, error) {
+	lines := make([]Contour, len(segments))
+	for i, s := range segments {
+		for _, v := range []Vertex{s.A, s.B} {
+			if math.IsNaN(float64(v.X)) || math.IsInf(float64(v.X), 0) || math.IsNaN(float64(v.Y)) || math.IsInf(float64(v.Y), 0) {
+				return nil, fmt.Errorf("tesselator: segment %d has a non-finite coordinate", i)
+			}
+		}
+		lines[i] = Contour{s.A, s.B}
+	}
+	tolerance := validationTolerance(lines)
+
+	t := &tesselator{recordTouches: true, keepDegenerateContours: true}
+	for _, s := range segments {
+		if s.A.X != s.B.X || s.A.Y != s.B.Y {
+			addSegment(t, s)
+		}
+	}
+	if t.mesh != nil {
+		t.normal = [3]float{0, 0, 1}
+		t.windingRule = WindingRuleOdd
+		tessProjectPolygon(t)
+		tessComputeInterior(t)
+	}
+
+	grid := newPointGrid(tolerance)
+	var points []Vertex
+	for _, s := range segments {
+		for _, v := range []Vertex{s.A, s.B} {
+			if _, added := grid.snap(v); added {
+				points = append(points, v)
+			}
+		}
+	}
+	endpoints := len(points)
+	for _, c := range t.touches {
+		v := Vertex{X: float32(c[0]), Y: float32(c[1]), Z: float32(c[2])}
+		if _, added := grid.snap(v); added {
+			points = append(points, v)
+		}
+	}
+
+	index := newRingSegments(lines, tolerance)
+	result := make([]SegmentIntersection, len(points))
+	for k, p := range points {
+		result[k].Point = p
+		// 每条线段作为两条方向相反的边建立索引，同一线段可能出现两次
+		for _, seg := range index.near(p) {
+			result[k].Segments = append(result[k].Segments, seg.contour)
+		}
+		sort.Ints(result[k].Segments)
+		result[k].Segments = uniqueInts(result[k].Segments)
+		if ids := result[k].Segments; k >= endpoints && len(ids) >= 2 {
+			// 扫描以单精度计算交点，按输入坐标重新计算
+			if q, ok := lineIntersection(segments[ids[0]], segments[ids[1]]); ok && math.Hypot(float64(q.X-p.X), float64(q.Y-p.Y)) <= tolerance {
+				result[k].Point.X, result[k].Point.Y = q.X, q.Y
+			}
+		}
+	}
+	return result, nil
+}
+
+// lineIntersection 以双精度计算两条线段所在直线的交点，平行时返回false
+func lineIntersection(a, b Segment) (Vertex, bool) {
+	ax, ay := float64(a.A.X), float64(a.A.Y)
+	dx, dy := float64(a.B.X)-ax, float64(a.B.Y)-ay
+	ex, ey := float64(b.B.X-b.A.X), float64(b.B.Y-b.A.Y)
+	den := dx*ey - dy*ex
+	if den == 0 {
+		return Vertex{}, false
+	}
+	t := ((float64(b.A.X)-ax)*ey - (float64(b.A.Y)-ay)*ex) / den
+	return Vertex{X: float32(ax + t*dx), Y: float32(ay + t*dy)}, true
+}
+
+// addSegment 把线段作为一条孤立的边加入tesselator，两侧的环绕数不变
+func addSegment(t *tesselator, s Segment) {
+	addPolyline(t, Contour{s.A, s.B})
+}
+
+// uniqueInts 去除有序切片中的重复元素
+func uniqueInts(s []int) []int {
+	result := s[:0]
+	for i, v := range s {
+		if i == 0 || v != s[i-1] {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,134 @@
+package tesselator
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// segmentLength 线段在XY平面上的长度
+func segmentLength(s Segment) float64 {
+	return math.Hypot(float64(s.B.X-s.A.X), float64(s.B.Y-s.A.Y))
+}
+
+// TestSegmentIntersections 交叉、T形接触、共享端点和共线重叠
+func TestSegmentIntersections(t *testing.T) {
+	type found struct {
+		X, Y     float32
+		Segments []int
+	}
+	cases := []struct {
+		name     string
+		segments []Segment
+		expected []found
+	}{
+		{"cross", []Segment{{Vertex{X: 0, Y: 0}, Vertex{X: 4, Y: 4}}, {Vertex{X: 0, Y: 4}, Vertex{X: 4, Y: 0}}},
+			[]found{{2, 2, []int{0, 1}}}},
+		{"disjoint", []Segment{{Vertex{X: 0, Y: 0}, Vertex{X: 4, Y: 0}}, {Vertex{X: 0, Y: 1}, Vertex{X: 4, Y: 1}}}, nil},
+		{"t junction", []Segment{{Vertex{X: 0, Y: 0}, Vertex{X: 4, Y: 0}}, {Vertex{X: 2, Y: 0}, Vertex{X: 2, Y: 3}}},
+			[]found{{2, 0, []int{0, 1}}}},
+		{"shared endpoint", []Segment{{Vertex{X: 0, Y: 0}, Vertex{X: 4, Y: 0}}, {Vertex{X: 4, Y: 0}, Vertex{X: 4, Y: 3}}},
+			[]found{{4, 0, []int{0, 1}}}},
+		{"collinear overlap", []Segment{{Vertex{X: 0, Y: 0}, Vertex{X: 4, Y: 0}}, {Vertex{X: 2, Y: 0}, Vertex{X: 6, Y: 0}}},
+			[]found{{2, 0, []int{0, 1}}, {4, 0, []int{0, 1}}}},
+		{"three through a point", []Segment{
+			{Vertex{X: 0, Y: 0}, Vertex{X: 10, Y: 10}}, {Vertex{X: 0, Y: 10}, Vertex{X: 10, Y: 0}}, {Vertex{X: 5, Y: 0}, Vertex{X: 5, Y: 10}}},
+			[]found{{5, 5, []int{0, 1, 2}}}},
+	}
+	for _, c := range cases {
+		intersections, err := SegmentIntersections(c.segments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []found
+		for _, x := range intersections {
+			got = append(got, found{x.Point.X, x.Point.Y, x.Segments})
+		}
+		if !reflect.DeepEqual(got, c.expected) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, intersections)
+		}
+	}
+
+	r := rand.New(rand.NewSource(1))
+	var segments []Segment
+	for i := 0; i < 200; i++ {
+		segments = append(segments, Segment{
+			Vertex{X: float32(r.Float64() * 100), Y: float32(r.Float64() * 100)},
+			Vertex{X: float32(r.Float64() * 100), Y: float32(r.Float64() * 100)}})
+	}
+	intersections, err := SegmentIntersections(segments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reported := map[[2]int]bool{}
+	for _, x := range intersections {
+		for _, i := range x.Segments {
+			for _, j := range x.Segments {
+				reported[[2]int{i, j}] = true
+			}
+			if d := segmentDistance(x.Point, segments[i].A, segments[i].B); d > 1e-3 {
+				t.Errorf("intersection %v is %g away from segment %d", x.Point, d, i)
+			}
+		}
+	}
+	// 严格交叉的每一对线段都被报告
+	for i := range segments {
+		for j := i + 1; j < len(segments); j++ {
+			a, b := segments[i], segments[j]
+			side := func(s Segment, v Vertex) float64 {
+				return float64(s.B.X-s.A.X)*float64(v.Y-s.A.Y) - float64(s.B.Y-s.A.Y)*float64(v.X-s.A.X)
+			}
+			if side(a, b.A)*side(a, b.B) < 0 && side(b, a.A)*side(b, a.B) < 0 && !reported[[2]int{i, j}] {
+				t.Errorf("crossing of segments %d and %d is missing", i, j)
+			}
+		}
+	}
+
+	if _, err := SegmentIntersections([]Segment{{Vertex{X: float32(math.NaN())}, Vertex{X: 1}}}); err == nil {
+		t.Error("non-finite coordinates should be rejected")
+	}
+}
+
+// TestNodeSegments 网格状的线段在每个交点处打断，总长度不变
+func TestNodeSegments(t *testing.T) {
+	const n = 10
+	var segments []Segment
+	for i := 0; i < n; i++ {
+		p := float32(i) + 0.5
+		segments = append(segments, Segment{Vertex{X: 0, Y: p}, Vertex{X: n, Y: p}})
+		segments = append(segments, Segment{Vertex{X: p, Y: 0, Z: 0}, Vertex{X: p, Y: n, Z: n}})
+	}
+	noded, err := NodeSegments(segments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(noded) != len(segments)*(n+1) {
+		t.Errorf("expected %d pieces, got %d", len(segments)*(n+1), len(noded))
+	}
+	length := make([]float64, len(segments))
+	for _, s := range noded {
+		length[s.Source] += segmentLength(s.Segment)
+		if s.Source%2 == 1 && s.A.Z != s.A.Y {
+			t.Errorf("z of %v should be interpolated along segment %d", s.A, s.Source)
+		}
+	}
+	for i, s := range segments {
+		if math.Abs(length[i]-segmentLength(s)) > 1e-4 {
+			t.Errorf("pieces of segment %d have length %g, expected %g", i, length[i], segmentLength(s))
+		}
+	}
+
+	// 共线重叠的线段都在对方的端点处打断
+	noded, err = NodeSegments([]Segment{{Vertex{X: 0, Y: 0}, Vertex{X: 4, Y: 0}}, {Vertex{X: 6, Y: 0}, Vertex{X: 2, Y: 0}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []NodedSegment{
+		{Segment{Vertex{X: 0}, Vertex{X: 2}}, 0}, {Segment{Vertex{X: 2}, Vertex{X: 4}}, 0},
+		{Segment{Vertex{X: 6}, Vertex{X: 4}}, 1}, {Segment{Vertex{X: 4}, Vertex{X: 2}}, 1},
+	}
+	if !reflect.DeepEqual(noded, expected) {
+		t.Errorf("expected %v, got %v", expected, noded)
+	}
+}
@@ -0,0 +1,1036 @@
+package tesselator
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// SkeletonNode 直骨架的节点：输入顶点（Distance为0）或波前事件发生的位置。
+// Distance为波前到达该点的时间；各边权重为1时即该点到其所在各面对应输入边所在直线的距离
+type SkeletonNode struct {
+	Position Vertex
+	Distance float32
+}
+
+// SkeletonArc 直骨架的弧，由一个波前顶点从From节点移动到To节点扫出。
+// Left和Right为弧左右两侧的面（即输入边）的序号
+type SkeletonArc struct {
+	From, To    int
+	Left, Right int
+}
+
+// SkeletonFace 一条输入边在收缩过程中扫过的区域。Nodes为逆时针排列的节点序号，
+// 前两个节点为该输入边的起点和终点
+type SkeletonFace struct {
+	Nodes []int
+}
+
+// Skeleton 多边形（可带洞）的直骨架。
+// Contours为规整后的输入：外轮廓逆时针、洞顺时针，自相交和重叠已按环绕规则消除。
+// 前len(输入顶点)个节点依次为Contours中的顶点；输入边按轮廓依次编号，
+// 第k条边从第k个顶点指向同一轮廓的下一个顶点，Faces[k]为它扫过的面，Weights[k]为它平移的速度
+type Skeleton struct {
+	Contours []Contour
+	Weights  []float32
+	Nodes    []SkeletonNode
+	Arcs     []SkeletonArc
+	Faces    []SkeletonFace
+}
+
+// StraightSkeleton 计算按环绕规则确定的区域的直骨架。
+// 所有边以单位速度同时向内平移，顶点沿角平分线移动；边长收缩为0（边事件）、
+// 凹顶点撞上其他边（分裂事件）或多个顶点相遇时记录骨架节点，同一时刻同一位置的
+// 多个事件（如对称多边形的多条边同时收缩）合并为一个节点一起处理；输入坐标的舍入使这些事件略有先后时，
+// 时间和位置都只相差2e-5倍包围盒尺寸以内的节点在输出时合并。
+// 波前顶点扫出的弧直接连接在与剖分相同的半边网格上，收缩结束时网格的每个面就是一条输入边扫过的区域。
+// 事件按时间放在优先队列中，只使用XY坐标，复杂度约为O((n+r·n)·log n)，r为凹顶点个数
+func StraightSkeleton(contours []Contour, windingRule WindingRule) (*Skeleton, error) {
+	return StraightSkeletonWeighted(contours, windingRule, nil)
+}
+
+// StraightSkeletonWeighted 计算加权直骨架：规整后从a到b的输入边以weight(a, b)的速度向内平移，
+// weight为nil时所有边的速度都为1。权重不能为负。Roof中该边屋面的坡度为slope/weight：
+// 权重为0的边不移动，相邻的屋面在它上方相交，得到竖直的山墙（如双坡屋顶的两端）
+func StraightSkeletonWeighted(contours []Contour, windingRule WindingRule, weight func(a, b Vertex) float32) (*Skeleton, error) {
+	boundary, err := boundaryContours(contours, windingRule)
+	if err != nil {
+		return nil, err
+	}
+	s := &Skeleton{}
+	tolerance := 1e-7 * contoursExtent(boundary)
+	for _, c := range boundary {
+		if c = cleanSkeletonContour(c, tolerance); len(c) >= 3 {
+			s.Contours = append(s.Contours, c)
+		}
+	}
+	for _, c := range s.Contours {
+		for k, a := range c {
+			w := float32(1)
+			if weight != nil {
+				b := c[(k+1)%len(c)]
+				if w = weight(a, b); !(w >= 0) || math.IsInf(float64(w), 1) {
+					return nil, fmt.Errorf("straight skeleton: invalid weight %v for edge %v %v", w, a, b)
+				}
+			}
+			s.Weights = append(s.Weights, w)
+		}
+	}
+	b, initial := newSkeletonBuilder(s.Contours, s.Weights)
+	if err := b.run(initial); err != nil {
+		return nil, err
+	}
+	if err := b.output(s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Roof 按坡度把骨架节点抬升为高度Distance×slope，生成由各面三角剖分得到的屋顶网格。
+// 权重都为1时为四坡屋顶，权重为0的边上是竖直的山墙。相邻面共享节点顶点，
+// 三角形的法线朝向屋顶外侧（坡面从上方看为逆时针）
+func (s *Skeleton) Roof(slope float32) ([]int, []Vertex, error) {
+	vertices := make([]Vertex, len(s.Nodes))
+	for i, n := range s.Nodes {
+		vertices[i] = Vertex{X: n.Position.X, Y: n.Position.Y, Z: n.Distance * slope}
+	}
+	var indices []int
+	for f, face := range s.Faces {
+		if len(face.Nodes) < 3 {
+			continue
+		}
+		// 在以输入边方向和时间为坐标轴的平面上剖分：山墙在XY平面上的投影退化为线段，
+		// 在这个平面上仍是逆时针的多边形
+		a, b := s.Nodes[face.Nodes[0]].Position, s.Nodes[face.Nodes[1]].Position
+		dx, dy := float64(b.X-a.X), float64(b.Y-a.Y)
+		length := math.Hypot(dx, dy)
+		dx, dy = dx/length, dy/length
+		w := 1.0
+		if f < len(s.Weights) {
+			w = float64(s.Weights[f])
+		}
+		contour := make(Contour, len(face.Nodes))
+		lookup := make(map[[2]float32]int, len(face.Nodes))
+		for i, n := range face.Nodes {
+			p := s.Nodes[n]
+			u := dx*float64(p.Position.X-a.X) + dy*float64(p.Position.Y-a.Y)
+			contour[i] = Vertex{X: float32(u), Y: p.Distance}
+			lookup[[2]float32{contour[i].X, contour[i].Y}] = n
+		}
+		faceIndices, faceVertices, err := Tesselate([]Contour{contour}, WindingRuleNonzero)
+		if err != nil {
+			return nil, nil, fmt.Errorf("roof face %d: %v", f, err)
+		}
+		// 剖分新增的顶点由沿边的位置和时间换算回平面坐标和高度
+		mapping := make([]int, len(faceVertices))
+		for i, v := range faceVertices {
+			n, ok := lookup[[2]float32{v.X, v.Y}]
+			if !ok {
+				u, t := float64(v.X), float64(v.Y)*w
+				n = len(vertices)
+				vertices = append(vertices, Vertex{
+					X: a.X + float32(dx*u-dy*t),
+					Y: a.Y + float32(dy*u+dx*t),
+					Z: v.Y * slope,
+				})
+			}
+			mapping[i] = n
+		}
+		for _, i := range faceIndices {
+			indices = append(indices, mapping[i])
+		}
+	}
+	return indices, vertices, nil
+}
+
+// contoursExtent 返回轮廓包围盒的最大边长
+func contoursExtent(contours []Contour) float64 {
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, c := range contours {
+		for _, v := range c {
+			minX, maxX = math.Min(minX, float64(v.X)), math.Max(maxX, float64(v.X))
+			minY, maxY = math.Min(minY, float64(v.Y)), math.Max(maxY, float64(v.Y))
+		}
+	}
+	return math.Max(maxX-minX, maxY-minY)
+}
+
+// cleanSkeletonContour 合并距离不超过tolerance的相邻顶点，并去掉宽度不超过tolerance的尖刺
+func cleanSkeletonContour(c Contour, tolerance float64) Contour {
+	near := func(a, b Vertex) bool {
+		return math.Abs(float64(a.X-b.X)) <= tolerance && math.Abs(float64(a.Y-b.Y)) <= tolerance
+	}
+	result := make(Contour, 0, len(c))
+	for _, v := range c {
+		v.Z = 0
+		result = append(result, v)
+		for changed := true; changed && len(result) >= 2; {
+			changed = false
+			n := len(result)
+			if near(result[n-1], result[n-2]) {
+				result = result[:n-1]
+				changed = true
+			} else if n >= 3 && isSpike(result[n-3], result[n-2], result[n-1], tolerance) {
+				result = append(result[:n-2], result[n-1])
+				changed = true
+			}
+		}
+	}
+	// 处理首尾相接处
+	for changed := true; changed && len(result) >= 3; {
+		changed = false
+		n := len(result)
+		if near(result[n-1], result[0]) {
+			result = result[:n-1]
+			changed = true
+		} else if isSpike(result[n-1], result[0], result[1], tolerance) {
+			result = result[1:]
+			changed = true
+		} else if isSpike(result[n-2], result[n-1], result[0], tolerance) {
+			result = result[:n-1]
+			changed = true
+		}
+	}
+	return result
+}
+
+// isSpike 判断b处是否为折返的尖刺：两条边方向相反，且较短边的端点到较长边所在直线的距离不超过tolerance
+func isSpike(a, b, c Vertex, tolerance float64) bool {
+	ux, uy := float64(b.X-a.X), float64(b.Y-a.Y)
+	vx, vy := float64(c.X-b.X), float64(c.Y-b.Y)
+	l := math.Max(math.Hypot(ux, uy), math.Hypot(vx, vy))
+	return ux*vx+uy*vy < 0 && math.Abs(ux*vy-uy*vx) <= tolerance*l
+}
+
+// skelPoint 直骨架计算使用的双精度点
+type skelPoint struct {
+	x, y float64
+}
+
+func (p skelPoint) add(q skelPoint, s float64) skelPoint {
+	return skelPoint{p.x + q.x*s, p.y + q.y*s}
+}
+
+func (p skelPoint) sub(q skelPoint) skelPoint {
+	return skelPoint{p.x - q.x, p.y - q.y}
+}
+
+func (p skelPoint) dot(q skelPoint) float64 {
+	return p.x*q.x + p.y*q.y
+}
+
+func (p skelPoint) cross(q skelPoint) float64 {
+	return p.x*q.y - p.y*q.x
+}
+
+// skelLine 输入边所在的直线：起点、单位方向、指向内部的单位法线和平移速度
+type skelLine struct {
+	a, d, n skelPoint
+	w       float64
+}
+
+// offset 返回直线在时间t平移后的位置c，平移后的直线为p·n = c
+func (l skelLine) offset(t float64) float64 {
+	return l.a.dot(l.n) + l.w*t
+}
+
+// wavefrontVertex 波前多边形的顶点，位于in和out两条边平移后的直线的交点上，
+// 在时间t0位于origin，之后以速度vel移动。next方向的边为out。
+// arc为网格中从出发节点指向该顶点的半边，终点随顶点移动，到达下一个节点时才固定；
+// 分裂事件在边上插入的临时顶点没有arc
+type wavefrontVertex struct {
+	in, out    int
+	origin     skelPoint
+	t0         float64
+	vel        skelPoint
+	pos        skelPoint
+	arc        *halfEdge
+	prev, next *wavefrontVertex
+	alive      bool
+	stuck      bool
+	splits     skeletonSplits // 凹顶点到达其他直线的时间，尚未放入事件队列
+}
+
+func (v *wavefrontVertex) at(t float64) skelPoint {
+	return v.origin.add(v.vel, t-v.t0)
+}
+
+// skeletonEvent 边事件（v与其后的顶点w相遇）或分裂事件（凹顶点v到达直线line）。
+// 事件放入队列后涉及的顶点可能已被其他事件结束，取出时再检查是否仍然有效
+type skeletonEvent struct {
+	t    float64
+	v, w *wavefrontVertex
+	line int
+}
+
+// skeletonEvents 按时间排序的事件队列
+type skeletonEvents []skeletonEvent
+
+func (q skeletonEvents) Len() int            { return len(q) }
+func (q skeletonEvents) Less(i, j int) bool  { return q[i].t < q[j].t }
+func (q skeletonEvents) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *skeletonEvents) Push(x interface{}) { *q = append(*q, x.(skeletonEvent)) }
+
+func (q *skeletonEvents) Pop() interface{} {
+	old := *q
+	x := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return x
+}
+
+// skeletonSplit 凹顶点到达直线line的时间
+type skeletonSplit struct {
+	t    float64
+	line int
+}
+
+// skeletonSplits 一个凹顶点的分裂事件候选，按时间排序的堆。每次只把最早的一个放入事件队列，
+// 顶点结束后其余的候选不再处理
+type skeletonSplits []skeletonSplit
+
+func (q skeletonSplits) Len() int            { return len(q) }
+func (q skeletonSplits) Less(i, j int) bool  { return q[i].t < q[j].t }
+func (q skeletonSplits) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *skeletonSplits) Push(x interface{}) { *q = append(*q, x.(skeletonSplit)) }
+
+func (q *skeletonSplits) Pop() interface{} {
+	old := *q
+	x := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return x
+}
+
+type skeletonBuilder struct {
+	mesh   *mesh
+	lines  []skelLine
+	edges  []*halfEdge // 输入边，左侧为它扫过的面
+	nodes  []skelPoint
+	times  []float64
+	onLine [][]*wavefrontVertex // 以各直线为out的顶点，即该直线上各段波前边的起点
+	events skeletonEvents
+	alive  int // 存活的波前顶点个数
+	budget int // 还可以创建的波前顶点个数，防止退化输入导致死循环
+	eps    float64
+	snap   float64 // 合并骨架节点的容差，远大于eps，用于吸收输入坐标舍入造成的事件时间差
+	now    float64
+}
+
+// newSkeletonBuilder 与tessAddContour一样把轮廓加入网格，并为每个输入顶点创建波前顶点
+// 和一条从该顶点伸向内部的弧
+func newSkeletonBuilder(contours []Contour, weights []float32) (*skeletonBuilder, []*wavefrontVertex) {
+	extent := contoursExtent(contours)
+	b := &skeletonBuilder{mesh: tessMeshNewMesh(), eps: 1e-7 * extent, snap: 2e-5 * extent}
+	var bases []int
+	for _, c := range contours {
+		base, m := len(b.lines), len(c)
+		bases = append(bases, base)
+		var e *halfEdge
+		for k, v := range c {
+			p := skelPoint{float64(v.X), float64(v.Y)}
+			q := skelPoint{float64(c[(k+1)%m].X), float64(c[(k+1)%m].Y)}
+			d := q.sub(p)
+			l := math.Hypot(d.x, d.y)
+			d = skelPoint{d.x / l, d.y / l}
+			b.lines = append(b.lines, skelLine{a: p, d: d, n: skelPoint{-d.y, d.x}, w: float64(weights[base+k])})
+			b.onLine = append(b.onLine, nil)
+
+			// 第k条半边从第k个顶点指向下一个顶点，左侧为内部
+			if e == nil {
+				e = tessMeshMakeEdge(b.mesh)
+				tessMeshSplice(b.mesh, e, e.Sym)
+			} else {
+				tessMeshSplitEdge(b.mesh, e)
+				e = e.Lnext
+			}
+			e.Org.idx = b.addNode(p)
+			b.edges = append(b.edges, e)
+		}
+	}
+	// 所有直线都加入后才能计算凹顶点的分裂事件
+	var initial []*wavefrontVertex
+	for i, c := range contours {
+		base, m := bases[i], len(c)
+		vs := make([]*wavefrontVertex, m)
+		for k := range c {
+			in := base + (k+m-1)%m
+			vs[k] = b.newVertex(in, base+k, b.nodes[base+k])
+			b.attach(vs[k], b.edges[in])
+		}
+		for k, v := range vs {
+			v.next, vs[(k+1)%m].prev = vs[(k+1)%m], v
+		}
+		initial = append(initial, vs...)
+	}
+	b.budget = 16*len(initial) + 64
+	return b, initial
+}
+
+func (b *skeletonBuilder) addNode(p skelPoint) index {
+	b.nodes = append(b.nodes, p)
+	b.times = append(b.times, b.now)
+	return index(len(b.nodes) - 1)
+}
+
+// newVertex 在当前时刻创建位于origin的波前顶点，凹顶点同时把最早的分裂事件放入队列
+func (b *skeletonBuilder) newVertex(in, out int, origin skelPoint) *wavefrontVertex {
+	v := &wavefrontVertex{in: in, out: out, origin: origin, t0: b.now, pos: origin, alive: true}
+	b.velocity(v)
+	b.onLine[out] = append(b.onLine[out], v)
+	b.alive++
+	b.budget--
+	if b.reflex(v) {
+		v.splits = b.splitCandidates(v)
+		b.pushSplit(v)
+	}
+	return v
+}
+
+// attach 在eOrg的终点（v出发的节点）处、紧接eOrg之后插入v的弧
+func (b *skeletonBuilder) attach(v *wavefrontVertex, eOrg *halfEdge) {
+	v.arc = tessMeshAddEdgeVertex(b.mesh, eOrg)
+	v.arc.dst().idx = undef
+}
+
+// kill 结束顶点，临时顶点不计入存活个数
+func (b *skeletonBuilder) kill(v *wavefrontVertex) {
+	if v.alive && v.arc != nil {
+		b.alive--
+	}
+	v.alive = false
+}
+
+// velocity 求顶点的速度v，使v·n_in = w_in、v·n_out = w_out，即顶点保持在两条平移后的直线上。
+// 权重相同时v = w(n_in+n_out)/(1+n_in·n_out)。两条边接近反向平行时速度无定义，标记为stuck
+func (b *skeletonBuilder) velocity(v *wavefrontVertex) {
+	l1, l2 := b.lines[v.in], b.lines[v.out]
+	na, nb := l1.n, l2.n
+	k := 1 + na.dot(nb)
+	v.stuck = k < 1e-14
+	switch {
+	case v.stuck:
+		v.vel = skelPoint{}
+	case l1.w == l2.w:
+		v.vel = skelPoint{l1.w * (na.x + nb.x) / k, l1.w * (na.y + nb.y) / k}
+	default:
+		det := na.cross(nb)
+		if math.Abs(det) < 1e-9 {
+			// 同向共线而速度不同的两条边，取平均速度沿法线移动
+			w := (l1.w + l2.w) / 2
+			v.vel = skelPoint{w * (na.x + nb.x) / k, w * (na.y + nb.y) / k}
+			return
+		}
+		v.vel = skelPoint{(l1.w*nb.y - l2.w*na.y) / det, (na.x*l2.w - nb.x*l1.w) / det}
+	}
+}
+
+// meet 返回in和out两条边在当前时刻平移后的直线的交点，使新顶点不继承聚类位置的误差。
+// 两条直线接近平行或交点偏离p超过容差时返回p
+func (b *skeletonBuilder) meet(in, out int, p skelPoint) skelPoint {
+	l1, l2 := b.lines[in], b.lines[out]
+	det := l1.n.cross(l2.n)
+	if math.Abs(det) < 1e-6 {
+		return p
+	}
+	c1, c2 := l1.offset(b.now), l2.offset(b.now)
+	q := skelPoint{(c1*l2.n.y - c2*l1.n.y) / det, (l1.n.x*c2 - l2.n.x*c1) / det}
+	if d := q.sub(p); math.Abs(d.x) > 10*b.eps || math.Abs(d.y) > 10*b.eps {
+		return p
+	}
+	return q
+}
+
+// reflex 判断顶点是否为凹顶点（内部在左侧时向右转）
+func (b *skeletonBuilder) reflex(v *wavefrontVertex) bool {
+	return b.lines[v.in].d.cross(b.lines[v.out].d) < 0
+}
+
+// splitCandidates 返回凹顶点v到达其他各条边平移后的直线的时间。v只可能在其中某个时刻
+// 撞上该直线上的一段波前边，是否确实撞上在事件取出时检查
+func (b *skeletonBuilder) splitCandidates(v *wavefrontVertex) skeletonSplits {
+	var result skeletonSplits
+	for i, line := range b.lines {
+		if i == v.in || i == v.out {
+			continue
+		}
+		k := line.w - v.vel.dot(line.n)
+		if k <= 1e-12 {
+			continue
+		}
+		// 当前已在直线上的接触在产生v的那批事件中处理
+		c := v.pos.dot(line.n) - line.offset(b.now)
+		if c <= b.eps {
+			continue
+		}
+		result = append(result, skeletonSplit{b.now + c/k, i})
+	}
+	heap.Init(&result)
+	return result
+}
+
+// pushSplit 把v的下一个分裂事件放入队列
+func (b *skeletonBuilder) pushSplit(v *wavefrontVertex) {
+	if len(v.splits) > 0 {
+		s := heap.Pop(&v.splits).(skeletonSplit)
+		heap.Push(&b.events, skeletonEvent{t: s.t, v: v, line: s.line})
+	}
+}
+
+// scheduleEdge 计算v与其后的顶点之间的边收缩为0的时间
+func (b *skeletonBuilder) scheduleEdge(v *wavefrontVertex) {
+	w := v.next
+	if !v.alive || !w.alive || w == v {
+		return
+	}
+	d := b.lines[v.out].d
+	s := w.at(b.now).sub(v.at(b.now)).dot(d)
+	rate := w.vel.sub(v.vel).dot(d)
+	switch {
+	case s <= b.eps:
+		heap.Push(&b.events, skeletonEvent{t: b.now, v: v, w: w, line: -1})
+	case rate < 0:
+		heap.Push(&b.events, skeletonEvent{t: b.now - s/rate, v: v, w: w, line: -1})
+	}
+
+}
+
+// splitTarget 返回直线line上在时间t包含v所在位置的一段波前边的起点，顺便移除已结束的起点
+func (b *skeletonBuilder) splitTarget(v *wavefrontVertex, line int, t float64) *wavefrontVertex {
+	h := v.at(t)
+	d := b.lines[line].d
+	var found *wavefrontVertex
+	live := b.onLine[line][:0]
+	for _, u := range b.onLine[line] {
+		if !u.alive {
+			continue
+		}
+		live = append(live, u)
+		if found != nil || u == v || u.next == v {
+			continue
+		}
+		p := u.at(t)
+		if s := h.sub(p).dot(d); s >= -b.eps && s <= u.next.at(t).sub(p).dot(d)+b.eps {
+			found = u
+		}
+	}
+	b.onLine[line] = live
+	return found
+}
+
+// run 推进波前直到所有波前多边形都收缩消失
+func (b *skeletonBuilder) run(initial []*wavefrontVertex) error {
+	// 先处理输入中重合的顶点（如只在一点相接的两个区域），此时的节点就是输入顶点
+	var created []*wavefrontVertex
+	for _, c := range b.clusters(initial) {
+		created = append(created, b.processCluster(c, c[0].pos)...)
+	}
+	b.activate(append(initial, created...))
+	for b.alive > 0 {
+		if len(b.events) == 0 {
+			return fmt.Errorf("straight skeleton: wavefront did not collapse")
+		}
+		if b.budget < 0 {
+			return fmt.Errorf("straight skeleton: too many events")
+		}
+		b.step()
+	}
+	return nil
+}
+
+// step 取出时间最早的一批事件，把涉及的顶点推进到该时刻，在被撞上的边内部插入临时顶点，
+// 再把位置重合的顶点分组处理。同一时刻同一位置的多个事件因此合并为一个节点
+func (b *skeletonBuilder) step() {
+	t := b.events[0].t
+	var cands []*wavefrontVertex
+	seen := map[*wavefrontVertex]bool{}
+	add := func(vs ...*wavefrontVertex) {
+		for _, v := range vs {
+			if !seen[v] {
+				seen[v] = true
+				cands = append(cands, v)
+			}
+		}
+	}
+	type hit struct {
+		u, v *wavefrontVertex
+	}
+	var hits []hit
+	var later []skeletonEvent
+	for len(b.events) > 0 && b.events[0].t <= t+b.eps {
+		e := heap.Pop(&b.events).(skeletonEvent)
+		if e.line < 0 {
+			if e.v.alive && e.w.alive && e.v.next == e.w {
+				add(e.v, e.w)
+			}
+			continue
+		}
+		if !e.v.alive {
+			continue
+		}
+		b.pushSplit(e.v)
+		if u := b.splitTarget(e.v, e.line, e.t); u != nil {
+			add(e.v, u, u.next)
+			hits = append(hits, hit{u, e.v})
+			if e.t > t {
+				later = append(later, e)
+			}
+		}
+	}
+	b.now = t
+	for _, v := range cands {
+		v.pos = v.at(t)
+	}
+
+	// 顶点落在其他边内部时，在边上插入临时顶点，与该顶点一起处理
+	type split struct {
+		v *wavefrontVertex
+		s float64
+	}
+	onEdge := map[*wavefrontVertex][]split{}
+	var starts []*wavefrontVertex
+	for _, h := range hits {
+		d := b.lines[h.u.out].d
+		s := h.v.pos.sub(h.u.pos).dot(d)
+		if s <= b.eps || s >= h.u.next.pos.sub(h.u.pos).dot(d)-b.eps {
+			continue
+		}
+		if onEdge[h.u] == nil {
+			starts = append(starts, h.u)
+		}
+		onEdge[h.u] = append(onEdge[h.u], split{h.v, s})
+	}
+	for _, u := range starts {
+		ss := onEdge[u]
+		sort.Slice(ss, func(i, j int) bool { return ss[i].s < ss[j].s })
+		prev, next := u, u.next
+		for i, sp := range ss {
+			if i > 0 && sp.s-ss[i-1].s <= b.eps {
+				continue
+			}
+			tmp := &wavefrontVertex{in: u.out, out: u.out, pos: sp.v.pos, alive: true}
+			tmp.prev, prev.next = prev, tmp
+			prev = tmp
+			cands = append(cands, tmp)
+		}
+		prev.next, next.prev = next, prev
+	}
+
+	var created []*wavefrontVertex
+	for _, c := range b.clusters(cands) {
+		created = append(created, b.processCluster(c, c[0].pos)...)
+	}
+	// 时间略晚于t的事件在t时可能还没有发生：顶点仍然存活时重新计算边事件，分裂事件放回队列
+	for _, e := range later {
+		if e.v.alive {
+			heap.Push(&b.events, e)
+		}
+	}
+	b.activate(append(created, cands...))
+}
+
+// clusters 返回位置重合的顶点组（至少两个顶点）；一条边两端的距离可以忽略时两端也视为重合
+func (b *skeletonBuilder) clusters(vs []*wavefrontVertex) [][]*wavefrontVertex {
+	parent := make([]int, len(vs))
+	for i := range parent {
+		parent[i] = i
+	}
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+	order := make([]int, len(vs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return vs[order[i]].pos.x < vs[order[j]].pos.x })
+	for i, a := range order {
+		for _, c := range order[i+1:] {
+			if vs[c].pos.x-vs[a].pos.x > b.eps {
+				break
+			}
+			if math.Abs(vs[c].pos.y-vs[a].pos.y) <= b.eps {
+				parent[find(a)] = find(c)
+			}
+		}
+	}
+	index := make(map[*wavefrontVertex]int, len(vs))
+	for i, v := range vs {
+		index[v] = i
+	}
+	for i, v := range vs {
+		if j, ok := index[v.next]; ok && v.next.pos.sub(v.pos).dot(b.lines[v.out].d) <= b.eps {
+			parent[find(i)] = find(j)
+		}
+	}
+	groups := map[int][]*wavefrontVertex{}
+	var roots []int
+	for i, v := range vs {
+		r := find(i)
+		if groups[r] == nil {
+			roots = append(roots, r)
+		}
+		groups[r] = append(groups[r], v)
+	}
+	var result [][]*wavefrontVertex
+	for _, r := range roots {
+		if len(groups[r]) >= 2 {
+			result = append(result, groups[r])
+		}
+	}
+	return result
+}
+
+// processCluster 结束位于同一点p的一组顶点，并重新连接经过该点的波前边。
+// 两端都在该点的边已收缩为0，被丢弃；其余的边按角度排序，每条到达该点的边
+// 与顺时针方向的下一条离开该点的边相连（二者之间为内部），在该点产生新的顶点。
+// 网格中各顶点的弧的终点合并为p处的节点，新顶点的弧插入到相邻的弧之间。
+// 返回新顶点和重新连接的顶点
+func (b *skeletonBuilder) processCluster(members []*wavefrontVertex, p skelPoint) []*wavefrontVertex {
+	in := make(map[*wavefrontVertex]bool, len(members))
+	hasArc := false
+	for _, m := range members {
+		in[m] = true
+		hasArc = hasArc || m.arc != nil
+	}
+	if !hasArc {
+		return nil
+	}
+
+	type end struct {
+		line     int
+		v        *wavefrontVertex // 簇外的相邻顶点
+		m        *wavefrontVertex // 该端所在的簇内顶点
+		angle    float64
+		incoming bool
+	}
+	var ends []end
+	for _, m := range members {
+		if !in[m.prev] {
+			d := b.lines[m.in].d
+			ends = append(ends, end{m.in, m.prev, m, math.Atan2(-d.y, -d.x), true})
+		}
+		if !in[m.next] {
+			d := b.lines[m.out].d
+			ends = append(ends, end{m.out, m.next, m, math.Atan2(d.y, d.x), false})
+		}
+	}
+	for _, m := range members {
+		b.kill(m)
+	}
+	// 按顺时针排序，角度相同时到达的边在前，使收缩为0宽度的区域直接闭合
+	sort.SliceStable(ends, func(i, j int) bool {
+		if math.Abs(ends[i].angle-ends[j].angle) > 1e-12 {
+			return ends[i].angle > ends[j].angle
+		}
+		return ends[i].incoming && !ends[j].incoming
+	})
+
+	var touched []*wavefrontVertex
+	fresh := map[int]*wavefrontVertex{}
+	pair := map[int]int{}
+	used := make([]bool, len(ends))
+	for i, e := range ends {
+		if !e.incoming {
+			continue
+		}
+		for k := 1; k < len(ends); k++ {
+			j := (i + k) % len(ends)
+			if ends[j].incoming || used[j] {
+				continue
+			}
+			used[j] = true
+			pair[i] = j
+			from, to := e.v, ends[j].v
+			touched = append(touched, from, to)
+			if e.line == ends[j].line {
+				// 同一条边的两段，直接接回
+				from.next, to.prev = to, from
+				break
+			}
+			v := b.newVertex(e.line, ends[j].line, b.meet(e.line, ends[j].line, p))
+			from.next, v.prev = v, from
+			v.next, to.prev = to, v
+			fresh[i] = v
+			touched = append(touched, v)
+			break
+		}
+	}
+
+	// 按面的邻接关系排列p周围的弧（顺时针）：到达p的一串簇内顶点的弧之后，是其中第一个顶点的
+	// 到达边配对产生的新顶点的弧，再接着是配对的离开边所在的那串簇内顶点。
+	// 几个区域只在p相接时得到多个环，各自在网格中形成一个顶点，它们对应同一个节点
+	inEnd := map[*wavefrontVertex]int{}
+	for i, e := range ends {
+		if e.incoming {
+			inEnd[e.m] = i
+		}
+	}
+	emitted := map[*wavefrontVertex]bool{}
+	var rings [][]*wavefrontVertex
+	var ring []*wavefrontVertex
+	emit := func(m *wavefrontVertex) {
+		emitted[m] = true
+		if m.arc != nil {
+			ring = append(ring, m)
+		}
+	}
+	visited := make([]bool, len(ends))
+	for start, e := range ends {
+		if !e.incoming || visited[start] {
+			continue
+		}
+		ring = nil
+		for i, ok := start, true; ok && !visited[i]; {
+			visited[i] = true
+			var chain []*wavefrontVertex
+			for m := ends[i].m; in[m] && !emitted[m]; m = m.next {
+				chain = append(chain, m)
+				emitted[m] = true
+			}
+			for k := len(chain) - 1; k >= 0; k-- {
+				emit(chain[k])
+			}
+			if v := fresh[i]; v != nil {
+				ring = append(ring, v)
+			}
+			var j int
+			if j, ok = pair[i]; ok {
+				m := ends[j].m
+				for in[m.prev] {
+					m = m.prev
+				}
+				i, ok = inEnd[m]
+			}
+		}
+		rings = append(rings, ring)
+	}
+	// 整圈都在簇内的波前多边形
+	for _, m := range members {
+		if !emitted[m] {
+			ring = nil
+			for ; !emitted[m]; m = m.prev {
+				emit(m)
+			}
+			rings = append(rings, ring)
+		}
+	}
+
+	isNew := make(map[*wavefrontVertex]bool, len(fresh))
+	for _, v := range fresh {
+		isNew[v] = true
+	}
+	// 没有已有弧的环（只由临时顶点组成）无法定位，并入第一个有弧的环
+	var orphans []*wavefrontVertex
+	valid := rings[:0]
+	for _, r := range rings {
+		hasArc := false
+		for _, v := range r {
+			hasArc = hasArc || !isNew[v]
+		}
+		if hasArc {
+			valid = append(valid, r)
+		} else {
+			orphans = append(orphans, r...)
+		}
+	}
+	valid[0] = append(valid[0], orphans...)
+
+	node := b.addNode(p)
+	for _, r := range valid {
+		b.joinArcs(r, node, isNew)
+	}
+	return touched
+}
+
+// joinArcs 按顺时针顺序排列的一环弧在网格中汇聚为一个顶点：先按逆时针顺序合并已有弧的终点，
+// 再从后向前把新顶点的弧插入到逆时针方向的下一条弧之前
+func (b *skeletonBuilder) joinArcs(ring []*wavefrontVertex, node index, isNew map[*wavefrontVertex]bool) {
+	n := len(ring)
+	for i := 0; i < n/2; i++ {
+		ring[i], ring[n-1-i] = ring[n-1-i], ring[i]
+	}
+	outgoing := func(v *wavefrontVertex) *halfEdge {
+		if isNew[v] {
+			return v.arc
+		}
+		return v.arc.Sym
+	}
+	start := 0
+	for isNew[ring[start]] {
+		start++
+	}
+	// 合并时保留h的终点并新建prev一侧的面，即刚闭合的面，而不是遍历尚未闭合的大面
+	var prev *halfEdge
+	for k := 0; k < n; k++ {
+		v := ring[(start+k)%n]
+		if isNew[v] {
+			continue
+		}
+		h := v.arc.Sym
+		h.Org.idx = node
+		if prev != nil {
+			tessMeshSplice(b.mesh, h, prev)
+		}
+		prev = h
+	}
+	for k := n - 1; k > 0; k-- {
+		if v := ring[(start+k)%n]; isNew[v] {
+			b.attach(v, outgoing(ring[(start+k+1)%n]).Sym)
+		}
+	}
+}
+
+// activate 处理新连接的顶点：只剩一个顶点的波前多边形直接结束；只剩两个顶点时两条边重合为一条屋脊；
+// 两侧的边反向重合、速度无定义的顶点是宽度为0的尖刺，把它收回到较近的相邻顶点；
+// 其余顶点计算两侧的边事件
+func (b *skeletonBuilder) activate(vs []*wavefrontVertex) {
+	for len(vs) > 0 {
+		v := vs[0]
+		vs = vs[1:]
+		if !v.alive || v.arc == nil {
+			continue
+		}
+		switch {
+		case v.next == v:
+			b.kill(v)
+			tessMeshDelete(b.mesh, v.arc)
+		case v.next.next == v:
+			b.ridge(v, v.next)
+		case v.stuck:
+			v.pos = v.at(b.now)
+			v.prev.pos, v.next.pos = v.prev.at(b.now), v.next.at(b.now)
+			other := v.prev
+			if d1, d2 := v.pos.sub(v.prev.pos), v.pos.sub(v.next.pos); d2.dot(d2) < d1.dot(d1) {
+				other = v.next
+			}
+			vs = append(vs, b.processCluster([]*wavefrontVertex{v, other}, other.pos)...)
+		default:
+			b.scheduleEdge(v)
+			b.scheduleEdge(v.prev)
+		}
+	}
+}
+
+// ridge 结束只剩两个顶点的波前多边形：两条边重合为一条屋脊，连接两个顶点的当前位置
+func (b *skeletonBuilder) ridge(s, t *wavefrontVertex) {
+	e := tessMeshMakeEdge(b.mesh)
+	tessMeshSplice(b.mesh, s.arc.Sym, e)
+	tessMeshSplice(b.mesh, t.arc.Sym, e.Sym)
+	s.arc.dst().idx = b.addNode(s.at(b.now))
+	t.arc.dst().idx = b.addNode(t.at(b.now))
+	b.kill(s)
+	b.kill(t)
+}
+
+// output 从网格中取出节点、弧和面。同一时刻同一位置的多个事件产生的长度为0的弧，
+// 两端合并为一个节点；输入顶点保持原来的序号
+func (b *skeletonBuilder) output(s *Skeleton) error {
+	inputs := len(b.edges)
+	parent := make([]int, len(b.nodes))
+	for i := range parent {
+		parent[i] = i
+	}
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+	// zero 判断弧两端的节点在时间和位置上是否都只相差tol以内
+	zero := func(e *halfEdge, tol float64) bool {
+		i, j := int(e.Org.idx), int(e.dst().idx)
+		d := b.nodes[i].sub(b.nodes[j])
+		return math.Abs(d.x) <= tol && math.Abs(d.y) <= tol && math.Abs(b.times[i]-b.times[j]) <= tol
+	}
+	input := make(map[*halfEdge]bool, 2*inputs)
+	for _, e := range b.edges {
+		input[e], input[e.Sym] = true, true
+	}
+	for e := b.mesh.eHead.next; e != &b.mesh.eHead; e = e.next {
+		if e.Org.idx == undef || e.dst().idx == undef {
+			return fmt.Errorf("straight skeleton: wavefront did not collapse")
+		}
+		if input[e] {
+			continue
+		}
+		// 输入数据的舍入使本应同时发生的事件（如正多边形的各条边同时收缩）略有先后，中间留下很短的弧，
+		// 两端都不包含输入顶点时以较大的容差合并
+		i, j := find(int(e.Org.idx)), find(int(e.dst().idx))
+		tol := b.eps
+		if i >= inputs && j >= inputs {
+			tol = b.snap
+		}
+		if i != j && (i >= inputs || j >= inputs) && zero(e, tol) {
+			if i < j {
+				parent[j] = i
+			} else {
+				parent[i] = j
+			}
+		}
+	}
+
+	// 合并的节点取各节点位置和时间的平均值，包含输入顶点时为该输入顶点
+	sums := make([]struct {
+		p    skelPoint
+		t, n float64
+	}, len(b.nodes))
+	for i, p := range b.nodes {
+		r := &sums[find(i)]
+		r.p, r.t, r.n = r.p.add(p, 1), r.t+b.times[i], r.n+1
+	}
+	ids := make([]int, len(b.nodes))
+	for i, r := range sums {
+		if find(i) == i {
+			p, t := b.nodes[i], b.times[i]
+			if i >= inputs {
+				p, t = skelPoint{r.p.x / r.n, r.p.y / r.n}, r.t/r.n
+			}
+			ids[i] = len(s.Nodes)
+			s.Nodes = append(s.Nodes, SkeletonNode{Position: Vertex{X: float32(p.x), Y: float32(p.y)}, Distance: float32(t)})
+		}
+	}
+	for i := range b.nodes {
+		ids[i] = ids[find(i)]
+	}
+	node := func(v *vertex) int { return ids[v.idx] }
+
+	faceOf := make(map[*face]int, inputs)
+	s.Faces = make([]SkeletonFace, inputs)
+	for k, e := range b.edges {
+		if _, ok := faceOf[e.Lface]; ok {
+			return fmt.Errorf("straight skeleton: faces of edges %d and %d are not separated", faceOf[e.Lface], k)
+		}
+		faceOf[e.Lface] = k
+		nodes := []int{node(e.Org)}
+		for f := e.Lnext; f != e; f = f.Lnext {
+			if n := node(f.Org); n != nodes[len(nodes)-1] {
+				nodes = append(nodes, n)
+			}
+		}
+		if len(nodes) > 1 && nodes[len(nodes)-1] == nodes[0] {
+			nodes = nodes[:len(nodes)-1]
+		}
+		s.Faces[k] = SkeletonFace{Nodes: nodes}
+	}
+
+	for e := b.mesh.eHead.next; e != &b.mesh.eHead; e = e.next {
+		if input[e] || node(e.Org) == node(e.dst()) {
+			continue
+		}
+		h := e
+		if b.times[e.Org.idx] > b.times[e.dst().idx] {
+			h = e.Sym
+		}
+		left, ok1 := faceOf[h.Lface]
+		right, ok2 := faceOf[h.rFace()]
+		if !ok1 || !ok2 {
+			return fmt.Errorf("straight skeleton: arc outside the input faces")
+		}
+		s.Arcs = append(s.Arcs, SkeletonArc{From: node(h.Org), To: node(h.dst()), Left: left, Right: right})
+	}
+	return nil
+}
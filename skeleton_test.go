@@ -0,0 +1,268 @@
+package tesselator
+
+import (
+	"math"
+	"testing"
+)
+
+// checkSkeleton 检查直骨架的基本性质：每条输入边一个面，各面为逆时针且面积之和等于多边形面积，
+// 面上每个节点的Distance等于它到该面输入边所在直线的距离
+func checkSkeleton(t *testing.T, name string, s *Skeleton, area float64) {
+	t.Helper()
+	edges := 0
+	for _, c := range s.Contours {
+		edges += len(c)
+	}
+	if len(s.Faces) != edges {
+		t.Fatalf("%s: expected %d faces, got %d", name, edges, len(s.Faces))
+	}
+	total := 0.0
+	for f, face := range s.Faces {
+		if len(face.Nodes) < 3 {
+			t.Fatalf("%s: face %d has only %d nodes", name, f, len(face.Nodes))
+		}
+		c := make(Contour, len(face.Nodes))
+		for i, n := range face.Nodes {
+			c[i] = s.Nodes[n].Position
+		}
+		a := contourSignedArea(c)
+		if a <= 0 {
+			t.Errorf("%s: face %d is not counter-clockwise (area %.4f)", name, f, a)
+		}
+		total += a
+
+		p, q := c[0], c[1]
+		dx, dy := float64(q.X-p.X), float64(q.Y-p.Y)
+		l := math.Hypot(dx, dy)
+		for i, n := range face.Nodes {
+			d := (dx*float64(c[i].Y-p.Y) - dy*float64(c[i].X-p.X)) / l
+			if math.Abs(d-float64(s.Nodes[n].Distance)) > 1e-3 {
+				t.Errorf("%s: face %d node %v has distance %v, expected %.4f", name, f, c[i], s.Nodes[n].Distance, d)
+			}
+		}
+	}
+	if math.Abs(total-area) > 1e-3*area {
+		t.Errorf("%s: faces cover %.4f, expected %.4f", name, total, area)
+	}
+	for _, a := range s.Arcs {
+		if a.From == a.To || a.Left == a.Right {
+			t.Errorf("%s: degenerate arc %+v", name, a)
+		}
+	}
+}
+
+// TestStraightSkeleton 测试凸多边形、带洞多边形、凹多边形和同时发生的事件
+func TestStraightSkeleton(t *testing.T) {
+	octagon := GenerateRegularPolygon(8, 0, 0, 10)
+	// 半径起伏的星形有大量凹顶点，很多事件的时间只相差一点
+	var jagged Contour
+	for i := 0; i < 400; i++ {
+		a, r := 2*math.Pi*float64(i)/400, 50+10*math.Sin(float64(i)*7.3)
+		jagged = append(jagged, Vertex{X: float32(r * math.Cos(a)), Y: float32(r * math.Sin(a))})
+	}
+	cases := []struct {
+		name     string
+		contours []Contour
+		nodes    int // 内部节点个数，-1表示不检查
+		maxDist  float64
+	}{
+		// 四条边同时收缩到中心
+		{"square", []Contour{toContour([]Vector2f{{0, 0}, {10, 0}, {10, 10}, {0, 10}})}, 1, 5},
+		{"rectangle", []Contour{toContour([]Vector2f{{0, 0}, {20, 0}, {20, 10}, {0, 10}})}, 2, 5},
+		{"octagon", []Contour{octagon}, 1, 10 * math.Cos(math.Pi/8)},
+		// 两臂等宽的L形：凹顶点与两端的边事件同时到达
+		{"L", []Contour{toContour([]Vector2f{{0, 0}, {20, 0}, {20, 10}, {10, 10}, {10, 20}, {0, 20}})}, 3, 5},
+		// 窄臂两侧在同一时刻重合为一条屋脊
+		{"arm", []Contour{toContour([]Vector2f{{0, 0}, {10, 0}, {10, 4}, {20, 4}, {20, 6}, {10, 6}, {10, 10}, {0, 10}})}, -1, 5},
+		// 缺口处的凹顶点撞上底边，多边形分裂为两部分
+		{"notch", []Contour{toContour([]Vector2f{{0, 0}, {30, 0}, {30, 10}, {16, 10}, {15, 2}, {14, 10}, {0, 10}})}, -1, -1},
+		{"hole", []Contour{
+			toContour([]Vector2f{{0, 0}, {10, 0}, {10, 10}, {0, 10}}),
+			toContour([]Vector2f{{4, 4}, {4, 6}, {6, 6}, {6, 4}}),
+		}, 4, 2},
+		// 宽度远小于长度的细长三角形，顶点的两条边几乎反向平行
+		{"sliver", []Contour{toContour([]Vector2f{{-18.34705, 16.652365}, {-27.74819, 18.695282}, {-19.111658, 9.802729}, {-27.445665, -64.76258}})}, -1, -1},
+		// 自相交的输入先按环绕规则整理
+		{"bowtie", []Contour{toContour([]Vector2f{{0, 0}, {4, 4}, {4, 0}, {0, 4}})}, -1, -1},
+		{"jagged", []Contour{jagged}, -1, -1},
+	}
+	for _, c := range cases {
+		s, err := StraightSkeleton(c.contours, WindingRuleNonzero)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		indices, vertices, _ := Tesselate(c.contours, WindingRuleNonzero)
+		checkSkeleton(t, c.name, s, meshArea(indices, vertices))
+
+		inputs := 0
+		for _, ct := range s.Contours {
+			inputs += len(ct)
+		}
+		if c.nodes >= 0 && len(s.Nodes)-inputs != c.nodes {
+			t.Errorf("%s: expected %d skeleton nodes, got %d", c.name, c.nodes, len(s.Nodes)-inputs)
+		}
+		if c.maxDist >= 0 {
+			max := 0.0
+			for _, n := range s.Nodes {
+				max = math.Max(max, float64(n.Distance))
+			}
+			if math.Abs(max-c.maxDist) > 1e-4 {
+				t.Errorf("%s: expected maximum distance %.4f, got %.4f", c.name, c.maxDist, max)
+			}
+		}
+	}
+
+	// 矩形的屋脊
+	s, _ := StraightSkeleton([]Contour{toContour([]Vector2f{{0, 0}, {20, 0}, {20, 10}, {0, 10}})}, WindingRuleOdd)
+	ridge := false
+	for _, a := range s.Arcs {
+		p, q := s.Nodes[a.From].Position, s.Nodes[a.To].Position
+		if p.Y == 5 && q.Y == 5 && math.Abs(float64(p.X-q.X)) == 10 {
+			ridge = true
+		}
+	}
+	if !ridge {
+		t.Errorf("rectangle skeleton should contain the ridge from (5,5) to (15,5), got %+v", s.Arcs)
+	}
+
+	if s, err := StraightSkeleton(nil, WindingRuleOdd); err != nil || len(s.Faces) != 0 {
+		t.Errorf("empty input should produce an empty skeleton")
+	}
+}
+
+// TestSkeletonRegularPolygon 测试正多边形：输入坐标舍入后各条边的收缩时间略有差别，
+// 仍应合并为中心的一个节点
+func TestSkeletonRegularPolygon(t *testing.T) {
+	for _, n := range []int{8, 32, 64} {
+		for _, r := range []float32{1, 10, 1000} {
+			// 远离原点时坐标的舍入误差更大
+			for _, cx := range []float32{0, 3 * r} {
+				polygon := GenerateRegularPolygon(n, cx, 0, r)
+				s, err := StraightSkeleton([]Contour{polygon}, WindingRuleOdd)
+				if err != nil {
+					t.Fatalf("%d-gon r=%v: %v", n, r, err)
+				}
+				if r <= 10 {
+					indices, vertices, _ := Tesselate([]Contour{polygon}, WindingRuleOdd)
+					checkSkeleton(t, "regular polygon", s, meshArea(indices, vertices))
+				}
+				if len(s.Nodes) != n+1 || len(s.Arcs) != n {
+					t.Errorf("%d-gon r=%v center (%v,0): expected %d nodes and %d arcs, got %d and %d",
+						n, r, cx, n+1, n, len(s.Nodes), len(s.Arcs))
+				}
+			}
+		}
+	}
+}
+
+// TestSkeletonRoof 测试按坡度抬升得到的屋顶网格
+func TestSkeletonRoof(t *testing.T) {
+	contours := []Contour{toContour([]Vector2f{{0, 0}, {20, 0}, {20, 10}, {10, 10}, {10, 20}, {0, 20}})}
+	s, err := StraightSkeleton(contours, WindingRuleOdd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	indices, vertices, err := s.Roof(0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a := meshArea(indices, vertices); math.Abs(a-300) > 1e-3 {
+		t.Errorf("roof should cover the footprint area 300, got %.4f", a)
+	}
+	for i, n := range s.Nodes {
+		if vertices[i].Z != n.Distance*0.5 {
+			t.Errorf("node %d lifted to %v, expected %v", i, vertices[i].Z, n.Distance*0.5)
+		}
+	}
+	// 每个三角形的法线朝上，屋顶上每条内部边被两个三角形共用
+	for i, n := range FaceNormals(indices, vertices) {
+		if n.Z <= 0 {
+			t.Errorf("roof triangle %d faces downwards: %v", i, n)
+		}
+	}
+	edges := map[[2]int]int{}
+	for i := 0; i < len(indices); i += 3 {
+		for k := 0; k < 3; k++ {
+			edges[[2]int{indices[i+k], indices[i+(k+1)%3]}]++
+		}
+	}
+	for e, n := range edges {
+		_, opposite := edges[[2]int{e[1], e[0]}]
+		border := vertices[e[0]].Z == 0 && vertices[e[1]].Z == 0
+		if n != 1 || opposite == border {
+			t.Errorf("roof edge %v is not shared correctly", e)
+		}
+	}
+}
+
+// TestSkeletonGable 测试加权直骨架：短边权重为0的矩形得到双坡屋顶，两端为竖直的山墙
+func TestSkeletonGable(t *testing.T) {
+	rect := []Contour{toContour([]Vector2f{{0, 0}, {10, 0}, {10, 4}, {0, 4}})}
+	gable := func(a, b Vertex) float32 {
+		if a.X == b.X {
+			return 0
+		}
+		return 1
+	}
+	s, err := StraightSkeletonWeighted(rect, WindingRuleOdd, gable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k := 0
+	for _, c := range s.Contours {
+		for i, a := range c {
+			if w := s.Weights[k]; w != gable(a, c[(i+1)%len(c)]) {
+				t.Errorf("edge %d has weight %v", k, w)
+			}
+			k++
+		}
+	}
+	// 屋脊从一端的山墙顶点到另一端，高度为矩形宽度的一半
+	if len(s.Nodes) != 6 {
+		t.Fatalf("expected 2 skeleton nodes, got %+v", s.Nodes[4:])
+	}
+	for _, n := range s.Nodes[4:] {
+		if (n.Position.X != 0 && n.Position.X != 10) || n.Position.Y != 2 || n.Distance != 2 {
+			t.Errorf("unexpected ridge node %+v", n)
+		}
+	}
+	if len(s.Arcs) != 5 {
+		t.Errorf("expected 4 hip arcs and the ridge, got %+v", s.Arcs)
+	}
+
+	indices, vertices, err := s.Roof(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a := meshArea(indices, vertices); math.Abs(a-40) > 1e-3 {
+		t.Errorf("roof should cover the footprint area 40, got %.4f", a)
+	}
+	gables := 0
+	for i, n := range FaceNormals(indices, vertices) {
+		a := vertices[indices[3*i]]
+		switch {
+		case math.Abs(float64(n.Z)-math.Sqrt2/2) < 1e-4:
+		case n.Z == 0 && n.X*(a.X-5) > 0:
+			gables++
+		default:
+			t.Errorf("roof triangle %d has normal %v", i, n)
+		}
+	}
+	if gables != 2 {
+		t.Errorf("expected 2 vertical gable triangles, got %d", gables)
+	}
+
+	// 权重为2的边移动得更快，节点的时间减半
+	s, err = StraightSkeletonWeighted(rect[:1], WindingRuleOdd, func(a, b Vertex) float32 { return 2 })
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range s.Nodes[4:] {
+		if n.Distance != 1 {
+			t.Errorf("expected time 1 for weight 2, got %+v", n)
+		}
+	}
+	if _, err := StraightSkeletonWeighted(rect, WindingRuleOdd, func(a, b Vertex) float32 { return -1 }); err == nil {
+		t.Error("expected an error for a negative weight")
+	}
+}
@@ -0,0 +1,249 @@
+package tesselator
+
+import "math"
+
+// TesselateWithPoints 与Tesselate相同，但额外把孤立点（如测量点、高程采样点）作为顶点加入三角剖分，
+// 使得到的不规则三角网经过这些点。位于填充区域内（包括边界上）的点成为输出的顶点并保留自身的Z；
+// 区域外的点、非有限的点以及与已有顶点重合的点被忽略
+func TesselateWithPoints(contours []Contour, points []Vertex, windingRule WindingRule) ([]int, []Vertex, error) {
+	return tesselateWithExtras(&tesselator{steinerPoints: points}, contours, nil, windingRule, nil)
+}
+
+// steinerPoint 待插入的点及其在扫描平面上的投影
+type steinerPoint struct {
+	coords [3]float
+	s, t   float64
+}
+
+// insertSteinerPoints 把tess.steinerPoints插入已剖分为三角形的网格。网格不支持孤立顶点，
+// 因此先把每个点分配到包含它的三角形，再逐个插入：点在三角形内部时把三角形分成三个，
+// 在边上时拆分这条边并把两侧的三角形各分成两个。同一三角形中剩余的点重新分配到新的三角形中
+func insertSteinerPoints(tess *tesselator, mesh *mesh) {
+	var points []steinerPoint
+	for _, p := range tess.steinerPoints {
+		c := [3]float{float(p.X), float(p.Y), float(p.Z)}
+		sp := steinerPoint{c, float64(dot(c[:], tess.sUnit[:])), float64(dot(c[:], tess.tUnit[:]))}
+		if !math.IsNaN(sp.s) && !math.IsInf(sp.s, 0) && !math.IsNaN(sp.t) && !math.IsInf(sp.t, 0) {
+			points = append(points, sp)
+		}
+	}
+	if len(points) == 0 {
+		return
+	}
+	extent := math.Max(float64(tess.bmax[0]-tess.bmin[0]), float64(tess.bmax[1]-tess.bmin[1]))
+	tolerance := 1e-6 * math.Max(extent, 1)
+
+	// 按网格对点分桶，每个三角形只检查与其范围相交的格子中的点
+	cellSize := math.Max(extent/math.Sqrt(float64(len(points))), tolerance)
+	cellOf := func(s, t float64) [2]int64 {
+		return [2]int64{int64(math.Floor(s / cellSize)), int64(math.Floor(t / cellSize))}
+	}
+	cells := map[[2]int64][]int{}
+	for i, p := range points {
+		c := cellOf(p.s, p.t)
+		cells[c] = append(cells[c], i)
+	}
+
+	// 先按精确的包含关系分配，剩下的点（如凸包外侧容差内的点）再按容差分配
+	assigned := make([]bool, len(points))
+	pending := map[*face][]int{}
+	var stack []*face
+	for _, tol := range []float64{0, tolerance} {
+		for f := mesh.fHead.next; f != &mesh.fHead; f = f.next {
+			if !f.inside {
+				continue
+			}
+			minS, minT := math.Inf(1), math.Inf(1)
+			maxS, maxT := math.Inf(-1), math.Inf(-1)
+			e := f.anEdge
+			for {
+				minS, maxS = math.Min(minS, float64(e.Org.s)), math.Max(maxS, float64(e.Org.s))
+				minT, maxT = math.Min(minT, float64(e.Org.t)), math.Max(maxT, float64(e.Org.t))
+				e = e.Lnext
+				if e == f.anEdge {
+					break
+				}
+			}
+			visit := func(i int) {
+				if !assigned[i] && faceContains(f, points[i], tol) {
+					assigned[i] = true
+					pending[f] = append(pending[f], i)
+				}
+			}
+			lo, hi := cellOf(minS-tolerance, minT-tolerance), cellOf(maxS+tolerance, maxT+tolerance)
+			if float64(hi[0]-lo[0]+1)*float64(hi[1]-lo[1]+1) > float64(len(cells)) {
+				// 三角形覆盖的格子比非空的格子还多，直接检查所有点
+				for i := range points {
+					visit(i)
+				}
+			} else {
+				for x := lo[0]; x <= hi[0]; x++ {
+					for y := lo[1]; y <= hi[1]; y++ {
+						for _, i := range cells[[2]int64{x, y}] {
+							visit(i)
+						}
+					}
+				}
+			}
+		}
+	}
+	for f := mesh.fHead.next; f != &mesh.fHead; f = f.next {
+		if len(pending[f]) > 0 {
+			stack = append(stack, f)
+		}
+	}
+
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		list := pending[f]
+		if len(list) == 0 {
+			continue
+		}
+		delete(pending, f)
+		p := points[list[0]]
+		rest := list[1:]
+
+		v, split := insertPoint(tess, mesh, f, p, tolerance, true)
+		if v == nil {
+			// 与顶点重合或无法插入
+			if len(rest) > 0 {
+				pending[f] = rest
+				stack = append(stack, f)
+			}
+			continue
+		}
+		for _, g := range split {
+			rest = append(rest, pending[g]...)
+			delete(pending, g)
+		}
+
+		// 新顶点周围的三角形接收被拆分的三角形中剩余的点；点插入相邻三角形时f保持不变
+		faces := []*face{f}
+		e := v.anEdge
+		for {
+			if e.Lface.inside && e.Lface != f {
+				faces = append(faces, e.Lface)
+			}
+			e = e.Onext
+			if e == v.anEdge {
+				break
+			}
+		}
+		for _, i := range rest {
+			placed := false
+			for _, tol := range []float64{0, tolerance} {
+				for _, g := range faces {
+					if !placed && faceContains(g, points[i], tol) {
+						pending[g] = append(pending[g], i)
+						placed = true
+					}
+				}
+			}
+		}
+		for _, g := range faces {
+			if len(pending[g]) > 0 {
+				stack = append(stack, g)
+			}
+		}
+	}
+}
+
+// faceContains 判断点是否位于逆时针三角形f内部或与其边界的距离不超过tolerance
+func faceContains(f *face, p steinerPoint, tolerance float64) bool {
+	e := f.anEdge
+	for {
+		if edgeDistance(e, p) < -tolerance {
+			return false
+		}
+		e = e.Lnext
+		if e == f.anEdge {
+			break
+		}
+	}
+	return true
+}
+
+// edgeDistance 返回点到边所在直线的有向距离，位于边的左侧时为正
+func edgeDistance(e *halfEdge, p steinerPoint) float64 {
+	os, ot := float64(e.Org.s), float64(e.Org.t)
+	ds, dt := float64(e.dst().s)-os, float64(e.dst().t)-ot
+	length := math.Hypot(ds, dt)
+	if length == 0 {
+		return 0
+	}
+	return (ds*(p.t-ot) - dt*(p.s-os)) / length
+}
+
+// insertPoint 把点插入三角形f，返回新顶点，以及除f以外被拆分的三角形。插入后的三角形必须都是逆时针的：
+// 点在容差内位于f之外时改为插入相邻的三角形（retry为false时不再尝试），
+// 点与三角形的顶点重合或无法插入时返回nil
+func insertPoint(tess *tesselator, mesh *mesh, f *face, p steinerPoint, tolerance float64, retry bool) (*vertex, []*face) {
+	e := f.anEdge
+	nearest, distance := e, math.Inf(1)
+	for {
+		if math.Hypot(float64(e.Org.s)-p.s, float64(e.Org.t)-p.t) <= tolerance {
+			return nil, nil
+		}
+		if d := edgeDistance(e, p); d < distance {
+			nearest, distance = e, d
+		}
+		e = e.Lnext
+		if e == f.anEdge {
+			break
+		}
+	}
+
+	e = nearest
+	var neighbour *face
+	if r := e.rFace(); r != nil && r.inside {
+		neighbour = r
+	}
+	splitEdge := distance <= tolerance && edgeDistance(e.Lnext, p) > 0 && edgeDistance(e.Lnext.Lnext, p) > 0 &&
+		(neighbour == nil || edgeDistance(e.Sym.Lnext, p) > 0 && edgeDistance(e.Sym.Lnext.Lnext, p) > 0)
+	if !splitEdge && distance <= 0 {
+		if neighbour == nil || !retry {
+			return nil, nil
+		}
+		v, split := insertPoint(tess, mesh, neighbour, p, tolerance, false)
+		if v == nil {
+			return nil, nil
+		}
+		affected := []*face{neighbour}
+		for _, g := range split {
+			if g != f {
+				affected = append(affected, g)
+			}
+		}
+		return v, affected
+	}
+
+	var v *vertex
+	if splitEdge {
+		// 拆分边X->Y得到X->P和P->Y，再把P与两侧三角形的对顶点相连
+		eNew := tessMeshSplitEdge(mesh, e)
+		v = eNew.Org
+		tessMeshConnect(mesh, e, e.Lnext.Lnext.Lnext)
+		if neighbour != nil {
+			a := eNew.Sym
+			tessMeshConnect(mesh, a, a.Lnext.Lnext.Lnext)
+		}
+	} else {
+		// 点严格位于三角形内部：从一个顶点引出到P的边，再把P与另外两个顶点相连
+		neighbour = nil
+		e0 := f.anEdge
+		e2 := e0.Lnext.Lnext
+		eNew := tessMeshAddEdgeVertex(mesh, e0)
+		v = eNew.dst()
+		tessMeshConnect(mesh, eNew, e2)
+		tessMeshConnect(mesh, eNew, e0)
+	}
+	v.coords = p.coords
+	v.s, v.t = float(p.s), float(p.t)
+	v.idx = tess.vertexIndexCounter
+	tess.vertexIndexCounter++
+	if neighbour != nil {
+		return v, []*face{neighbour}
+	}
+	return v, nil
+}
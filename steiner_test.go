@@ -0,0 +1,94 @@
+package tesselator
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// TestTesselateWithPoints 区域内的点成为顶点，三角形不重叠且覆盖整个区域
+func TestTesselateWithPoints(t *testing.T) {
+	contours := []Contour{squareContour(0, 0, 10), reversedContour(squareContour(4, 4, 2))}
+	points := []Vertex{
+		{X: 2, Y: 2, Z: 1},
+		{X: 8, Y: 3, Z: 2},
+		// 边界上、洞的边上和三角形的边上
+		{X: 5, Y: 0, Z: 3},
+		{X: 5, Y: 4, Z: 4},
+		{X: 5, Y: 5, Z: 5},
+		// 洞内、区域外、重复和与轮廓顶点重合的点被忽略
+		{X: 5, Y: 5.5, Z: 6},
+		{X: 12, Y: 5, Z: 7},
+		{X: 2, Y: 2, Z: 8},
+		{X: 10, Y: 10, Z: 9},
+		{X: float32(math.NaN()), Y: 1},
+	}
+	indices, vertices, err := TesselateWithPoints(contours, points, WindingRuleOdd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vertices) != 8+4 {
+		t.Errorf("expected 12 vertices, got %d: %v", len(vertices), vertices)
+	}
+	for _, p := range points[:4] {
+		found := false
+		for _, v := range vertices {
+			found = found || v == p
+		}
+		if !found {
+			t.Errorf("point %v is not a vertex of the triangulation", p)
+		}
+	}
+	checkPointTriangulation(t, indices, vertices, 96)
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		star := []Contour{GenerateStar(5+r.Intn(5), 0, 0, 10, 4)}
+		var samples []Vertex
+		for k := 0; k < 200; k++ {
+			// 整数坐标使点经常落在已有的边上
+			if i%2 == 0 {
+				samples = append(samples, Vertex{X: float32(r.Intn(21) - 10), Y: float32(r.Intn(21) - 10), Z: 1})
+			} else {
+				samples = append(samples, Vertex{X: float32(r.Float64()*20 - 10), Y: float32(r.Float64()*20 - 10), Z: 1})
+			}
+		}
+		plainIndices, plainVertices, _ := Tesselate(star, WindingRuleOdd)
+		indices, vertices, err := TesselateWithPoints(star, samples, WindingRuleOdd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkPointTriangulation(t, indices, vertices, meshArea(plainIndices, plainVertices))
+		inside := map[[2]float32]bool{}
+		for _, v := range vertices {
+			inside[[2]float32{v.X, v.Y}] = true
+		}
+		for _, p := range samples {
+			// 与轮廓顶点在容差内重合的点被合并
+			onVertex := false
+			for _, v := range star[0] {
+				onVertex = onVertex || math.Hypot(float64(v.X-p.X), float64(v.Y-p.Y)) < 1e-4
+			}
+			if !onVertex && Contains(star, WindingRuleOdd, p) && !inside[[2]float32{p.X, p.Y}] {
+				t.Fatalf("interior point %v is not a vertex", p)
+			}
+		}
+	}
+}
+
+// checkPointTriangulation 检查所有三角形为逆时针且面积之和等于区域面积
+func checkPointTriangulation(t *testing.T, indices []int, vertices []Vertex, area float64) {
+	t.Helper()
+	total := 0.0
+	for i := 0; i < len(indices); i += 3 {
+		a, b, c := vertices[indices[i]], vertices[indices[i+1]], vertices[indices[i+2]]
+		signed := (float64(b.X-a.X)*float64(c.Y-a.Y) - float64(c.X-a.X)*float64(b.Y-a.Y)) / 2
+		if signed < -1e-6 {
+			t.Errorf("triangle %v %v %v is clockwise", a, b, c)
+		}
+		total += math.Abs(signed)
+	}
+	if math.Abs(total-area) > 1e-3 {
+		t.Errorf("triangles cover %g, expected %g", total, area)
+	}
+}
@@ -0,0 +1,420 @@
+package tesselator
+
+import (
+	"math"
+)
+
+// LineJoin 描边在折线拐角处的连接方式
+type LineJoin int
+
+const (
+	LineJoinMiter LineJoin = iota
+	LineJoinRound
+	LineJoinBevel
+)
+
+// LineCap 开放折线端点处的线帽样式
+type LineCap int
+
+const (
+	LineCapButt LineCap = iota
+	LineCapRound
+	LineCapSquare
+)
+
+// StrokeStyle 描边参数
+type StrokeStyle struct {
+	Width float32 // 线宽
+	Join  LineJoin
+	Cap   LineCap
+
+	// 斜接长度与线宽之比的上限（与SVG的stroke-miterlimit一致），
+	// 超过时退化为斜切连接；<=0时取4
+	MiterLimit float32
+
+	// 虚线模式：交替的实线段与间隔长度，为空表示实线。
+	// 奇数个元素时按SVG的规则重复一次
+	Dash       []float32
+	DashOffset float32
+
+	// 圆角与圆帽的最大弦高误差；<=0时取线宽的1/50
+	Tolerance float32
+}
+
+// strokePoint 描边计算使用的双精度点
+type strokePoint struct {
+	x, y, z float64
+}
+
+func (p strokePoint) add(q strokePoint, s float64) strokePoint {
+	return strokePoint{p.x + q.x*s, p.y + q.y*s, p.z}
+}
+
+func (p strokePoint) vertex() Vertex {
+	return Vertex{X: float32(p.x), Y: float32(p.y), Z: float32(p.z)}
+}
+
+func lerpStrokePoint(a, b strokePoint, t float64) strokePoint {
+	return strokePoint{
+		a.x + (b.x-a.x)*t,
+		a.y + (b.y-a.y)*t,
+		a.z + (b.z-a.z)*t,
+	}
+}
+
+// Stroke 将折线描边并三角剖分。
+// 各段、连接和线帽之间的重叠通过WindingRuleNonzero在同一次扫描中合并，
+// 因此输出的三角形互不重叠，半透明描边不会重复混合。
+func Stroke(polyline Contour, closed bool, style StrokeStyle) ([]int, []Vertex, error) {
+	return Tesselate(StrokeContours(polyline, closed, style), WindingRuleNonzero)
+}
+
+// StrokeContours 将折线按描边样式转换为一组逆时针的轮廓。
+// 轮廓之间可能重叠，应使用WindingRuleNonzero进行三角剖分。
+func StrokeContours(polyline Contour, closed bool, style StrokeStyle) []Contour {
+	if style.Width <= 0 {
+		return nil
+	}
+
+	pts := make([]strokePoint, 0, len(polyline))
+	for _, v := range polyline {
+		p := strokePoint{float64(v.X), float64(v.Y), float64(v.Z)}
+		if len(pts) > 0 && pts[len(pts)-1].x == p.x && pts[len(pts)-1].y == p.y {
+			continue
+		}
+		pts = append(pts, p)
+	}
+	if closed && len(pts) > 1 && pts[0].x == pts[len(pts)-1].x && pts[0].y == pts[len(pts)-1].y {
+		pts = pts[:len(pts)-1]
+	}
+	if len(pts) == 0 {
+		return nil
+	}
+	if len(pts) < 3 {
+		// 少于3个点的闭合折线按开放折线处理
+		closed = false
+	}
+
+	s := &stroker{
+		style:      style,
+		halfWidth:  float64(style.Width) / 2,
+		miterLimit: float64(style.MiterLimit),
+		tolerance:  float64(style.Tolerance),
+	}
+	if s.miterLimit <= 0 {
+		s.miterLimit = 4
+	}
+	if s.tolerance <= 0 {
+		s.tolerance = float64(style.Width) / 50
+	}
+
+	for _, piece := range dashPolyline(pts, closed, style.Dash, style.DashOffset) {
+		s.strokePiece(piece.pts, piece.closed)
+	}
+	return s.contours
+}
+
+type stroker struct {
+	style      StrokeStyle
+	halfWidth  float64
+	miterLimit float64
+	tolerance  float64
+	contours   []Contour
+}
+
+// emit 以逆时针方向保存一个轮廓
+func (s *stroker) emit(pts ...strokePoint) {
+	c := make(Contour, len(pts))
+	for i, p := range pts {
+		c[i] = p.vertex()
+	}
+	if contourSignedArea(c) < 0 {
+		reverseContour(c)
+	}
+	s.contours = append(s.contours, c)
+}
+
+// arcSteps 返回半径为r、张角为angle的圆弧在给定误差下需要的分段数
+func (s *stroker) arcSteps(r, angle float64) int {
+	step := math.Pi / 2
+	if s.tolerance < r {
+		step = math.Min(step, 2*math.Acos(1-s.tolerance/r))
+	}
+	n := int(math.Ceil(math.Abs(angle) / step))
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// arc 生成以c为圆心、从角度a0扫过sweep弧度的圆弧上的点（包含两端）
+func (s *stroker) arc(c strokePoint, a0, sweep float64) []strokePoint {
+	n := s.arcSteps(s.halfWidth, sweep)
+	pts := make([]strokePoint, 0, n+1)
+	for i := 0; i <= n; i++ {
+		a := a0 + sweep*float64(i)/float64(n)
+		pts = append(pts, strokePoint{
+			c.x + s.halfWidth*math.Cos(a),
+			c.y + s.halfWidth*math.Sin(a),
+			c.z,
+		})
+	}
+	return pts
+}
+
+func (s *stroker) strokePiece(pts []strokePoint, closed bool) {
+	hw := s.halfWidth
+
+	if len(pts) == 1 {
+		// 零长度子路径：圆帽和方帽画出一个点
+		p := pts[0]
+		switch s.style.Cap {
+		case LineCapRound:
+			circle := s.arc(p, 0, 2*math.Pi)
+			s.emit(circle[:len(circle)-1]...)
+		case LineCapSquare:
+			s.emit(
+				strokePoint{p.x - hw, p.y - hw, p.z},
+				strokePoint{p.x + hw, p.y - hw, p.z},
+				strokePoint{p.x + hw, p.y + hw, p.z},
+				strokePoint{p.x - hw, p.y + hw, p.z},
+			)
+		}
+		return
+	}
+
+	n := len(pts)
+	segCount := n - 1
+	if closed {
+		segCount = n
+	}
+
+	// 各段的单位方向
+	dirs := make([]strokePoint, segCount)
+	for i := 0; i < segCount; i++ {
+		a := pts[i]
+		b := pts[(i+1)%n]
+		dx, dy := b.x-a.x, b.y-a.y
+		l := math.Hypot(dx, dy)
+		dirs[i] = strokePoint{dx / l, dy / l, 0}
+	}
+
+	// 线段主体
+	for i := 0; i < segCount; i++ {
+		a := pts[i]
+		b := pts[(i+1)%n]
+		nrm := strokePoint{-dirs[i].y, dirs[i].x, 0}
+		s.emit(a.add(nrm, hw), b.add(nrm, hw), b.add(nrm, -hw), a.add(nrm, -hw))
+	}
+
+	// 连接
+	for i := 0; i < n; i++ {
+		var d0, d1 strokePoint
+		if closed {
+			d0 = dirs[(i+segCount-1)%segCount]
+			d1 = dirs[i]
+		} else {
+			if i == 0 || i == n-1 {
+				continue
+			}
+			d0 = dirs[i-1]
+			d1 = dirs[i]
+		}
+		s.join(pts[i], d0, d1)
+	}
+
+	// 线帽
+	if !closed {
+		first := dirs[0]
+		last := dirs[segCount-1]
+		s.cap(pts[0], strokePoint{-first.x, -first.y, 0})
+		s.cap(pts[n-1], last)
+	}
+}
+
+// join 在p处连接方向d0的入射段与方向d1的出射段
+func (s *stroker) join(p, d0, d1 strokePoint) {
+	hw := s.halfWidth
+	cross := d0.x*d1.y - d0.y*d1.x
+	dot := d0.x*d1.x + d0.y*d1.y
+
+	const eps = 1e-9
+	if math.Abs(cross) < eps {
+		if dot > 0 {
+			// 共线，两段的矩形已经连续
+			return
+		}
+		// 180度折返：只有圆角连接需要补一个整圆
+		if s.style.Join == LineJoinRound {
+			circle := s.arc(p, 0, 2*math.Pi)
+			s.emit(circle[:len(circle)-1]...)
+		}
+		return
+	}
+
+	// 外侧在转向的相反一侧
+	side := 1.0
+	if cross > 0 {
+		side = -1
+	}
+	n0 := strokePoint{-d0.y * side, d0.x * side, 0}
+	n1 := strokePoint{-d1.y * side, d1.x * side, 0}
+	o0 := p.add(n0, hw)
+	o1 := p.add(n1, hw)
+
+	switch s.style.Join {
+	case LineJoinRound:
+		a0 := math.Atan2(n0.y, n0.x)
+		sweep := math.Atan2(n0.x*n1.y-n0.y*n1.x, n0.x*n1.x+n0.y*n1.y)
+		arc := s.arc(p, a0, sweep)
+		s.emit(append([]strokePoint{p}, arc...)...)
+	case LineJoinMiter:
+		// 斜接长度与线宽之比为 1/sin(θ/2)，θ为两段之间的夹角
+		ratio := 1 / math.Sqrt((1+dot)/2)
+		if ratio <= s.miterLimit {
+			mx, my := n0.x+n1.x, n0.y+n1.y
+			ml := math.Hypot(mx, my)
+			m := p.add(strokePoint{mx / ml, my / ml, 0}, hw*ratio)
+			s.emit(p, o0, m, o1)
+			return
+		}
+		s.emit(p, o0, o1)
+	default:
+		s.emit(p, o0, o1)
+	}
+}
+
+// cap 在端点p处添加朝向d的线帽
+func (s *stroker) cap(p, d strokePoint) {
+	hw := s.halfWidth
+	nrm := strokePoint{-d.y, d.x, 0}
+	switch s.style.Cap {
+	case LineCapSquare:
+		q := p.add(d, hw)
+		s.emit(p.add(nrm, hw), p.add(nrm, -hw), q.add(nrm, -hw), q.add(nrm, hw))
+	case LineCapRound:
+		a0 := math.Atan2(-nrm.y, -nrm.x)
+		s.emit(s.arc(p, a0, math.Pi)...)
+	}
+}
+
+// dashPiece 虚线切分后的一段折线
+type dashPiece struct {
+	pts    []strokePoint
+	closed bool
+}
+
+// dashPolyline 按虚线模式切分折线；模式为空或总长为0时原样返回
+func dashPolyline(pts []strokePoint, closed bool, dash []float32, offset float32) []dashPiece {
+	pattern := make([]float64, 0, len(dash)*2)
+	total := 0.0
+	for _, d := range dash {
+		if d < 0 {
+			return []dashPiece{{pts, closed}}
+		}
+		pattern = append(pattern, float64(d))
+		total += float64(d)
+	}
+	if len(pattern) == 0 || total <= 0 || len(pts) < 2 {
+		return []dashPiece{{pts, closed}}
+	}
+	if len(pattern)%2 == 1 {
+		pattern = append(pattern, pattern...)
+		total *= 2
+	}
+
+	// 根据偏移定位起始的虚线元素
+	idx := 0
+	rem := math.Mod(float64(offset), total)
+	if rem < 0 {
+		rem += total
+	}
+	// 偏移恰好落在零长度的实线段上时停在该段，使其画出一个点
+	for rem > pattern[idx] || (rem > 0 && rem == pattern[idx]) {
+		rem -= pattern[idx]
+		idx = (idx + 1) % len(pattern)
+	}
+	rem = pattern[idx] - rem
+	startsOn := idx%2 == 0
+
+	n := len(pts)
+	segCount := n - 1
+	if closed {
+		segCount = n
+	}
+
+	var pieces []dashPiece
+	var current []strokePoint
+	for i := 0; i < segCount; i++ {
+		a := pts[i]
+		b := pts[(i+1)%n]
+		l := math.Hypot(b.x-a.x, b.y-a.y)
+		pos := 0.0
+		if idx%2 == 0 && current == nil {
+			current = []strokePoint{a}
+		}
+		for l-pos > rem {
+			pos += rem
+			q := lerpStrokePoint(a, b, pos/l)
+			if idx%2 == 0 {
+				pieces = append(pieces, dashPiece{pts: append(current, q)})
+				current = nil
+			} else {
+				current = []strokePoint{q}
+			}
+			idx = (idx + 1) % len(pattern)
+			rem = pattern[idx]
+		}
+		rem -= l - pos
+		if idx%2 == 0 {
+			current = append(current, b)
+		}
+	}
+	if idx%2 == 0 && current != nil {
+		if closed && len(pieces) == 0 {
+			// 整个闭合折线位于同一段实线内，仍按闭合折线连接首尾
+			return []dashPiece{{pts, closed}}
+		}
+		if closed && startsOn {
+			// 闭合折线首尾相接的两段虚线合并为一段
+			pieces[0].pts = append(current, pieces[0].pts[1:]...)
+		} else {
+			pieces = append(pieces, dashPiece{pts: current})
+		}
+	} else if !closed && rem == 0 && pattern[(idx+1)%len(pattern)] == 0 {
+		// 间隔恰好在终点结束，其后零长度的实线段在终点画出一个点
+		pieces = append(pieces, dashPiece{pts: []strokePoint{pts[n-1]}})
+	}
+
+	// 去掉切分产生的重复点
+	for i := range pieces {
+		src := pieces[i].pts
+		dst := src[:1]
+		for _, p := range src[1:] {
+			last := dst[len(dst)-1]
+			if p.x != last.x || p.y != last.y {
+				dst = append(dst, p)
+			}
+		}
+		pieces[i].pts = dst
+	}
+	return pieces
+}
+
+// contourSignedArea 计算轮廓在XY平面上的有向面积，逆时针为正
+func contourSignedArea(c Contour) float64 {
+	area := 0.0
+	for i := range c {
+		a := c[i]
+		b := c[(i+1)%len(c)]
+		area += float64(a.X)*float64(b.Y) - float64(b.X)*float64(a.Y)
+	}
+	return area / 2
+}
+
+// reverseContour 原地反转轮廓方向
+func reverseContour(c Contour) {
+	for i, j := 0, len(c)-1; i < j; i, j = i+1, j-1 {
+		c[i], c[j] = c[j], c[i]
+	}
+}
@@ -0,0 +1,208 @@
+package tesselator
+
+import (
+	"math"
+	"testing"
+)
+
+// 三角形网格在XY平面上的总面积
+func meshArea(indices []int, vertices []Vertex) float64 {
+	area := 0.0
+	for i := 0; i+2 < len(indices); i += 3 {
+		a, b, c := vertices[indices[i]], vertices[indices[i+1]], vertices[indices[i+2]]
+		area += math.Abs(float64((b.X-a.X)*(c.Y-a.Y)-(c.X-a.X)*(b.Y-a.Y))) / 2
+	}
+	return area
+}
+
+// 统计覆盖点(x, y)的三角形数量（点在三角形内部）
+func meshCoverage(indices []int, vertices []Vertex, x, y float64) int {
+	count := 0
+	for i := 0; i+2 < len(indices); i += 3 {
+		a, b, c := vertices[indices[i]], vertices[indices[i+1]], vertices[indices[i+2]]
+		d1 := (float64(b.X)-float64(a.X))*(y-float64(a.Y)) - (float64(b.Y)-float64(a.Y))*(x-float64(a.X))
+		d2 := (float64(c.X)-float64(b.X))*(y-float64(b.Y)) - (float64(c.Y)-float64(b.Y))*(x-float64(b.X))
+		d3 := (float64(a.X)-float64(c.X))*(y-float64(c.Y)) - (float64(a.Y)-float64(c.Y))*(x-float64(c.X))
+		if (d1 > 0 && d2 > 0 && d3 > 0) || (d1 < 0 && d2 < 0 && d3 < 0) {
+			count++
+		}
+	}
+	return count
+}
+
+// TestStrokeStraightLine 测试直线描边的面积和线帽
+func TestStrokeStraightLine(t *testing.T) {
+	line := Contour{{X: 0, Y: 0}, {X: 10, Y: 0}}
+
+	cases := []struct {
+		cap  LineCap
+		area float64
+	}{
+		{LineCapButt, 20},
+		{LineCapSquare, 24},
+		{LineCapRound, 20 + math.Pi},
+	}
+	for _, c := range cases {
+		indices, vertices, err := Stroke(line, false, StrokeStyle{Width: 2, Cap: c.cap, Tolerance: 0.001})
+		if err != nil {
+			t.Fatalf("Stroke returned an error: %v", err)
+		}
+		area := meshArea(indices, vertices)
+		if math.Abs(area-c.area) > 0.01 {
+			t.Errorf("cap %d: expected area %.3f, got %.3f", c.cap, c.area, area)
+		}
+	}
+}
+
+// TestStrokeJoins 测试直角折线的三种连接方式
+func TestStrokeJoins(t *testing.T) {
+	polyline := Contour{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}}
+
+	// 两段矩形的并集面积为 20 + 20 - 1（拐角内侧重叠的1x1方块）
+	base := 39.0
+	cases := []struct {
+		join LineJoin
+		area float64
+	}{
+		{LineJoinBevel, base + 0.5},
+		{LineJoinMiter, base + 1},
+		{LineJoinRound, base + math.Pi/4},
+	}
+	for _, c := range cases {
+		indices, vertices, err := Stroke(polyline, false, StrokeStyle{Width: 2, Join: c.join, Tolerance: 0.001})
+		if err != nil {
+			t.Fatalf("Stroke returned an error: %v", err)
+		}
+		area := meshArea(indices, vertices)
+		if math.Abs(area-c.area) > 0.01 {
+			t.Errorf("join %d: expected area %.3f, got %.3f", c.join, c.area, area)
+		}
+	}
+}
+
+// TestStrokeMiterLimit 测试尖角超过斜接上限时退化为斜切
+func TestStrokeMiterLimit(t *testing.T) {
+	polyline := Contour{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 0, Y: 1}}
+
+	style := StrokeStyle{Width: 1, Join: LineJoinMiter, MiterLimit: 4}
+	contours := StrokeContours(polyline, false, style)
+	for _, c := range contours {
+		for _, v := range c {
+			if v.X > 11 {
+				t.Fatalf("miter should have been clipped, found vertex %v", v)
+			}
+		}
+	}
+
+	style.MiterLimit = 100
+	contours = StrokeContours(polyline, false, style)
+	maxX := float32(0)
+	for _, c := range contours {
+		for _, v := range c {
+			if v.X > maxX {
+				maxX = v.X
+			}
+		}
+	}
+	if maxX < 15 {
+		t.Errorf("expected a long miter tip, got max x %.2f", maxX)
+	}
+}
+
+// TestStrokeSelfOverlap 测试自相交折线的描边没有重叠三角形
+func TestStrokeSelfOverlap(t *testing.T) {
+	polyline := Contour{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 5, Y: 10}, {X: 5, Y: -5}}
+	indices, vertices, err := Stroke(polyline, false, StrokeStyle{Width: 3, Join: LineJoinRound, Cap: LineCapRound})
+	if err != nil {
+		t.Fatalf("Stroke returned an error: %v", err)
+	}
+	if len(indices) == 0 {
+		t.Fatal("expected triangles")
+	}
+	for x := -2.0; x <= 12; x += 0.37 {
+		for y := -7.0; y <= 12; y += 0.41 {
+			if n := meshCoverage(indices, vertices, x, y); n > 1 {
+				t.Fatalf("point (%.2f, %.2f) covered by %d triangles", x, y, n)
+			}
+		}
+	}
+	// 交叉处应当被覆盖
+	if meshCoverage(indices, vertices, 5.1, 0.1) != 1 {
+		t.Error("crossing point should be covered exactly once")
+	}
+}
+
+// TestStrokeClosed 测试闭合折线描边形成带孔的环
+func TestStrokeClosed(t *testing.T) {
+	square := Contour{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}
+	indices, vertices, err := Stroke(square, true, StrokeStyle{Width: 2, Join: LineJoinMiter})
+	if err != nil {
+		t.Fatalf("Stroke returned an error: %v", err)
+	}
+	area := meshArea(indices, vertices)
+	if math.Abs(area-(144-64)) > 0.01 {
+		t.Errorf("expected area 80, got %.3f", area)
+	}
+	if meshCoverage(indices, vertices, 5, 5) != 0 {
+		t.Error("center of a stroked square should not be filled")
+	}
+}
+
+// TestStrokeDash 测试虚线切分
+func TestStrokeDash(t *testing.T) {
+	line := Contour{{X: 0, Y: 0}, {X: 10, Y: 0}}
+	style := StrokeStyle{Width: 1, Dash: []float32{2, 1}}
+	indices, vertices, err := Stroke(line, false, style)
+	if err != nil {
+		t.Fatalf("Stroke returned an error: %v", err)
+	}
+	// 实线段为 [0,2] [3,5] [6,8] [9,10]
+	area := meshArea(indices, vertices)
+	if math.Abs(area-7) > 0.01 {
+		t.Errorf("expected dashed area 7, got %.3f", area)
+	}
+	if meshCoverage(indices, vertices, 2.5, 0.1) != 0 {
+		t.Error("gap should not be filled")
+	}
+
+	style.DashOffset = 1
+	indices, vertices, _ = Stroke(line, false, style)
+	if meshCoverage(indices, vertices, 0.5, 0.1) != 1 || meshCoverage(indices, vertices, 1.5, 0.1) != 0 {
+		t.Error("dash offset not applied")
+	}
+
+	// 开头的零长度实线段同样画出圆点
+	dots := StrokeStyle{Width: 1, Cap: LineCapRound, Dash: []float32{0, 2}, Tolerance: 0.001}
+	indices, vertices, _ = Stroke(line, false, dots)
+	if area := meshArea(indices, vertices); math.Abs(area-6*math.Pi/4) > 0.02 {
+		t.Errorf("expected 6 dots with area %.3f, got %.3f", 6*math.Pi/4, area)
+	}
+	if meshCoverage(indices, vertices, 0.1, 0.1) != 1 {
+		t.Error("leading zero-length dash should produce a dot")
+	}
+
+	// 整个位于一段实线内的闭合折线仍是带连接的闭合环，而不是带两个线帽的开放折线
+	square := Contour{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}
+	ring := StrokeStyle{Width: 2, Join: LineJoinMiter, Dash: []float32{100, 10}}
+	indices, vertices, _ = Stroke(square, true, ring)
+	if area := meshArea(indices, vertices); math.Abs(area-(144-64)) > 0.01 {
+		t.Errorf("expected a closed ring with area 80, got %.3f", area)
+	}
+}
+
+// TestStrokeDegenerate 测试退化输入
+func TestStrokeDegenerate(t *testing.T) {
+	if c := StrokeContours(Contour{}, false, StrokeStyle{Width: 1}); len(c) != 0 {
+		t.Errorf("expected no contours for empty polyline, got %d", len(c))
+	}
+	if c := StrokeContours(Contour{{X: 1, Y: 1}}, false, StrokeStyle{Width: 1}); len(c) != 0 {
+		t.Errorf("butt cap dot should be empty, got %d contours", len(c))
+	}
+	indices, vertices, err := Stroke(Contour{{X: 1, Y: 1}, {X: 1, Y: 1}}, false, StrokeStyle{Width: 2, Cap: LineCapSquare})
+	if err != nil {
+		t.Fatalf("Stroke returned an error: %v", err)
+	}
+	if area := meshArea(indices, vertices); math.Abs(area-4) > 0.01 {
+		t.Errorf("square cap dot: expected area 4, got %.3f", area)
+	}
+}
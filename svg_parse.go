@@ -0,0 +1,612 @@
+package tesselator
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SVGShape 从SVG文档中读取的一个图形
+type SVGShape struct {
+	ID          string      // 元素的id属性
+	Element     string      // 元素名，如path、rect、circle
+	Contours    []Contour   // 已应用transform的轮廓
+	WindingRule WindingRule // 由fill-rule映射：nonzero为WindingRuleNonzero，evenodd为WindingRuleOdd
+}
+
+// svgMatrix SVG仿射变换矩阵 [a b c d e f]：
+// x' = a*x + c*y + e, y' = b*x + d*y + f
+type svgMatrix [6]float64
+
+var svgIdentity = svgMatrix{1, 0, 0, 1, 0, 0}
+
+func (m svgMatrix) mul(n svgMatrix) svgMatrix {
+	return svgMatrix{
+		m[0]*n[0] + m[2]*n[1],
+		m[1]*n[0] + m[3]*n[1],
+		m[0]*n[2] + m[2]*n[3],
+		m[1]*n[2] + m[3]*n[3],
+		m[0]*n[4] + m[2]*n[5] + m[4],
+		m[1]*n[4] + m[3]*n[5] + m[5],
+	}
+}
+
+func (m svgMatrix) apply(v Vertex) Vertex {
+	x, y := float64(v.X), float64(v.Y)
+	return Vertex{
+		X: float32(m[0]*x + m[2]*y + m[4]),
+		Y: float32(m[1]*x + m[3]*y + m[5]),
+		Z: v.Z,
+	}
+}
+
+// maxScale 返回变换的最大缩放系数（线性部分的最大奇异值）
+func (m svgMatrix) maxScale() float64 {
+	a, b, c, d := m[0], m[1], m[2], m[3]
+	p := a*a + b*b
+	q := c*c + d*d
+	r := a*c + b*d
+	return math.Sqrt((p + q + math.Sqrt((p-q)*(p-q)+4*r*r)) / 2)
+}
+
+// ParseSVG 解析SVG文档中的path、polygon、polyline、rect、circle和ellipse元素，
+// 应用各级transform并按fill-rule确定环绕规则。曲线按tolerance（文档用户坐标系下
+// 变换后的单位）展平，<=0时使用DefaultFlattenTolerance。
+// defs、clipPath、mask、symbol等不直接渲染的内容会被跳过。
+func ParseSVG(r io.Reader, tolerance float32) ([]SVGShape, error) {
+	if tolerance <= 0 {
+		tolerance = DefaultFlattenTolerance
+	}
+
+	type state struct {
+		transform svgMatrix
+		fillRule  WindingRule
+		skip      bool
+	}
+	stack := []state{{transform: svgIdentity, fillRule: WindingRuleNonzero}}
+
+	var shapes []SVGShape
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("svg: %v", err)
+		}
+
+		switch el := tok.(type) {
+		case xml.StartElement:
+			parent := stack[len(stack)-1]
+			cur := parent
+			attrs := svgAttributes(el.Attr)
+
+			if t, ok := attrs["transform"]; ok {
+				m, err := parseSVGTransform(t)
+				if err != nil {
+					return nil, err
+				}
+				cur.transform = parent.transform.mul(m)
+			}
+			if fr, ok := attrs["fill-rule"]; ok {
+				switch strings.TrimSpace(fr) {
+				case "evenodd":
+					cur.fillRule = WindingRuleOdd
+				case "nonzero":
+					cur.fillRule = WindingRuleNonzero
+				}
+			}
+			switch el.Name.Local {
+			case "defs", "clipPath", "mask", "symbol", "pattern", "marker":
+				cur.skip = true
+			}
+			stack = append(stack, cur)
+			if cur.skip {
+				continue
+			}
+
+			path, err := svgElementPath(el.Name.Local, attrs)
+			if err != nil {
+				return nil, err
+			}
+			if path == nil {
+				continue
+			}
+
+			scale := cur.transform.maxScale()
+			if scale <= 0 {
+				continue
+			}
+			contours := path.Flatten(float32(float64(tolerance) / scale))
+			for _, c := range contours {
+				for i := range c {
+					c[i] = cur.transform.apply(c[i])
+				}
+			}
+			shapes = append(shapes, SVGShape{
+				ID:          attrs["id"],
+				Element:     el.Name.Local,
+				Contours:    contours,
+				WindingRule: cur.fillRule,
+			})
+		case xml.EndElement:
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+	return shapes, nil
+}
+
+// svgAttributes 收集元素属性，style中的声明覆盖同名的表现属性
+func svgAttributes(attrs []xml.Attr) map[string]string {
+	m := make(map[string]string, len(attrs))
+	for _, a := range attrs {
+		m[a.Name.Local] = a.Value
+	}
+	if style, ok := m["style"]; ok {
+		for _, decl := range strings.Split(style, ";") {
+			kv := strings.SplitN(decl, ":", 2)
+			if len(kv) == 2 {
+				m[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+			}
+		}
+	}
+	return m
+}
+
+// svgElementPath 将基本图形元素转换为路径，不是图形元素时返回nil
+func svgElementPath(name string, attrs map[string]string) (*Path, error) {
+	length := func(key string) (float64, error) {
+		s, ok := attrs[key]
+		if !ok {
+			return 0, nil
+		}
+		s = strings.TrimSuffix(strings.TrimSpace(s), "px")
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, fmt.Errorf("svg: invalid %s attribute %q on <%s>", key, attrs[key], name)
+		}
+		return v, nil
+	}
+	lengths := func(keys ...string) ([]float64, error) {
+		vs := make([]float64, len(keys))
+		for i, k := range keys {
+			v, err := length(k)
+			if err != nil {
+				return nil, err
+			}
+			vs[i] = v
+		}
+		return vs, nil
+	}
+
+	switch name {
+	case "path":
+		return ParseSVGPath(attrs["d"])
+
+	case "polygon", "polyline":
+		nums, err := parseSVGNumbers(attrs["points"])
+		if err != nil {
+			return nil, err
+		}
+		p := NewPath()
+		for i := 0; i+1 < len(nums); i += 2 {
+			if i == 0 {
+				p.MoveTo(float32(nums[i]), float32(nums[i+1]))
+			} else {
+				p.LineTo(float32(nums[i]), float32(nums[i+1]))
+			}
+		}
+		// 填充时polyline同样按闭合处理
+		p.Close()
+		return p, nil
+
+	case "rect":
+		vs, err := lengths("x", "y", "width", "height", "rx", "ry")
+		if err != nil {
+			return nil, err
+		}
+		x, y, w, h, rx, ry := vs[0], vs[1], vs[2], vs[3], vs[4], vs[5]
+		if w <= 0 || h <= 0 {
+			return nil, nil
+		}
+		_, hasRx := attrs["rx"]
+		_, hasRy := attrs["ry"]
+		if hasRx && !hasRy {
+			ry = rx
+		} else if hasRy && !hasRx {
+			rx = ry
+		}
+		rx = math.Min(math.Max(rx, 0), w/2)
+		ry = math.Min(math.Max(ry, 0), h/2)
+
+		p := NewPath()
+		f := func(v float64) float32 { return float32(v) }
+		if rx == 0 || ry == 0 {
+			p.MoveTo(f(x), f(y))
+			p.LineTo(f(x+w), f(y))
+			p.LineTo(f(x+w), f(y+h))
+			p.LineTo(f(x), f(y+h))
+		} else {
+			p.MoveTo(f(x+rx), f(y))
+			p.LineTo(f(x+w-rx), f(y))
+			p.ArcTo(f(rx), f(ry), 0, false, true, f(x+w), f(y+ry))
+			p.LineTo(f(x+w), f(y+h-ry))
+			p.ArcTo(f(rx), f(ry), 0, false, true, f(x+w-rx), f(y+h))
+			p.LineTo(f(x+rx), f(y+h))
+			p.ArcTo(f(rx), f(ry), 0, false, true, f(x), f(y+h-ry))
+			p.LineTo(f(x), f(y+ry))
+			p.ArcTo(f(rx), f(ry), 0, false, true, f(x+rx), f(y))
+		}
+		p.Close()
+		return p, nil
+
+	case "circle", "ellipse":
+		var cx, cy, rx, ry float64
+		if name == "circle" {
+			vs, err := lengths("cx", "cy", "r")
+			if err != nil {
+				return nil, err
+			}
+			cx, cy, rx, ry = vs[0], vs[1], vs[2], vs[2]
+		} else {
+			vs, err := lengths("cx", "cy", "rx", "ry")
+			if err != nil {
+				return nil, err
+			}
+			cx, cy, rx, ry = vs[0], vs[1], vs[2], vs[3]
+		}
+		if rx <= 0 || ry <= 0 {
+			return nil, nil
+		}
+		p := NewPath()
+		p.MoveTo(float32(cx+rx), float32(cy))
+		p.ArcTo(float32(rx), float32(ry), 0, false, true, float32(cx-rx), float32(cy))
+		p.ArcTo(float32(rx), float32(ry), 0, false, true, float32(cx+rx), float32(cy))
+		p.Close()
+		return p, nil
+	}
+	return nil, nil
+}
+
+// svgScanner 路径数据和数值列表的词法扫描器
+type svgScanner struct {
+	s   string
+	pos int
+}
+
+func (sc *svgScanner) skipSeparators() {
+	for sc.pos < len(sc.s) {
+		switch sc.s[sc.pos] {
+		case ' ', '\t', '\n', '\r', '\f', ',':
+			sc.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (sc *svgScanner) done() bool {
+	sc.skipSeparators()
+	return sc.pos >= len(sc.s)
+}
+
+// peekNumber 判断下一个记号是否是数值
+func (sc *svgScanner) peekNumber() bool {
+	sc.skipSeparators()
+	if sc.pos >= len(sc.s) {
+		return false
+	}
+	c := sc.s[sc.pos]
+	return c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9')
+}
+
+func (sc *svgScanner) number() (float64, error) {
+	sc.skipSeparators()
+	start := sc.pos
+	i := sc.pos
+	if i < len(sc.s) && (sc.s[i] == '+' || sc.s[i] == '-') {
+		i++
+	}
+	digits := false
+	for i < len(sc.s) && sc.s[i] >= '0' && sc.s[i] <= '9' {
+		i++
+		digits = true
+	}
+	if i < len(sc.s) && sc.s[i] == '.' {
+		i++
+		for i < len(sc.s) && sc.s[i] >= '0' && sc.s[i] <= '9' {
+			i++
+			digits = true
+		}
+	}
+	if !digits {
+		return 0, fmt.Errorf("svg: expected number at offset %d in %q", start, sc.s)
+	}
+	if i < len(sc.s) && (sc.s[i] == 'e' || sc.s[i] == 'E') {
+		j := i + 1
+		if j < len(sc.s) && (sc.s[j] == '+' || sc.s[j] == '-') {
+			j++
+		}
+		if j < len(sc.s) && sc.s[j] >= '0' && sc.s[j] <= '9' {
+			for j < len(sc.s) && sc.s[j] >= '0' && sc.s[j] <= '9' {
+				j++
+			}
+			i = j
+		}
+	}
+	v, err := strconv.ParseFloat(sc.s[start:i], 64)
+	if err != nil {
+		return 0, fmt.Errorf("svg: invalid number %q", sc.s[start:i])
+	}
+	sc.pos = i
+	return v, nil
+}
+
+// flag 读取弧命令中的标志位，标志位后面可以不带分隔符
+func (sc *svgScanner) flag() (bool, error) {
+	sc.skipSeparators()
+	if sc.pos < len(sc.s) {
+		switch sc.s[sc.pos] {
+		case '0':
+			sc.pos++
+			return false, nil
+		case '1':
+			sc.pos++
+			return true, nil
+		}
+	}
+	return false, fmt.Errorf("svg: expected arc flag at offset %d in %q", sc.pos, sc.s)
+}
+
+func (sc *svgScanner) numbers(n int) ([]float64, error) {
+	vs := make([]float64, n)
+	for i := range vs {
+		v, err := sc.number()
+		if err != nil {
+			return nil, err
+		}
+		vs[i] = v
+	}
+	return vs, nil
+}
+
+func parseSVGNumbers(s string) ([]float64, error) {
+	sc := &svgScanner{s: s}
+	var vs []float64
+	for !sc.done() {
+		v, err := sc.number()
+		if err != nil {
+			return nil, err
+		}
+		vs = append(vs, v)
+	}
+	return vs, nil
+}
+
+// ParseSVGPath 解析SVG路径数据（path元素的d属性），支持全部绝对和相对命令，
+// 包括平滑曲线S/T和椭圆弧A。
+func ParseSVGPath(d string) (*Path, error) {
+	p := NewPath()
+	sc := &svgScanner{s: d}
+
+	var (
+		cmd        byte
+		cur, start [2]float64
+		lastCtrl   [2]float64 // 上一条曲线的最后一个控制点，用于S/T的反射
+		lastCmd    byte
+	)
+	f := func(v float64) float32 { return float32(v) }
+
+	for !sc.done() {
+		c := sc.s[sc.pos]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') {
+			cmd = c
+			sc.pos++
+		} else if cmd == 0 {
+			return nil, fmt.Errorf("svg: path data must start with a command: %q", d)
+		}
+		// 隐式重复的MoveTo之后按LineTo处理
+		if lastCmd == 'M' && cmd == 'M' && c != 'M' {
+			cmd = 'L'
+		} else if lastCmd == 'm' && cmd == 'm' && c != 'm' {
+			cmd = 'l'
+		}
+
+		rel := cmd >= 'a' && cmd <= 'z'
+		base := [2]float64{}
+		if rel {
+			base = cur
+		}
+		upper := cmd
+		if rel {
+			upper = cmd - 'a' + 'A'
+		}
+
+		switch upper {
+		case 'M':
+			v, err := sc.numbers(2)
+			if err != nil {
+				return nil, err
+			}
+			cur = [2]float64{base[0] + v[0], base[1] + v[1]}
+			start = cur
+			p.MoveTo(f(cur[0]), f(cur[1]))
+		case 'L':
+			v, err := sc.numbers(2)
+			if err != nil {
+				return nil, err
+			}
+			cur = [2]float64{base[0] + v[0], base[1] + v[1]}
+			p.LineTo(f(cur[0]), f(cur[1]))
+		case 'H':
+			v, err := sc.number()
+			if err != nil {
+				return nil, err
+			}
+			cur[0] = base[0] + v
+			p.LineTo(f(cur[0]), f(cur[1]))
+		case 'V':
+			v, err := sc.number()
+			if err != nil {
+				return nil, err
+			}
+			cur[1] = base[1] + v
+			p.LineTo(f(cur[0]), f(cur[1]))
+		case 'C', 'S':
+			var c1 [2]float64
+			var v []float64
+			var err error
+			if upper == 'C' {
+				if v, err = sc.numbers(6); err != nil {
+					return nil, err
+				}
+				c1 = [2]float64{base[0] + v[0], base[1] + v[1]}
+				v = v[2:]
+			} else {
+				if v, err = sc.numbers(4); err != nil {
+					return nil, err
+				}
+				c1 = cur
+				if lastCmd == 'C' || lastCmd == 'S' || lastCmd == 'c' || lastCmd == 's' {
+					c1 = [2]float64{2*cur[0] - lastCtrl[0], 2*cur[1] - lastCtrl[1]}
+				}
+			}
+			c2 := [2]float64{base[0] + v[0], base[1] + v[1]}
+			cur = [2]float64{base[0] + v[2], base[1] + v[3]}
+			p.CubicTo(f(c1[0]), f(c1[1]), f(c2[0]), f(c2[1]), f(cur[0]), f(cur[1]))
+			lastCtrl = c2
+		case 'Q', 'T':
+			var c1 [2]float64
+			if upper == 'Q' {
+				v, err := sc.numbers(4)
+				if err != nil {
+					return nil, err
+				}
+				c1 = [2]float64{base[0] + v[0], base[1] + v[1]}
+				cur = [2]float64{base[0] + v[2], base[1] + v[3]}
+			} else {
+				v, err := sc.numbers(2)
+				if err != nil {
+					return nil, err
+				}
+				c1 = cur
+				if lastCmd == 'Q' || lastCmd == 'T' || lastCmd == 'q' || lastCmd == 't' {
+					c1 = [2]float64{2*cur[0] - lastCtrl[0], 2*cur[1] - lastCtrl[1]}
+				}
+				cur = [2]float64{base[0] + v[0], base[1] + v[1]}
+			}
+			p.QuadTo(f(c1[0]), f(c1[1]), f(cur[0]), f(cur[1]))
+			lastCtrl = c1
+		case 'A':
+			v, err := sc.numbers(3)
+			if err != nil {
+				return nil, err
+			}
+			large, err := sc.flag()
+			if err != nil {
+				return nil, err
+			}
+			sweep, err := sc.flag()
+			if err != nil {
+				return nil, err
+			}
+			e, err := sc.numbers(2)
+			if err != nil {
+				return nil, err
+			}
+			cur = [2]float64{base[0] + e[0], base[1] + e[1]}
+			p.ArcTo(f(v[0]), f(v[1]), f(v[2]), large, sweep, f(cur[0]), f(cur[1]))
+		case 'Z':
+			p.Close()
+			cur = start
+			lastCmd = cmd
+			// Z不带参数，后面必须跟新的命令
+			if sc.peekNumber() {
+				return nil, fmt.Errorf("svg: unexpected number after Z in %q", d)
+			}
+			continue
+		default:
+			return nil, fmt.Errorf("svg: unknown path command %q", cmd)
+		}
+		lastCmd = cmd
+	}
+	return p, nil
+}
+
+// parseSVGTransform 解析transform属性中的变换列表
+func parseSVGTransform(s string) (svgMatrix, error) {
+	m := svgIdentity
+	rest := strings.TrimSpace(s)
+	for rest != "" {
+		open := strings.IndexByte(rest, '(')
+		end := strings.IndexByte(rest, ')')
+		if open < 0 || end < open {
+			return m, fmt.Errorf("svg: invalid transform %q", s)
+		}
+		name := strings.TrimSpace(rest[:open])
+		args, err := parseSVGNumbers(rest[open+1 : end])
+		if err != nil {
+			return m, err
+		}
+		rest = strings.TrimLeft(rest[end+1:], " \t\n\r,")
+
+		bad := fmt.Errorf("svg: invalid arguments for %s in transform %q", name, s)
+		var t svgMatrix
+		switch name {
+		case "matrix":
+			if len(args) != 6 {
+				return m, bad
+			}
+			copy(t[:], args)
+		case "translate":
+			switch len(args) {
+			case 1:
+				t = svgMatrix{1, 0, 0, 1, args[0], 0}
+			case 2:
+				t = svgMatrix{1, 0, 0, 1, args[0], args[1]}
+			default:
+				return m, bad
+			}
+		case "scale":
+			switch len(args) {
+			case 1:
+				t = svgMatrix{args[0], 0, 0, args[0], 0, 0}
+			case 2:
+				t = svgMatrix{args[0], 0, 0, args[1], 0, 0}
+			default:
+				return m, bad
+			}
+		case "rotate":
+			if len(args) != 1 && len(args) != 3 {
+				return m, bad
+			}
+			a := args[0] * math.Pi / 180
+			cos, sin := math.Cos(a), math.Sin(a)
+			t = svgMatrix{cos, sin, -sin, cos, 0, 0}
+			if len(args) == 3 {
+				cx, cy := args[1], args[2]
+				t = svgMatrix{1, 0, 0, 1, cx, cy}.mul(t).mul(svgMatrix{1, 0, 0, 1, -cx, -cy})
+			}
+		case "skewX":
+			if len(args) != 1 {
+				return m, bad
+			}
+			t = svgMatrix{1, 0, math.Tan(args[0] * math.Pi / 180), 1, 0, 0}
+		case "skewY":
+			if len(args) != 1 {
+				return m, bad
+			}
+			t = svgMatrix{1, math.Tan(args[0] * math.Pi / 180), 0, 1, 0, 0}
+		default:
+			return m, fmt.Errorf("svg: unknown transform %q", name)
+		}
+		m = m.mul(t)
+	}
+	return m, nil
+}
@@ -0,0 +1,189 @@
+package tesselator
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// 路径填充后的面积
+func pathArea(t *testing.T, p *Path, rule WindingRule) float64 {
+	t.Helper()
+	indices, vertices, err := p.Tesselate(rule, 0.001)
+	if err != nil {
+		t.Fatalf("Tesselate returned an error: %v", err)
+	}
+	return meshArea(indices, vertices)
+}
+
+// TestParseSVGPathCommands 测试绝对和相对命令
+func TestParseSVGPathCommands(t *testing.T) {
+	cases := []struct {
+		d    string
+		area float64
+	}{
+		{"M0 0 L10 0 10 10 0 10z", 100},
+		{"m0,0 h10 v10 h-10 z", 100},
+		{"M 0 0 H 10 V 10 H 0 Z", 100},
+		{"m5 5 l10 0 0 10 -10 0z", 100},
+		{"M0,0L1e1,0L10,1e1L0,10Z", 100},
+		// 两个子路径，第二个为相对坐标
+		{"M0 0h10v10h-10zm2 2h6v6h-6z", 64},
+		// 紧凑的数字写法
+		{"M.5.5L10.5.5 10.5 10.5.5 10.5z", 100},
+		// 半圆：半径为5
+		{"M0 0A5 5 0 0 1 10 0Z", math.Pi * 25 / 2},
+		// 不带分隔符的弧标志位
+		{"M0 0a5 5 0 0110 0z", math.Pi * 25 / 2},
+	}
+	for _, c := range cases {
+		p, err := ParseSVGPath(c.d)
+		if err != nil {
+			t.Fatalf("%q: %v", c.d, err)
+		}
+		if area := pathArea(t, p, WindingRuleOdd); math.Abs(area-c.area) > 0.02 {
+			t.Errorf("%q: expected area %.3f, got %.3f", c.d, c.area, area)
+		}
+	}
+}
+
+// TestParseSVGPathSmoothCurves 测试S和T命令的控制点反射
+func TestParseSVGPathSmoothCurves(t *testing.T) {
+	// 两段对称的二次曲线与用Q显式写出的结果相同
+	smooth, err := ParseSVGPath("M0 0 Q5 10 10 0 T20 0 Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	explicit, _ := ParseSVGPath("M0 0 Q5 10 10 0 Q15 -10 20 0 Z")
+	a1 := pathArea(t, smooth, WindingRuleOdd)
+	a2 := pathArea(t, explicit, WindingRuleOdd)
+	if math.Abs(a1-a2) > 1e-3 || a1 < 1 {
+		t.Errorf("T reflection mismatch: %.3f vs %.3f", a1, a2)
+	}
+
+	smooth, err = ParseSVGPath("m0 0 c0 10 10 10 10 0 s10 -10 10 0 z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	explicit, _ = ParseSVGPath("M0 0 C0 10 10 10 10 0 C10 -10 20 -10 20 0 Z")
+	a1 = pathArea(t, smooth, WindingRuleOdd)
+	a2 = pathArea(t, explicit, WindingRuleOdd)
+	if math.Abs(a1-a2) > 1e-3 || a1 < 1 {
+		t.Errorf("S reflection mismatch: %.3f vs %.3f", a1, a2)
+	}
+}
+
+// TestParseSVGPathErrors 测试非法的路径数据
+func TestParseSVGPathErrors(t *testing.T) {
+	for _, d := range []string{"10 10", "M0 0 L10", "M0 0 X5 5", "M0 0 A5 5 0 2 0 1 1", "M0 0 Z 5"} {
+		if _, err := ParseSVGPath(d); err == nil {
+			t.Errorf("%q: expected an error", d)
+		}
+	}
+	if p, err := ParseSVGPath(""); err != nil || len(p.Flatten(0)) != 0 {
+		t.Errorf("empty path should parse to an empty path")
+	}
+}
+
+// TestParseSVGDocument 测试SVG文档中的各种元素、变换和填充规则
+func TestParseSVGDocument(t *testing.T) {
+	doc := `<?xml version="1.0"?>
+<svg xmlns="http://www.w3.org/2000/svg" width="200" height="200">
+  <defs>
+    <rect id="hidden" width="1000" height="1000"/>
+  </defs>
+  <g transform="translate(100, 0)" fill-rule="evenodd">
+    <path id="frame" d="M0 0h10v10h-10zM2 2h6v6h-6z"/>
+    <rect id="rounded" x="20" y="0" width="10" height="10" rx="2" style="fill-rule: nonzero"/>
+  </g>
+  <circle id="circle" cx="0" cy="0" r="5" transform="scale(2)"/>
+  <ellipse id="ellipse" cx="50" cy="50" rx="4" ry="2"/>
+  <polygon id="triangle" points="0,0 10,0 0,10"/>
+  <polyline id="line" points="0 0 4 0 4 4"/>
+  <rect id="rotated" width="10" height="5" transform="rotate(90 5 5)"/>
+</svg>`
+	shapes, err := ParseSVG(strings.NewReader(doc), 0.001)
+	if err != nil {
+		t.Fatalf("ParseSVG returned an error: %v", err)
+	}
+
+	byID := map[string]SVGShape{}
+	for _, s := range shapes {
+		byID[s.ID] = s
+	}
+	if _, ok := byID["hidden"]; ok {
+		t.Error("shapes inside <defs> should be skipped")
+	}
+	if len(shapes) != 7 {
+		t.Fatalf("expected 7 shapes, got %d", len(shapes))
+	}
+
+	area := func(s SVGShape) float64 {
+		indices, vertices, err := Tesselate(s.Contours, s.WindingRule)
+		if err != nil {
+			t.Fatalf("%s: %v", s.ID, err)
+		}
+		return meshArea(indices, vertices)
+	}
+
+	frame := byID["frame"]
+	if frame.WindingRule != WindingRuleOdd {
+		t.Error("frame should inherit fill-rule evenodd")
+	}
+	if a := area(frame); math.Abs(a-64) > 1e-3 {
+		t.Errorf("frame: expected area 64, got %.3f", a)
+	}
+	if x := frame.Contours[0][0].X; x != 100 {
+		t.Errorf("frame: group translate not applied, first x = %v", x)
+	}
+
+	rounded := byID["rounded"]
+	if rounded.WindingRule != WindingRuleNonzero {
+		t.Error("style fill-rule should override the inherited value")
+	}
+	if a := area(rounded); math.Abs(a-(100-(4-math.Pi)*4)) > 0.01 {
+		t.Errorf("rounded rect: unexpected area %.3f", a)
+	}
+
+	if a := area(byID["circle"]); math.Abs(a-math.Pi*100) > 0.05 {
+		t.Errorf("scaled circle: expected area %.3f, got %.3f", math.Pi*100, a)
+	}
+	if a := area(byID["ellipse"]); math.Abs(a-math.Pi*8) > 0.01 {
+		t.Errorf("ellipse: expected area %.3f, got %.3f", math.Pi*8, a)
+	}
+	if a := area(byID["triangle"]); math.Abs(a-50) > 1e-3 {
+		t.Errorf("polygon: expected area 50, got %.3f", a)
+	}
+	if a := area(byID["line"]); math.Abs(a-8) > 1e-3 {
+		t.Errorf("polyline: expected area 8, got %.3f", a)
+	}
+
+	// 绕(5,5)旋转90度后，矩形占据 x∈[5,10], y∈[0,10]
+	for _, v := range byID["rotated"].Contours[0] {
+		if v.X < 5-1e-4 || v.X > 10+1e-4 || v.Y < -1e-4 || v.Y > 10+1e-4 {
+			t.Errorf("rotated rect vertex out of place: %v", v)
+		}
+	}
+}
+
+// TestParseSVGTransform 测试变换列表
+func TestParseSVGTransform(t *testing.T) {
+	m, err := parseSVGTransform("translate(10) scale(2, 3) skewX(45)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := m.apply(Vertex{X: 1, Y: 1})
+	// skewX(45): (2,1) -> scale: (4,3) -> translate: (14,3)
+	if math.Abs(float64(v.X)-14) > 1e-5 || math.Abs(float64(v.Y)-3) > 1e-5 {
+		t.Errorf("unexpected transformed point %v", v)
+	}
+	if _, err := parseSVGTransform("shear(1)"); err == nil {
+		t.Error("expected an error for unknown transform")
+	}
+	if _, err := parseSVGTransform("matrix(1 2 3)"); err == nil {
+		t.Error("expected an error for short matrix")
+	}
+	if _, err := ParseSVG(strings.NewReader(`<svg><path d="M0 0 Q"/></svg>`), 0); err == nil {
+		t.Error("expected an error for invalid path data")
+	}
+}
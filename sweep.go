@@ -152,6 +152,8 @@ func edgeLeq(tess *tesselator, reg1 *activeRegion, reg2 *activeRegion) bool {
 func addWinding(eDst *halfEdge, eSrc *halfEdge) {
 	eDst.winding += eSrc.winding
 	eDst.Sym.winding += eSrc.Sym.winding
+	eDst.constrained = eDst.constrained || eSrc.constrained
+	eDst.Sym.constrained = eDst.constrained
 }
 
 // fixUpperEdge replace an upper edge which needs fixing (see ConnectRightVertex).
@@ -346,9 +348,19 @@ func addRightEdges(tess *tesselator, regUp *activeRegion, eFirst *halfEdge, eLas
 // Two vertices with idential coordinates are combined into one.
 // e1.Org is kept, while e2.Org is discarded.
 func spliceMergeVertices(tess *tesselator, e1 *halfEdge, e2 *halfEdge) {
+	noteTouch(tess, e1.Org)
 	tessMeshSplice(tess.mesh, e1, e2)
 }
 
+// noteTouch records the location of a vertex where edges or vertices of the
+// input met during the sweep (merged vertices, a vertex spliced into an edge,
+// or a computed intersection).  Used by Validate; does nothing otherwise.
+func noteTouch(tess *tesselator, v *vertex) {
+	if tess.recordTouches {
+		tess.touches = append(tess.touches, v.coords)
+	}
+}
+
 // vertexWeights finds some weights which describe how the intersection vertex is
 // a linear combination of "org" and "dest".  Each of the two edges
 // which generated "isect" is allocated 50% of the weight; each edge
@@ -416,6 +428,7 @@ func checkForRightSplice(tess *tesselator, regUp *activeRegion) bool {
 		// eUp.Org appears to be below eLo
 		if !vertEq(eUp.Org, eLo.Org) {
 			// Splice eUp.Org into eLo
+			noteTouch(tess, eUp.Org)
 			tessMeshSplitEdge(tess.mesh, eLo.Sym)
 			tessMeshSplice(tess.mesh, eUp, eLo.oPrev())
 			regUp.dirty = true
@@ -432,6 +445,7 @@ func checkForRightSplice(tess *tesselator, regUp *activeRegion) bool {
 		}
 
 		// eLo.Org appears to be above eUp, so splice eLo.Org into eUp
+		noteTouch(tess, eLo.Org)
 		regUp.above().dirty = true
 		regUp.dirty = true
 		tessMeshSplitEdge(tess.mesh, eUp.Sym)
@@ -469,6 +483,7 @@ func checkForLeftSplice(tess *tesselator, regUp *activeRegion) bool {
 		}
 
 		// eLo.Dst is above eUp, so splice eLo.Dst into eUp
+		noteTouch(tess, eLo.dst())
 		regUp.above().dirty = true
 		regUp.dirty = true
 		e := tessMeshSplitEdge(tess.mesh, eUp)
@@ -479,6 +494,7 @@ func checkForLeftSplice(tess *tesselator, regUp *activeRegion) bool {
 			return false
 		}
 		// eUp.Dst is below eLo, so splice eUp.Dst into eLo
+		noteTouch(tess, eUp.dst())
 		regUp.dirty = true
 		regLo.dirty = true
 		e := tessMeshSplitEdge(tess.mesh, eLo)
@@ -575,6 +591,7 @@ func checkForIntersect(tess *tesselator, regUp *activeRegion) bool {
 		// Very unusual -- the new upper or lower edge would pass on the
 		// wrong side of the sweep event, or through it.  This can happen
 		// due to very small numerical errors in the intersection calculation.
+		noteTouch(tess, tess.event)
 		if dstLo == tess.event {
 			// Splice dstLo into eUp, and process the new region(s)
 			tessMeshSplitEdge(tess.mesh, eUp.Sym)
@@ -632,6 +649,7 @@ func checkForIntersect(tess *tesselator, regUp *activeRegion) bool {
 	eUp.Org.t = isect.t
 	eUp.Org.pqHandle = tess.pq.insert(eUp.Org)
 	getIntersectData(tess, eUp.Org, orgUp, dstUp, orgLo, dstLo)
+	noteTouch(tess, eUp.Org)
 	regUp.above().dirty = true
 	regUp.dirty = true
 	regLo.dirty = true
@@ -814,8 +832,9 @@ func connectLeftDegenerate(tess *tesselator, regUp *activeRegion, vEvent *vertex
 		return
 	}
 
-	if vertEq(e.dst(), vEvent) {
+	if !vertEq(e.dst(), vEvent) {
 		// General case -- splice vEvent into edge e which passes through it
+		noteTouch(tess, vEvent)
 		tessMeshSplitEdge(tess.mesh, e.Sym)
 		if regUp.fixUpperEdge {
 			// This edge was fixable -- delete unused portion of original edge
@@ -1041,7 +1060,7 @@ func removeDegenerateEdges(tess *tesselator) {
 			e = eLnext
 			eLnext = e.Lnext
 		}
-		if eLnext.Lnext == e {
+		if eLnext.Lnext == e && !(tess.keepDegenerateContours && e.winding == 0 && eLnext.winding == 0) {
 			// Degenerate contour (one or two edges)
 			if eLnext != e {
 				if eLnext == eNext || eLnext == eNext.Sym {
@@ -15,6 +15,10 @@ const (
 	WindingRulePositive
 	WindingRuleNegative
 	WindingRuleAbsGeqTwo
+	// WindingRuleNesting 不依赖输入方向的奇偶嵌套规则：先按每个轮廓被其他轮廓完全包含的层数
+	// 把偶数层调整为外轮廓（逆时针）、奇数层调整为洞（顺时针），再填充环绕数为正的区域。
+	// 部分重叠的外轮廓被合并，部分重叠的洞仍然都是洞
+	WindingRuleNesting
 )
 
 func (w WindingRule) isInside(n int) bool {
@@ -29,6 +33,9 @@ func (w WindingRule) isInside(n int) bool {
 		return (n < 0)
 	case WindingRuleAbsGeqTwo:
 		return (n >= 2) || (n <= -2)
+	case WindingRuleNesting:
+		// 轮廓已在resolveNesting中调整方向，外轮廓的环绕数为正
+		return (n > 0)
 	}
 	panic("not reached")
 }
@@ -133,6 +140,21 @@ type tesselator struct {
 
 	vertexIndexCounter index
 
+	// 扫描中顶点合并、顶点落在边上和边相交的位置，只在recordTouches为true时记录
+	recordTouches bool
+	touches       [][3]float
+
+	// 保留只有一两条边且环绕数为0的轮廓，使单独的线段和约束折线也能参与扫描
+	keepDegenerateContours bool
+
+	// 剖分后插入填充区域内的孤立点
+	steinerPoints []Vertex
+	// 剖分后翻转边，得到约束Delaunay三角剖分
+	delaunay bool
+	// 剖分后拆分长度超过maxEdgeLength的边，长度和新顶点由refiner计算
+	refiner       edgeRefiner
+	maxEdgeLength float64
+
 	vertices      []float
 	vertexIndices []index
 	vertexCount   int
@@ -149,6 +171,11 @@ type Vertex struct {
 type Contour []Vertex
 
 func Tesselate(contours []Contour, windingRule WindingRule) ([]int, []Vertex, error) {
+	return tesselateWith(&tesselator{}, contours, windingRule)
+}
+
+// tesselateWith 与Tesselate相同，但使用调用者提供的tesselator，以便设置剖分后的处理选项
+func tesselateWith(t *tesselator, contours []Contour, windingRule WindingRule) ([]int, []Vertex, error) {
 	// 预处理轮廓，过滤掉顶点数少于3的轮廓
 	validContours := make([]Contour, 0, len(contours))
 	for _, c := range contours {
@@ -162,17 +189,9 @@ func Tesselate(contours []Contour, windingRule WindingRule) ([]int, []Vertex, er
 	if len(validContours) == 0 {
 		return []int{}, []Vertex{}, nil
 	}
+	validContours, windingRule = resolveNesting(validContours, windingRule)
 
-	t := &tesselator{}
-	for _, c := range validContours {
-		fs := make([]float32, len(c)*3)
-		for i, v := range c {
-			fs[3*i] = v.X
-			fs[3*i+1] = v.Y
-			fs[3*i+2] = v.Z
-		}
-		tessAddContour(t, 3, fs)
-	}
+	addContours(t, validContours)
 
 	const (
 		polySize   = 3
@@ -202,6 +221,158 @@ func Tesselate(contours []Contour, windingRule WindingRule) ([]int, []Vertex, er
 	return elements, vertices, nil
 }
 
+// addContours 把轮廓加入tesselator
+func addContours(t *tesselator, contours []Contour) {
+	for _, c := range contours {
+		fs := make([]float32, len(c)*3)
+		for i, v := range c {
+			fs[3*i] = v.X
+			fs[3*i+1] = v.Y
+			fs[3*i+2] = v.Z
+		}
+		tessAddContour(t, 3, fs)
+	}
+}
+
+// computeInteriorXY 把轮廓投影到XY平面并按环绕规则标记内部区域。法线方向和朝向检查
+// 与Tesselate自动计算法线时一致，使环绕规则的含义和扫描结果都与Tesselate相同。
+// flipped表示扫描平面的t轴与y轴反向，此时输出需要翻转方向。没有有效轮廓时返回nil
+func computeInteriorXY(contours []Contour, windingRule WindingRule) (*mesh, bool, error) {
+	return computeInteriorXYWith(&tesselator{}, contours, windingRule)
+}
+
+// computeInteriorXYWith 与computeInteriorXY相同，但使用调用者提供的tesselator，
+// 以便在扫描前设置记录选项
+func computeInteriorXYWith(t *tesselator, contours []Contour, windingRule WindingRule) (*mesh, bool, error) {
+	validContours := make([]Contour, 0, len(contours))
+	for _, c := range contours {
+		if len(c) >= 3 {
+			validContours = append(validContours, c)
+		}
+	}
+	if len(validContours) == 0 {
+		return nil, false, nil
+	}
+	validContours, windingRule = resolveNesting(validContours, windingRule)
+
+	addContours(t, validContours)
+	if t.mesh == nil {
+		return nil, false, fmt.Errorf("libtess2: tessTesselate failed")
+	}
+
+	norm := make([]float, 3)
+	computeNormal(t, norm)
+	t.normal = [3]float{0, 0, 1}
+	if norm[2] < 0 {
+		t.normal[2] = -1
+	}
+	t.windingRule = windingRule
+	tessProjectPolygon(t)
+	checkOrientation(t)
+	// 按检查后的朝向重新投影，同时更新sweep使用的ST范围
+	t.normal[2] = t.tUnit[1]
+	tessProjectPolygon(t)
+	tessComputeInterior(t)
+	return t.mesh, t.tUnit[1] < 0, nil
+}
+
+// boundaryContours 按环绕规则求出区域的边界轮廓，自相交和重叠都已消除。
+// 外轮廓在XY平面上为逆时针，洞为顺时针
+func boundaryContours(contours []Contour, windingRule WindingRule) ([]Contour, error) {
+	mesh, flipped, err := computeInteriorXY(contours, windingRule)
+	if err != nil || mesh == nil {
+		return nil, err
+	}
+	tessMeshSetWindingNumber(mesh, 1, true)
+
+	var result []Contour
+	for f := mesh.fHead.next; f != &mesh.fHead; f = f.next {
+		if !f.inside {
+			continue
+		}
+		var c Contour
+		edge := f.anEdge
+		for {
+			c = append(c, Vertex{X: float32(edge.Org.coords[0]), Y: float32(edge.Org.coords[1]), Z: float32(edge.Org.coords[2])})
+			edge = edge.Lnext
+			if edge == f.anEdge {
+				break
+			}
+		}
+		if flipped {
+			reverseContour(c)
+		}
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+// tesselateWithBoundary 在XY平面上进行三角剖分，并从同一个网格中
+// 取出内部与外部之间的分界边。三角形在XY平面上为逆时针，分界边的方向使内部位于左侧，
+// 三角形和分界边共享顶点编号。环绕规则的含义与Tesselate相同。
+func tesselateWithBoundary(contours []Contour, windingRule WindingRule) ([]int, []Vertex, [][2]int, error) {
+	mesh, flipped, err := computeInteriorXY(contours, windingRule)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if mesh == nil {
+		return []int{}, []Vertex{}, nil, nil
+	}
+	indices, vertices, boundary := meshTrianglesWithBoundary(mesh, flipped)
+	return indices, vertices, boundary, nil
+}
+
+// meshTrianglesWithBoundary 剖分已标记内部区域的网格，取出三角形和内外分界边。
+// flipped的含义与computeInteriorXY的返回值相同
+func meshTrianglesWithBoundary(mesh *mesh, flipped bool) ([]int, []Vertex, [][2]int) {
+	tessMeshTessellateInterior(mesh)
+	tessMeshCheckMesh(mesh)
+
+	for v := mesh.vHead.next; v != &mesh.vHead; v = v.next {
+		v.n = undef
+	}
+	var vertices []Vertex
+	var indices []int
+	for f := mesh.fHead.next; f != &mesh.fHead; f = f.next {
+		if !f.inside {
+			continue
+		}
+		edge := f.anEdge
+		for {
+			v := edge.Org
+			if v.n == undef {
+				v.n = index(len(vertices))
+				vertices = append(vertices, Vertex{X: float32(v.coords[0]), Y: float32(v.coords[1]), Z: float32(v.coords[2])})
+			}
+			indices = append(indices, int(v.n))
+			edge = edge.Lnext
+			if edge == f.anEdge {
+				break
+			}
+		}
+	}
+
+	var boundary [][2]int
+	for e := mesh.eHead.next; e != &mesh.eHead; e = e.next {
+		left, right := e.Lface, e.rFace()
+		leftInside := left != nil && left.inside
+		rightInside := right != nil && right.inside
+		if leftInside != rightInside {
+			a, b := int(e.Org.n), int(e.dst().n)
+			if rightInside != flipped {
+				a, b = b, a
+			}
+			boundary = append(boundary, [2]int{a, b})
+		}
+	}
+	if flipped {
+		for i := 0; i < len(indices); i += 3 {
+			indices[i+1], indices[i+2] = indices[i+2], indices[i+1]
+		}
+	}
+	return indices, vertices, boundary
+}
+
 func abs(x float) float {
 	if x < 0 {
 		return -x
@@ -509,6 +680,61 @@ func tessMeshTessellateInterior(mesh *mesh) {
 	}
 }
 
+// edgeIsInternal reports whether e separates two interior triangles and
+// may be flipped.
+func edgeIsInternal(e *halfEdge) bool {
+	return e.rFace() != nil && e.rFace().inside && !e.constrained
+}
+
+// tessMeshRefineDelaunay flips the edges of a valid triangulation of the
+// interior until every internal edge is locally Delaunay.  Constraint
+// edges and the boundary are never flipped, so the result is the
+// constrained Delaunay triangulation.
+func tessMeshRefineDelaunay(mesh *mesh) {
+	var stack []*halfEdge
+	maxFaces := 0
+	for f := mesh.fHead.next; f != &mesh.fHead; f = f.next {
+		if !f.inside {
+			continue
+		}
+		e := f.anEdge
+		for {
+			e.mark = edgeIsInternal(e) // Mark internal edges
+			if e.mark && !e.Sym.mark {
+				stack = append(stack, e)
+			}
+			e = e.Lnext
+			if e == f.anEdge {
+				break
+			}
+		}
+		maxFaces++
+	}
+
+	// The algorithm should converge on O(n^2), since the predicate is not
+	// robust, we guard against an infinite loop.
+	maxIter := maxFaces * maxFaces
+	// Pop the stack until we find a reversed edge, flip it, and push any of
+	// the four opposite edges which are internal and not already in the stack.
+	for iter := 0; len(stack) > 0 && iter < maxIter; iter++ {
+		e := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		e.mark = false
+		e.Sym.mark = false
+		if edgeIsLocallyDelaunay(e) || !edgeFlipIsConvex(e) {
+			continue
+		}
+		tessMeshFlipEdge(mesh, e)
+		for _, o := range []*halfEdge{e.Lnext, e.lPrev(), e.Sym.Lnext, e.Sym.lPrev()} {
+			if !o.mark && edgeIsInternal(o) {
+				o.mark = true
+				o.Sym.mark = true
+				stack = append(stack, o)
+			}
+		}
+	}
+}
+
 // tessMeshSetWindingNumber resets the
 // winding numbers on all edges so that regions marked "inside" the
 // polygon have a winding number of "value", and regions outside
@@ -836,6 +1062,15 @@ func tessTesselate(tess *tesselator, windingRule WindingRule, elementType elemen
 		tessMeshSetWindingNumber(mesh, 1, true)
 	} else {
 		tessMeshTessellateInterior(mesh)
+		if len(tess.steinerPoints) > 0 {
+			insertSteinerPoints(tess, mesh)
+		}
+		if tess.delaunay {
+			tessMeshRefineDelaunay(mesh)
+		}
+		if tess.refiner != nil {
+			tessMeshRefineEdges(mesh, tess.refiner, tess.maxEdgeLength)
+		}
 	}
 
 	tessMeshCheckMesh(mesh)
@@ -0,0 +1,254 @@
+package tesselator
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// TileGrid 轴对齐的瓦片网格：第col列、第row行的瓦片覆盖
+// [MinX+col×TileWidth, MinX+(col+1)×TileWidth]×[MinY+row×TileHeight, MinY+(row+1)×TileHeight]
+type TileGrid struct {
+	MinX, MinY            float32
+	TileWidth, TileHeight float32
+	Cols, Rows            int
+}
+
+// QuadtreeGrid 返回四叉树第level层的瓦片网格，即把[minX, maxX]×[minY, maxY]均分为2^level×2^level个瓦片
+func QuadtreeGrid(minX, minY, maxX, maxY float32, level int) TileGrid {
+	n := 1 << uint(level)
+	return TileGrid{
+		MinX:       minX,
+		MinY:       minY,
+		TileWidth:  float32(float64(maxX-minX) / float64(n)),
+		TileHeight: float32(float64(maxY-minY) / float64(n)),
+		Cols:       n,
+		Rows:       n,
+	}
+}
+
+func (g TileGrid) x(col int) float32 {
+	return float32(float64(g.MinX) + float64(col)*float64(g.TileWidth))
+}
+
+func (g TileGrid) y(row int) float32 {
+	return float32(float64(g.MinY) + float64(row)*float64(g.TileHeight))
+}
+
+// TileBounds 返回瓦片的范围，相邻瓦片的共享边坐标完全相同
+func (g TileGrid) TileBounds(col, row int) (minX, minY, maxX, maxY float32) {
+	return g.x(col), g.y(row), g.x(col + 1), g.y(row + 1)
+}
+
+// cols 返回与[lo, hi]重叠的列范围，两端各放宽一列以免舍入遗漏
+func (g TileGrid) cols(lo, hi float32) (int, int) {
+	c0 := int(math.Floor(float64(lo-g.MinX)/float64(g.TileWidth))) - 1
+	c1 := int(math.Floor(float64(hi-g.MinX)/float64(g.TileWidth))) + 1
+	return clampInt(c0, 0, g.Cols-1), clampInt(c1, 0, g.Cols-1)
+}
+
+// rows 返回与[lo, hi]重叠的行范围，两端各放宽一行以免舍入遗漏
+func (g TileGrid) rows(lo, hi float32) (int, int) {
+	r0 := int(math.Floor(float64(lo-g.MinY)/float64(g.TileHeight))) - 1
+	r1 := int(math.Floor(float64(hi-g.MinY)/float64(g.TileHeight))) + 1
+	return clampInt(r0, 0, g.Rows-1), clampInt(r1, 0, g.Rows-1)
+}
+
+// Tile 一个瓦片内的剖分结果，三角形在XY平面上为逆时针
+type Tile struct {
+	Col, Row int
+	Indices  []int
+	Vertices []Vertex
+	// Outline 区域在瓦片内的边界边，方向使区域位于左侧
+	Outline [][2]int
+	// ClipEdges 与Outline一一对应，标记由裁剪产生、位于瓦片边界上的边，绘制轮廓线时应跳过
+	ClipEdges []bool
+}
+
+// TesselateTiled 把环绕规则确定的区域裁剪到网格的每个瓦片内分别进行三角剖分，只返回非空的瓦片，
+// 按行、列递增排列。轮廓的边先在所有网格线处统一打断，再用Sutherland-Hodgman算法裁剪到瓦片矩形，
+// 因此相邻瓦片在共享边上的顶点XY坐标完全相同，裁剪不改变瓦片内的环绕数。
+// 所有瓦片使用与Tesselate相同的整体朝向，自相交和重叠的轮廓也得到一致的结果。
+// 网格线上的新顶点的Z沿原来的边插值，瓦片角点的Z沿裁剪产生的边插值
+func TesselateTiled(contours []Contour, windingRule WindingRule, grid TileGrid) ([]Tile, error) {
+	if grid.Cols <= 0 || grid.Rows <= 0 || !(grid.TileWidth > 0) || !(grid.TileHeight > 0) {
+		return nil, fmt.Errorf("tesselator: invalid tile grid %+v", grid)
+	}
+	validContours := make([]Contour, 0, len(contours))
+	for _, c := range contours {
+		if len(c) >= 3 {
+			validContours = append(validContours, c)
+		}
+	}
+	if len(validContours) == 0 {
+		return []Tile{}, nil
+	}
+	validContours, windingRule = resolveNesting(validContours, windingRule)
+	validContours = orientContours(validContours)
+
+	split := splitAtGridLines(validContours, grid)
+	seams := newGridSegments(split, grid)
+
+	// 按包围盒把轮廓分配到可能相交的瓦片
+	candidates := map[int][]int{}
+	for i, c := range split {
+		minX, minY, maxX, maxY := computeBounds([]Contour{c})
+		c0, c1 := grid.cols(minX, maxX)
+		r0, r1 := grid.rows(minY, maxY)
+		for row := r0; row <= r1; row++ {
+			for col := c0; col <= c1; col++ {
+				key := row*grid.Cols + col
+				candidates[key] = append(candidates[key], i)
+			}
+		}
+	}
+	keys := make([]int, 0, len(candidates))
+	for key := range candidates {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+
+	tiles := []Tile{}
+	for _, key := range keys {
+		col, row := key%grid.Cols, key/grid.Cols
+		minX, minY, maxX, maxY := grid.TileBounds(col, row)
+		planes := rectHalfPlanes(minX, minY, maxX, maxY)
+		var parts []Contour
+		for _, i := range candidates[key] {
+			parts = append(parts, clipContours([]Contour{split[i]}, planes)...)
+		}
+		if len(parts) == 0 {
+			continue
+		}
+
+		t := &tesselator{}
+		addContours(t, parts)
+		if t.mesh == nil {
+			continue
+		}
+		t.normal = [3]float{0, 0, 1}
+		t.windingRule = windingRule
+		tessProjectPolygon(t)
+		tessComputeInterior(t)
+		indices, vertices, outline := meshTrianglesWithBoundary(t.mesh, false)
+		if len(indices) == 0 {
+			continue
+		}
+		clipEdges := make([]bool, len(outline))
+		for i, e := range outline {
+			clipEdges[i] = seams.isClipEdge(vertices[e[0]], vertices[e[1]], minX, minY, maxX, maxY)
+		}
+		tiles = append(tiles, Tile{Col: col, Row: row, Indices: indices, Vertices: vertices, Outline: outline, ClipEdges: clipEdges})
+	}
+	return tiles, nil
+}
+
+// orientContours 与Tesselate自动确定法线时一样，有向面积之和为负时把所有轮廓反向，使其非负。
+// 裁剪前确定朝向，之后以固定的法线剖分，环绕规则Positive和Negative的含义不因裁剪而改变
+func orientContours(contours []Contour) []Contour {
+	area := 0.0
+	for _, c := range contours {
+		area += contourSignedArea(c)
+	}
+	if area >= 0 {
+		return contours
+	}
+	reversed := make([]Contour, len(contours))
+	for i, c := range contours {
+		reversed[i] = append(Contour(nil), c...)
+		reverseContour(reversed[i])
+	}
+	return reversed
+}
+
+// splitAtGridLines 在边与网格线的交点处插入顶点，使每条边都不再穿过网格线。
+// 交点对整个网格只计算一次，相邻瓦片因而共享同样的顶点
+func splitAtGridLines(contours []Contour, grid TileGrid) []Contour {
+	type crossing struct {
+		t float64
+		v Vertex
+	}
+	result := make([]Contour, len(contours))
+	for i, c := range contours {
+		var out Contour
+		for k, a := range c {
+			b := c[(k+1)%len(c)]
+			out = append(out, a)
+
+			var crossings []crossing
+			lo, hi := min32(a.X, b.X), max32(a.X, b.X)
+			c0, c1 := grid.cols(lo, hi)
+			for col := c0; col <= c1+1; col++ {
+				if x := grid.x(col); x > lo && x < hi {
+					h := halfPlane{a: 1, c: float64(x)}
+					crossings = append(crossings, crossing{(float64(x) - float64(a.X)) / (float64(b.X) - float64(a.X)), h.intersect(a, b)})
+				}
+			}
+			lo, hi = min32(a.Y, b.Y), max32(a.Y, b.Y)
+			r0, r1 := grid.rows(lo, hi)
+			for row := r0; row <= r1+1; row++ {
+				if y := grid.y(row); y > lo && y < hi {
+					h := halfPlane{b: 1, c: float64(y)}
+					crossings = append(crossings, crossing{(float64(y) - float64(a.Y)) / (float64(b.Y) - float64(a.Y)), h.intersect(a, b)})
+				}
+			}
+			sort.Slice(crossings, func(i, j int) bool { return crossings[i].t < crossings[j].t })
+			for _, cr := range crossings {
+				if cr.v != out[len(out)-1] && cr.v != b {
+					out = append(out, cr.v)
+				}
+			}
+		}
+		result[i] = out
+	}
+	return result
+}
+
+// gridSegments 记录轮廓中位于网格线上的边，用于区分沿瓦片边界的原有边和裁剪产生的边
+type gridSegments struct {
+	vertical   map[float32][][2]float32 // 竖直网格线上的边覆盖的Y区间
+	horizontal map[float32][][2]float32 // 水平网格线上的边覆盖的X区间
+}
+
+func newGridSegments(contours []Contour, grid TileGrid) *gridSegments {
+	xs, ys := map[float32]bool{}, map[float32]bool{}
+	for col := 0; col <= grid.Cols; col++ {
+		xs[grid.x(col)] = true
+	}
+	for row := 0; row <= grid.Rows; row++ {
+		ys[grid.y(row)] = true
+	}
+	s := &gridSegments{vertical: map[float32][][2]float32{}, horizontal: map[float32][][2]float32{}}
+	for _, c := range contours {
+		for k, a := range c {
+			b := c[(k+1)%len(c)]
+			if a.X == b.X && xs[a.X] {
+				s.vertical[a.X] = append(s.vertical[a.X], [2]float32{min32(a.Y, b.Y), max32(a.Y, b.Y)})
+			}
+			if a.Y == b.Y && ys[a.Y] {
+				s.horizontal[a.Y] = append(s.horizontal[a.Y], [2]float32{min32(a.X, b.X), max32(a.X, b.X)})
+			}
+		}
+	}
+	return s
+}
+
+// isClipEdge 判断边ab是否位于瓦片边界上且不在任何原有的边上
+func (s *gridSegments) isClipEdge(a, b Vertex, minX, minY, maxX, maxY float32) bool {
+	covered := func(intervals [][2]float32, p, q float32) bool {
+		m := (float64(p) + float64(q)) / 2
+		for _, in := range intervals {
+			if float64(in[0]) <= m && m <= float64(in[1]) {
+				return true
+			}
+		}
+		return false
+	}
+	if a.X == b.X && (a.X == minX || a.X == maxX) {
+		return !covered(s.vertical[a.X], a.Y, b.Y)
+	}
+	if a.Y == b.Y && (a.Y == minY || a.Y == maxY) {
+		return !covered(s.horizontal[a.Y], a.X, b.X)
+	}
+	return false
+}
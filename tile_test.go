@@ -0,0 +1,124 @@
+package tesselator
+
+import (
+	"math"
+	"sort"
+	"testing"
+)
+
+// TestTesselateTiled 各瓦片的面积之和等于整体面积，共享边上的顶点一致，裁剪产生的边被标记
+func TestTesselateTiled(t *testing.T) {
+	star := GenerateStar(7, 0.3, -0.2, 10, 4)
+	contours := []Contour{star, reversedContour(squareContour(-1.5, -1.5, 3))}
+	grid := TileGrid{MinX: -12, MinY: -12, TileWidth: 4, TileHeight: 4, Cols: 6, Rows: 6}
+	for _, input := range [][]Contour{contours, {reversedContour(star), squareContour(-1.5, -1.5, 3)}} {
+		tiles, err := TesselateTiled(input, WindingRuleOdd, grid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		indices, vertices, _ := Tesselate(input, WindingRuleOdd)
+		expected := meshArea(indices, vertices)
+
+		total, outline := 0.0, 0.0
+		seams := map[[3]int][][2]float32{}
+		for _, tile := range tiles {
+			minX, minY, maxX, maxY := grid.TileBounds(tile.Col, tile.Row)
+			checkPointTriangulation(t, tile.Indices, tile.Vertices, meshArea(tile.Indices, tile.Vertices))
+			total += meshArea(tile.Indices, tile.Vertices)
+			for _, v := range tile.Vertices {
+				if v.X < minX || v.X > maxX || v.Y < minY || v.Y > maxY {
+					t.Fatalf("vertex %v is outside tile %d,%d", v, tile.Col, tile.Row)
+				}
+				// 按所在的瓦片边收集顶点，键为方向、网格线序号和另一方向上的瓦片序号
+				if v.X == minX {
+					seams[[3]int{0, tile.Col, tile.Row}] = append(seams[[3]int{0, tile.Col, tile.Row}], [2]float32{v.X, v.Y})
+				}
+				if v.X == maxX {
+					seams[[3]int{1, tile.Col + 1, tile.Row}] = append(seams[[3]int{1, tile.Col + 1, tile.Row}], [2]float32{v.X, v.Y})
+				}
+				if v.Y == minY {
+					seams[[3]int{2, tile.Row, tile.Col}] = append(seams[[3]int{2, tile.Row, tile.Col}], [2]float32{v.X, v.Y})
+				}
+				if v.Y == maxY {
+					seams[[3]int{3, tile.Row + 1, tile.Col}] = append(seams[[3]int{3, tile.Row + 1, tile.Col}], [2]float32{v.X, v.Y})
+				}
+			}
+			if len(tile.ClipEdges) != len(tile.Outline) {
+				t.Fatalf("expected %d clip flags, got %d", len(tile.Outline), len(tile.ClipEdges))
+			}
+			for i, e := range tile.Outline {
+				a, b := tile.Vertices[e[0]], tile.Vertices[e[1]]
+				if !tile.ClipEdges[i] {
+					outline += math.Hypot(float64(b.X-a.X), float64(b.Y-a.Y))
+				} else if a.X != b.X && a.Y != b.Y {
+					t.Errorf("clip edge %v %v is not on the tile border", a, b)
+				}
+			}
+		}
+		if math.Abs(total-expected) > 1e-3 {
+			t.Errorf("tiles cover %g, expected %g", total, expected)
+		}
+		// 不计裁剪边时，轮廓线的总长等于星形和方形洞的周长
+		perimeter := 12.0
+		for i := range star {
+			a, b := star[i], star[(i+1)%len(star)]
+			perimeter += math.Hypot(float64(b.X-a.X), float64(b.Y-a.Y))
+		}
+		if math.Abs(outline-perimeter) > 1e-3 {
+			t.Errorf("expected outline length %g, got %g", perimeter, outline)
+		}
+		// 共享边两侧的瓦片在边上有同样的顶点
+		for key, left := range seams {
+			if key[0]%2 == 1 {
+				continue
+			}
+			right := seams[[3]int{key[0] + 1, key[1], key[2]}]
+			if !sameVertexSet(left, right) {
+				t.Errorf("tiles disagree on seam %v: %v and %v", key, left, right)
+			}
+		}
+	}
+
+	// 沿瓦片边界的原有边不是裁剪边
+	tiles, err := TesselateTiled([]Contour{squareContour(0, 0, 2)}, WindingRuleOdd, QuadtreeGrid(0, 0, 4, 4, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tiles) != 1 || tiles[0].Col != 0 || tiles[0].Row != 0 {
+		t.Fatalf("expected only tile 0,0, got %v", tiles)
+	}
+	for i, clip := range tiles[0].ClipEdges {
+		if clip {
+			t.Errorf("edge %v should not be a clip edge", tiles[0].Outline[i])
+		}
+	}
+
+	if _, err := TesselateTiled(contours, WindingRuleOdd, TileGrid{TileWidth: 1}); err == nil {
+		t.Error("expected an error for an empty grid")
+	}
+}
+
+// sameVertexSet 判断两组顶点去重后是否相同
+func sameVertexSet(a, b [][2]float32) bool {
+	unique := func(s [][2]float32) [][2]float32 {
+		s = append([][2]float32(nil), s...)
+		sort.Slice(s, func(i, j int) bool { return s[i][0] < s[j][0] || (s[i][0] == s[j][0] && s[i][1] < s[j][1]) })
+		var out [][2]float32
+		for i, v := range s {
+			if i == 0 || v != s[i-1] {
+				out = append(out, v)
+			}
+		}
+		return out
+	}
+	a, b = unique(a), unique(b)
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,309 @@
+package tesselator
+
+import (
+	"math"
+	"sort"
+)
+
+// Triangulation 建立在三角剖分输出上的均匀网格索引，用于点定位、最近三角形和矩形查询。
+// 每个三角形按包围盒登记到它覆盖的网格单元中，单元数量与三角形数量相当。
+// 所有查询只使用XY坐标，三角形序号t对应indices[3t:3t+3]
+type Triangulation struct {
+	indices  []int
+	vertices []Vertex
+
+	minX, minY float64
+	maxX, maxY float64
+	cellSize   float64
+	cols, rows int
+	cellStart  []int32 // 第c个单元的三角形为cellTris[cellStart[c]:cellStart[c+1]]
+	cellTris   []int32
+}
+
+// NewTriangulation 为三角形网格建立查询索引。indices和vertices被直接引用，建立索引后不应再修改
+func NewTriangulation(indices []int, vertices []Vertex) *Triangulation {
+	tr := &Triangulation{indices: indices, vertices: vertices}
+	n := len(indices) / 3
+	if n == 0 {
+		return tr
+	}
+
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, i := range indices[:3*n] {
+		v := vertices[i]
+		minX, maxX = math.Min(minX, float64(v.X)), math.Max(maxX, float64(v.X))
+		minY, maxY = math.Min(minY, float64(v.Y)), math.Max(maxY, float64(v.Y))
+	}
+	w, h := maxX-minX, maxY-minY
+	// 单元为正方形，数量约等于三角形数量
+	size := math.Sqrt(w * h / float64(n))
+	if size == 0 || math.IsNaN(size) {
+		size = math.Max(w, h) / float64(n)
+	}
+	if size == 0 {
+		size = 1
+	}
+	tr.minX, tr.minY, tr.maxX, tr.maxY, tr.cellSize = minX, minY, maxX, maxY, size
+	tr.cols = int(w/size) + 1
+	tr.rows = int(h/size) + 1
+
+	// 两遍扫描：先统计每个单元的三角形数量，再按前缀和填入
+	tr.cellStart = make([]int32, tr.cols*tr.rows+1)
+	for t := 0; t < n; t++ {
+		c0, r0, c1, r1 := tr.triangleCells(t)
+		for r := r0; r <= r1; r++ {
+			for c := c0; c <= c1; c++ {
+				tr.cellStart[r*tr.cols+c+1]++
+			}
+		}
+	}
+	for c := 1; c < len(tr.cellStart); c++ {
+		tr.cellStart[c] += tr.cellStart[c-1]
+	}
+	tr.cellTris = make([]int32, tr.cellStart[len(tr.cellStart)-1])
+	fill := append([]int32(nil), tr.cellStart[:len(tr.cellStart)-1]...)
+	for t := 0; t < n; t++ {
+		c0, r0, c1, r1 := tr.triangleCells(t)
+		for r := r0; r <= r1; r++ {
+			for c := c0; c <= c1; c++ {
+				cell := r*tr.cols + c
+				tr.cellTris[fill[cell]] = int32(t)
+				fill[cell]++
+			}
+		}
+	}
+	return tr
+}
+
+// TriangleCount 返回三角形数量
+func (tr *Triangulation) TriangleCount() int {
+	return len(tr.indices) / 3
+}
+
+// Triangle 返回第t个三角形的三个顶点
+func (tr *Triangulation) Triangle(t int) (Vertex, Vertex, Vertex) {
+	return tr.vertices[tr.indices[3*t]], tr.vertices[tr.indices[3*t+1]], tr.vertices[tr.indices[3*t+2]]
+}
+
+// cell 返回坐标所在的列和行，超出网格时取最近的单元
+func (tr *Triangulation) cell(x, y float64) (int, int) {
+	c := int(math.Floor((x - tr.minX) / tr.cellSize))
+	r := int(math.Floor((y - tr.minY) / tr.cellSize))
+	return clampInt(c, 0, tr.cols-1), clampInt(r, 0, tr.rows-1)
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// triangleCells 返回三角形包围盒覆盖的单元范围
+func (tr *Triangulation) triangleCells(t int) (int, int, int, int) {
+	a, b, c := tr.Triangle(t)
+	minX := math.Min(float64(a.X), math.Min(float64(b.X), float64(c.X)))
+	minY := math.Min(float64(a.Y), math.Min(float64(b.Y), float64(c.Y)))
+	maxX := math.Max(float64(a.X), math.Max(float64(b.X), float64(c.X)))
+	maxY := math.Max(float64(a.Y), math.Max(float64(b.Y), float64(c.Y)))
+	c0, r0 := tr.cell(minX, minY)
+	c1, r1 := tr.cell(maxX, maxY)
+	return c0, r0, c1, r1
+}
+
+func (tr *Triangulation) cellTriangles(c, r int) []int32 {
+	cell := r*tr.cols + c
+	return tr.cellTris[tr.cellStart[cell]:tr.cellStart[cell+1]]
+}
+
+// barycentric 返回p在三角形t中的重心坐标，三角形退化时ok为false
+func (tr *Triangulation) barycentric(t int, x, y float64) ([3]float64, bool) {
+	a, b, c := tr.Triangle(t)
+	ax, ay := float64(a.X), float64(a.Y)
+	bx, by := float64(b.X)-ax, float64(b.Y)-ay
+	cx, cy := float64(c.X)-ax, float64(c.Y)-ay
+	px, py := x-ax, y-ay
+	det := bx*cy - by*cx
+	if det == 0 {
+		return [3]float64{}, false
+	}
+	u := (px*cy - py*cx) / det
+	v := (bx*py - by*px) / det
+	return [3]float64{1 - u - v, u, v}, true
+}
+
+// Locate 返回包含点p的三角形序号和p在其中的重心坐标，重心坐标依次对应三角形的三个顶点。
+// 点在多个三角形的公共边或顶点上时返回其中任意一个；不在任何三角形内时返回-1
+func (tr *Triangulation) Locate(p Vertex) (int, [3]float32) {
+	if len(tr.cellTris) == 0 {
+		return -1, [3]float32{}
+	}
+	x, y := float64(p.X), float64(p.Y)
+	c, r := tr.cell(x, y)
+	for _, t := range tr.cellTriangles(c, r) {
+		w, ok := tr.barycentric(int(t), x, y)
+		if ok && w[0] >= 0 && w[1] >= 0 && w[2] >= 0 {
+			return int(t), [3]float32{float32(w[0]), float32(w[1]), float32(w[2])}
+		}
+	}
+	return -1, [3]float32{}
+}
+
+// Interpolate 用p所在三角形的重心坐标对每个顶点的属性插值，attributes按顶点编号存放，
+// 每个顶点size个分量。p不在任何三角形内时返回nil
+func (tr *Triangulation) Interpolate(p Vertex, attributes []float32, size int) []float32 {
+	t, w := tr.Locate(p)
+	if t < 0 {
+		return nil
+	}
+	result := make([]float32, size)
+	for k := 0; k < 3; k++ {
+		base := tr.indices[3*t+k] * size
+		for i := range result {
+			result[i] += w[k] * attributes[base+i]
+		}
+	}
+	return result
+}
+
+// Nearest 返回在XY平面上距离点p最近的三角形序号及距离，p在三角形内时距离为0。
+// 从p所在的单元开始逐圈向外搜索，直到未搜索的单元不可能更近。没有三角形时返回-1
+func (tr *Triangulation) Nearest(p Vertex) (int, float32) {
+	if len(tr.cellTris) == 0 {
+		return -1, 0
+	}
+	x, y := float64(p.X), float64(p.Y)
+	c0, r0 := tr.cell(x, y)
+	best, bestDist := -1, math.Inf(1)
+	for ring := 0; ; ring++ {
+		for r := r0 - ring; r <= r0+ring; r++ {
+			if r < 0 || r >= tr.rows {
+				continue
+			}
+			step := 1
+			if r != r0-ring && r != r0+ring {
+				// 中间行只有两端的单元属于这一圈
+				step = 2 * ring
+			}
+			for c := c0 - ring; c <= c0+ring; c += step {
+				if c >= 0 && c < tr.cols {
+					for _, t := range tr.cellTriangles(c, r) {
+						if d := tr.distance(int(t), x, y); d < bestDist {
+							best, bestDist = int(t), d
+						}
+					}
+				}
+			}
+		}
+		// 网格中尚未搜索的单元到p的最小距离，全部搜索完时为无穷大。
+		// p在网格之外时各条带还要计入p到三角形包围盒的距离
+		outX := math.Max(0, math.Max(tr.minX-x, x-tr.maxX))
+		outY := math.Max(0, math.Max(tr.minY-y, y-tr.maxY))
+		margin := math.Inf(1)
+		if c0-ring > 0 {
+			margin = math.Min(margin, math.Hypot(x-(tr.minX+float64(c0-ring)*tr.cellSize), outY))
+		}
+		if c0+ring < tr.cols-1 {
+			margin = math.Min(margin, math.Hypot(tr.minX+float64(c0+ring+1)*tr.cellSize-x, outY))
+		}
+		if r0-ring > 0 {
+			margin = math.Min(margin, math.Hypot(y-(tr.minY+float64(r0-ring)*tr.cellSize), outX))
+		}
+		if r0+ring < tr.rows-1 {
+			margin = math.Min(margin, math.Hypot(tr.minY+float64(r0+ring+1)*tr.cellSize-y, outX))
+		}
+		if bestDist <= margin {
+			break
+		}
+	}
+	return best, float32(bestDist)
+}
+
+// distance 返回点到三角形t的距离，点在三角形内时为0
+func (tr *Triangulation) distance(t int, x, y float64) float64 {
+	if w, ok := tr.barycentric(t, x, y); ok && w[0] >= 0 && w[1] >= 0 && w[2] >= 0 {
+		return 0
+	}
+	a, b, c := tr.Triangle(t)
+	d := pointSegmentDistance(x, y, a, b)
+	d = math.Min(d, pointSegmentDistance(x, y, b, c))
+	return math.Min(d, pointSegmentDistance(x, y, c, a))
+}
+
+func pointSegmentDistance(x, y float64, a, b Vertex) float64 {
+	ax, ay := float64(a.X), float64(a.Y)
+	dx, dy := float64(b.X)-ax, float64(b.Y)-ay
+	s := 0.0
+	if l := dx*dx + dy*dy; l > 0 {
+		s = math.Max(0, math.Min(1, ((x-ax)*dx+(y-ay)*dy)/l))
+	}
+	return math.Hypot(x-ax-s*dx, y-ay-s*dy)
+}
+
+// Query 返回与矩形[minX, maxX]×[minY, maxY]相交（包括只接触边界）的三角形序号，按序号递增排列
+func (tr *Triangulation) Query(minX, minY, maxX, maxY float32) []int {
+	var result []int
+	if len(tr.cellTris) == 0 || minX > maxX || minY > maxY {
+		return result
+	}
+	c0, r0 := tr.cell(float64(minX), float64(minY))
+	c1, r1 := tr.cell(float64(maxX), float64(maxY))
+	for r := r0; r <= r1; r++ {
+		for c := c0; c <= c1; c++ {
+			for _, t := range tr.cellTriangles(c, r) {
+				// 跨越多个单元的三角形只在查询范围内它覆盖的第一个单元中处理一次
+				tc, tr0, _, _ := tr.triangleCells(int(t))
+				if c != maxInt(c0, tc) || r != maxInt(r0, tr0) {
+					continue
+				}
+				if tr.intersectsRect(int(t), float64(minX), float64(minY), float64(maxX), float64(maxY)) {
+					result = append(result, int(t))
+				}
+			}
+		}
+	}
+	sort.Ints(result)
+	return result
+}
+
+// intersectsRect 用分离轴判断三角形与轴对齐矩形是否相交：坐标轴方向和三条边的法线方向
+func (tr *Triangulation) intersectsRect(t int, minX, minY, maxX, maxY float64) bool {
+	a, b, c := tr.Triangle(t)
+	xs := [3]float64{float64(a.X), float64(b.X), float64(c.X)}
+	ys := [3]float64{float64(a.Y), float64(b.Y), float64(c.Y)}
+	if math.Max(xs[0], math.Max(xs[1], xs[2])) < minX || math.Min(xs[0], math.Min(xs[1], xs[2])) > maxX ||
+		math.Max(ys[0], math.Max(ys[1], ys[2])) < minY || math.Min(ys[0], math.Min(ys[1], ys[2])) > maxY {
+		return false
+	}
+	corners := [4][2]float64{{minX, minY}, {maxX, minY}, {maxX, maxY}, {minX, maxY}}
+	for i := 0; i < 3; i++ {
+		j, k := (i+1)%3, (i+2)%3
+		nx, ny := ys[j]-ys[i], xs[i]-xs[j]
+		if nx == 0 && ny == 0 {
+			continue
+		}
+		// 第三个顶点所在的一侧为三角形内部，矩形完全在另一侧时分离
+		inner := nx*(xs[k]-xs[i]) + ny*(ys[k]-ys[i])
+		separated := true
+		for _, q := range corners {
+			if d := nx*(q[0]-xs[i]) + ny*(q[1]-ys[i]); d == 0 || (d > 0) == (inner > 0) {
+				separated = false
+				break
+			}
+		}
+		if separated && inner != 0 {
+			return false
+		}
+	}
+	return true
+}
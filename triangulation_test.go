@@ -0,0 +1,203 @@
+package tesselator
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// triangulationTestMesh 带洞星形的三角剖分
+func triangulationTestMesh(t testing.TB) ([]int, []Vertex) {
+	contours := []Contour{GenerateStar(9, 0, 0, 50, 25), GenerateRegularPolygon(12, 0, 0, 10)}
+	reverseContour(contours[1])
+	indices, vertices, err := Tesselate(contours, WindingRuleNonzero)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return indices, vertices
+}
+
+// gridTestMesh 生成n×n个扰动过的方格组成的网格，共2n²个三角形
+func gridTestMesh(n int) ([]int, []Vertex) {
+	r := rand.New(rand.NewSource(1))
+	vertices := make([]Vertex, 0, (n+1)*(n+1))
+	for y := 0; y <= n; y++ {
+		for x := 0; x <= n; x++ {
+			v := Vertex{X: float32(x), Y: float32(y)}
+			if x > 0 && x < n && y > 0 && y < n {
+				v.X += float32(r.Float64()*0.6 - 0.3)
+				v.Y += float32(r.Float64()*0.6 - 0.3)
+			}
+			vertices = append(vertices, v)
+		}
+	}
+	indices := make([]int, 0, 6*n*n)
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			i := y*(n+1) + x
+			indices = append(indices, i, i+1, i+n+2, i, i+n+2, i+n+1)
+		}
+	}
+	return indices, vertices
+}
+
+// TestTriangulationLocate 与逐个三角形检查的结果比较点定位和重心坐标
+func TestTriangulationLocate(t *testing.T) {
+	indices, vertices := triangulationTestMesh(t)
+	tr := NewTriangulation(indices, vertices)
+	if tr.TriangleCount() != len(indices)/3 {
+		t.Fatalf("expected %d triangles, got %d", len(indices)/3, tr.TriangleCount())
+	}
+
+	r := rand.New(rand.NewSource(3))
+	for i := 0; i < 2000; i++ {
+		p := Vertex{X: float32(r.Float64()*120 - 60), Y: float32(r.Float64()*120 - 60)}
+		tri, w := tr.Locate(p)
+		covered := meshCoverage(indices, vertices, float64(p.X), float64(p.Y)) > 0
+		if tri < 0 {
+			if covered {
+				t.Errorf("%v lies inside the mesh but was not located", p)
+			}
+			continue
+		}
+		a, b, c := tr.Triangle(tri)
+		x := w[0]*a.X + w[1]*b.X + w[2]*c.X
+		y := w[0]*a.Y + w[1]*b.Y + w[2]*c.Y
+		if math.Abs(float64(x-p.X)) > 1e-3 || math.Abs(float64(y-p.Y)) > 1e-3 || math.Abs(float64(w[0]+w[1]+w[2]-1)) > 1e-5 {
+			t.Errorf("barycentric coordinates %v of triangle %d do not reproduce %v", w, tri, p)
+		}
+		if w[0] < 0 || w[1] < 0 || w[2] < 0 {
+			t.Errorf("%v located in triangle %d with negative weights %v", p, tri, w)
+		}
+	}
+
+	// 属性插值：按顶点坐标线性变化的属性应被精确还原
+	attributes := make([]float32, 2*len(vertices))
+	for i, v := range vertices {
+		attributes[2*i] = 2*v.X + 1
+		attributes[2*i+1] = v.Y - v.X
+	}
+	p := Vertex{X: 30, Y: 2}
+	if got := tr.Interpolate(p, attributes, 2); got == nil || math.Abs(float64(got[0]-61)) > 1e-3 || math.Abs(float64(got[1]+28)) > 1e-3 {
+		t.Errorf("interpolated attributes at %v: %v, expected [61 -28]", p, got)
+	}
+	if tr.Interpolate(Vertex{}, attributes, 2) != nil {
+		t.Errorf("the hole centre should not interpolate")
+	}
+
+	empty := NewTriangulation(nil, nil)
+	if tri, _ := empty.Locate(Vertex{}); tri != -1 {
+		t.Errorf("empty triangulation should not locate points")
+	}
+	if tri, _ := empty.Nearest(Vertex{}); tri != -1 {
+		t.Errorf("empty triangulation should not have a nearest triangle")
+	}
+}
+
+// TestTriangulationNearest 与逐个三角形计算的最小距离比较
+func TestTriangulationNearest(t *testing.T) {
+	indices, vertices := triangulationTestMesh(t)
+	tr := NewTriangulation(indices, vertices)
+	r := rand.New(rand.NewSource(5))
+	for i := 0; i < 500; i++ {
+		// 包括远离网格的点
+		p := Vertex{X: float32(r.Float64()*400 - 200), Y: float32(r.Float64()*400 - 200)}
+		tri, d := tr.Nearest(p)
+		best := math.Inf(1)
+		for k := 0; k < tr.TriangleCount(); k++ {
+			best = math.Min(best, tr.distance(k, float64(p.X), float64(p.Y)))
+		}
+		if tri < 0 || math.Abs(float64(d)-best) > 1e-4 {
+			t.Errorf("nearest triangle to %v: %d at %v, expected distance %v", p, tri, d, best)
+		}
+		if located, _ := tr.Locate(p); located >= 0 && d != 0 {
+			t.Errorf("%v lies in triangle %d but nearest distance is %v", p, located, d)
+		}
+	}
+}
+
+// TestTriangulationQuery 测试矩形查询
+func TestTriangulationQuery(t *testing.T) {
+	indices, vertices := triangulationTestMesh(t)
+	tr := NewTriangulation(indices, vertices)
+	r := rand.New(rand.NewSource(9))
+	for i := 0; i < 200; i++ {
+		x, y := float32(r.Float64()*120-60), float32(r.Float64()*120-60)
+		w, h := float32(r.Float64()*20), float32(r.Float64()*20)
+		result := tr.Query(x, y, x+w, y+h)
+		found := map[int]bool{}
+		for k, tri := range result {
+			if k > 0 && result[k-1] >= tri {
+				t.Fatalf("query result is not sorted: %v", result)
+			}
+			found[tri] = true
+		}
+		for k := 0; k < tr.TriangleCount(); k++ {
+			a, b, c := tr.Triangle(k)
+			vertexInside := false
+			for _, v := range []Vertex{a, b, c} {
+				if v.X >= x && v.X <= x+w && v.Y >= y && v.Y <= y+h {
+					vertexInside = true
+				}
+			}
+			bboxApart := math.Max(float64(a.X), math.Max(float64(b.X), float64(c.X))) < float64(x) ||
+				math.Min(float64(a.X), math.Min(float64(b.X), float64(c.X))) > float64(x+w) ||
+				math.Max(float64(a.Y), math.Max(float64(b.Y), float64(c.Y))) < float64(y) ||
+				math.Min(float64(a.Y), math.Min(float64(b.Y), float64(c.Y))) > float64(y+h)
+			if vertexInside && !found[k] {
+				t.Errorf("triangle %d has a vertex inside the query rectangle but was not returned", k)
+			}
+			if bboxApart && found[k] {
+				t.Errorf("triangle %d lies outside the query rectangle but was returned", k)
+			}
+		}
+	}
+
+	// 包围盒与矩形重叠但三角形本身不相交
+	tri := NewTriangulation([]int{0, 1, 2}, []Vertex{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 0, Y: 10}})
+	if got := tri.Query(8, 8, 9, 9); len(got) != 0 {
+		t.Errorf("rectangle beyond the hypotenuse should not intersect, got %v", got)
+	}
+	if got := tri.Query(4, 4, 9, 9); len(got) != 1 {
+		t.Errorf("rectangle crossing the hypotenuse should intersect, got %v", got)
+	}
+}
+
+const benchmarkGridSize = 708 // 约10^6个三角形
+
+func BenchmarkNewTriangulation(b *testing.B) {
+	indices, vertices := gridTestMesh(benchmarkGridSize)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewTriangulation(indices, vertices)
+	}
+}
+
+func BenchmarkTriangulationLocate(b *testing.B) {
+	tr := NewTriangulation(gridTestMesh(benchmarkGridSize))
+	r := rand.New(rand.NewSource(1))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tr.Locate(Vertex{X: float32(r.Float64() * benchmarkGridSize), Y: float32(r.Float64() * benchmarkGridSize)})
+	}
+}
+
+func BenchmarkTriangulationNearest(b *testing.B) {
+	tr := NewTriangulation(gridTestMesh(benchmarkGridSize))
+	r := rand.New(rand.NewSource(1))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// 一半的点位于网格之外
+		tr.Nearest(Vertex{X: float32(r.Float64()*2*benchmarkGridSize - benchmarkGridSize/2), Y: float32(r.Float64() * benchmarkGridSize)})
+	}
+}
+
+func BenchmarkTriangulationQuery(b *testing.B) {
+	tr := NewTriangulation(gridTestMesh(benchmarkGridSize))
+	r := rand.New(rand.NewSource(1))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x, y := float32(r.Float64()*benchmarkGridSize), float32(r.Float64()*benchmarkGridSize)
+		tr.Query(x, y, x+10, y+10)
+	}
+}
@@ -0,0 +1,547 @@
+package tesselator
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ValidationProblemType 轮廓有效性问题的类型，参照OGC简单要素规范中多边形的有效性规则
+type ValidationProblemType int
+
+const (
+	// ProblemSelfIntersection 边在交点处交叉，或者边与边部分重叠；可以发生在同一个轮廓内或两个轮廓之间
+	ProblemSelfIntersection ValidationProblemType = iota
+	// ProblemRingSelfTouch 轮廓在一点与自身接触但不交叉
+	ProblemRingSelfTouch
+	// ProblemHoleOutsideShell 顺时针的洞不在任何外轮廓内
+	ProblemHoleOutsideShell
+	// ProblemWrongOrientation 方向与嵌套层数不符：偶数层应为逆时针的外轮廓，奇数层应为顺时针的洞
+	ProblemWrongOrientation
+	// ProblemDuplicatePoint 相邻的两个顶点重合，包括首尾顶点
+	ProblemDuplicatePoint
+	// ProblemSpike 轮廓沿原路折返形成的尖刺，Point为尖刺的顶点
+	ProblemSpike
+	// ProblemTooFewPoints 去除重复点和尖刺后不同的顶点少于3个
+	ProblemTooFewPoints
+)
+
+func (t ValidationProblemType) String() string {
+	switch t {
+	case ProblemSelfIntersection:
+		return "self-intersection"
+	case ProblemRingSelfTouch:
+		return "ring self-touch"
+	case ProblemHoleOutsideShell:
+		return "hole outside shell"
+	case ProblemWrongOrientation:
+		return "wrong orientation"
+	case ProblemDuplicatePoint:
+		return "duplicate point"
+	case ProblemSpike:
+		return "spike"
+	case ProblemTooFewPoints:
+		return "too few points"
+	}
+	return fmt.Sprintf("ValidationProblemType(%d)", int(t))
+}
+
+// ValidationProblem Validate发现的一个问题：Point为问题所在的位置，Contours为涉及的轮廓在输入中的序号（升序）
+type ValidationProblem struct {
+	Type     ValidationProblemType
+	Point    Vertex
+	Contours []int
+}
+
+func (p ValidationProblem) String() string {
+	return fmt.Sprintf("%v at (%g %g) in contours %v", p.Type, p.Point.X, p.Point.Y, p.Contours)
+}
+
+// Validate 检查轮廓是否构成有效的多边形，返回发现的所有问题，输入有效时返回空切片。只使用XY坐标。
+// 交叉和接触取自扫描线合并重合顶点、把顶点接入边和计算交点的位置，再按经过该点的各段轮廓
+// 判断是交叉还是接触；不同轮廓在一点接触是允许的。重复点、尖刺和顶点过少逐个轮廓检查。
+// 外轮廓和洞按被其他轮廓包含的层数区分，偶数层为外轮廓
+func Validate(contours []Contour) ([]ValidationProblem, error) {
+	tolerance := validationTolerance(contours)
+	var problems []ValidationProblem
+	rings := make([]Contour, len(contours))
+	var valid []Contour
+	for i, c := range contours {
+		var ps []ValidationProblem
+		rings[i], ps = cleanRing(c, i, tolerance)
+		problems = append(problems, ps...)
+		if rings[i] != nil {
+			valid = append(valid, rings[i])
+		}
+	}
+
+	t := &tesselator{recordTouches: true}
+	if _, _, err := computeInteriorXYWith(t, valid, WindingRuleOdd); err != nil {
+		return nil, err
+	}
+	segments := newRingSegments(rings, tolerance)
+	crossing := make(map[int]bool)
+	for _, p := range uniqueTouches(t.touches, tolerance) {
+		for _, problem := range segments.classify(p) {
+			if problem.Type == ProblemSelfIntersection {
+				for _, c := range problem.Contours {
+					crossing[c] = true
+				}
+			}
+			problems = append(problems, problem)
+		}
+	}
+	problems = append(problems, nestingProblems(rings, crossing)...)
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		a, b := problems[i], problems[j]
+		if a.Contours[0] != b.Contours[0] {
+			return a.Contours[0] < b.Contours[0]
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Point.X != b.Point.X {
+			return a.Point.X < b.Point.X
+		}
+		return a.Point.Y < b.Point.Y
+	})
+	return problems, nil
+}
+
+// MakeValid 按环绕规则求出区域的边界轮廓，消除自相交、重叠、重复点和尖刺，
+// 并在与自身接触的顶点处把轮廓拆开，使结果能通过Validate。
+// 外轮廓为逆时针，洞为顺时针；WindingRuleOdd的结果与按边线重建区域的常见做法一致
+func MakeValid(contours []Contour, windingRule WindingRule) ([]Contour, error) {
+	boundary, err := boundaryContours(contours, windingRule)
+	if err != nil {
+		return nil, err
+	}
+	// 交点坐标舍入为float32后，同一位置可能出现几个相距极近的顶点，顶点也可能恰好落在
+	// 其他边上，扫描无法可靠地处理这种接触。按Validate的容差合并顶点，并把落在边上的顶点
+	// 插入该边，再求一次边界，使相接的区域合并、与自身接触的轮廓成为重复经过的顶点后再拆开
+	tolerance := validationTolerance(contours)
+	if boundary, err = boundaryContours(nodeContours(boundary, tolerance), WindingRulePositive); err != nil {
+		return nil, err
+	}
+	var result []Contour
+	for _, c := range nodeContours(boundary, tolerance) {
+		for _, part := range splitSelfTouches(c) {
+			// 舍入也可能留下退化的细长轮廓，同样按Validate的标准去除
+			if ring, _ := cleanRing(part, 0, tolerance); ring != nil {
+				result = append(result, ring)
+			}
+		}
+	}
+	return result, nil
+}
+
+// nodeContours 合并相距不超过tolerance的顶点，并把与某条边（同一轮廓中相邻的边除外）
+// 相距不超过tolerance的顶点插入该边
+func nodeContours(contours []Contour, tolerance float64) []Contour {
+	grid := newPointGrid(tolerance)
+	snapped := make([]Contour, len(contours))
+	for i, c := range contours {
+		snapped[i] = make(Contour, len(c))
+		for k, v := range c {
+			snapped[i][k], _ = grid.snap(v)
+		}
+	}
+
+	segments := newRingSegments(snapped, tolerance)
+	inserted := make(map[ringSegment][]Vertex)
+	for ci, c := range snapped {
+		for k, v := range c {
+			for _, seg := range segments.near(v) {
+				r := snapped[seg.contour]
+				i := seg.index
+				a, b := r[i], r[(i+1)%len(r)]
+				if seg.contour == ci && (i == k || (i+1)%len(r) == k) {
+					continue
+				}
+				if (a.X == v.X && a.Y == v.Y) || (b.X == v.X && b.Y == v.Y) {
+					continue
+				}
+				inserted[seg] = append(inserted[seg], v)
+			}
+		}
+	}
+	if len(inserted) == 0 {
+		return snapped
+	}
+
+	result := make([]Contour, len(snapped))
+	for ci, c := range snapped {
+		for i, a := range c {
+			result[ci] = append(result[ci], a)
+			vs := inserted[ringSegment{ci, i}]
+			sort.Slice(vs, func(x, y int) bool {
+				return math.Hypot(float64(vs[x].X-a.X), float64(vs[x].Y-a.Y)) < math.Hypot(float64(vs[y].X-a.X), float64(vs[y].Y-a.Y))
+			})
+			for k, v := range vs {
+				if k == 0 || v != vs[k-1] {
+					result[ci] = append(result[ci], v)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// splitSelfTouches 在重复经过的顶点处把轮廓拆成多个简单轮廓，各部分保持原来的方向
+func splitSelfTouches(c Contour) []Contour {
+	var result []Contour
+	var stack Contour
+	seen := make(map[[2]float32]int)
+	for _, v := range c {
+		key := [2]float32{v.X, v.Y}
+		j, ok := seen[key]
+		if !ok {
+			seen[key] = len(stack)
+			stack = append(stack, v)
+			continue
+		}
+		loop := append(Contour(nil), stack[j:]...)
+		for _, w := range loop[1:] {
+			delete(seen, [2]float32{w.X, w.Y})
+		}
+		stack = stack[:j+1]
+		if len(loop) >= 3 {
+			result = append(result, loop)
+		}
+	}
+	if len(stack) >= 3 {
+		result = append(result, stack)
+	}
+	return result
+}
+
+// validationTolerance 判断点位于边上或两点重合时使用的距离，与坐标范围成比例
+func validationTolerance(contours []Contour) float64 {
+	extent := 0.0
+	for _, c := range contours {
+		for _, v := range c {
+			extent = math.Max(extent, math.Max(math.Abs(float64(v.X)), math.Abs(float64(v.Y))))
+		}
+	}
+	return 1e-6 * math.Max(extent, 1)
+}
+
+// cleanRing 报告轮廓i中的重复点、尖刺和顶点过少的问题，并返回去除它们之后的轮廓，
+// 剩余顶点少于3个时返回nil。去除尖刺后新出现的重复点不再单独报告
+func cleanRing(c Contour, i int, tolerance float64) (Contour, []ValidationProblem) {
+	var problems []ValidationProblem
+	report := func(t ValidationProblemType, v Vertex) {
+		problems = append(problems, ValidationProblem{Type: t, Point: v, Contours: []int{i}})
+	}
+	same := func(a, b Vertex) bool { return a.X == b.X && a.Y == b.Y }
+
+	ring := make(Contour, 0, len(c))
+	for k, v := range c {
+		if k > 0 && same(v, c[k-1]) {
+			report(ProblemDuplicatePoint, v)
+			continue
+		}
+		ring = append(ring, v)
+	}
+	if len(ring) > 1 && same(ring[0], ring[len(ring)-1]) {
+		report(ProblemDuplicatePoint, ring[0])
+		ring = ring[:len(ring)-1]
+	}
+
+	for removed := true; removed && len(ring) >= 3; {
+		removed = false
+		n := len(ring)
+		for k := 0; k < n; k++ {
+			a, b, c := ring[(k+n-1)%n], ring[k], ring[(k+1)%n]
+			if !isSpike(a, b, c, tolerance) {
+				continue
+			}
+			report(ProblemSpike, b)
+			ring = append(ring[:k], ring[k+1:]...)
+			if same(a, c) {
+				// 尖刺两侧的顶点重合
+				k %= len(ring)
+				ring = append(ring[:k], ring[k+1:]...)
+			}
+			removed = true
+			break
+		}
+	}
+
+	if len(ring) < 3 {
+		var v Vertex
+		if len(c) > 0 {
+			v = c[0]
+		}
+		report(ProblemTooFewPoints, v)
+		return nil, problems
+	}
+	return ring, problems
+}
+
+// uniqueTouches 合并扫描中记录的相距不超过tolerance的位置
+func uniqueTouches(touches [][3]float, tolerance float64) []Vertex {
+	grid := newPointGrid(tolerance)
+	var result []Vertex
+	for _, t := range touches {
+		v := Vertex{X: float32(t[0]), Y: float32(t[1]), Z: float32(t[2])}
+		if _, added := grid.snap(v); added {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// pointGrid 按tolerance大小的格子记录的点，用于合并相距不超过tolerance的点
+type pointGrid struct {
+	tolerance float64
+	cells     map[[2]int64][]Vertex
+}
+
+func newPointGrid(tolerance float64) *pointGrid {
+	return &pointGrid{tolerance: tolerance, cells: make(map[[2]int64][]Vertex)}
+}
+
+// snap 返回已记录的与v相距不超过tolerance的点；没有时记录v并返回它，added为true
+func (g *pointGrid) snap(v Vertex) (Vertex, bool) {
+	cx, cy := int64(math.Floor(float64(v.X)/g.tolerance)), int64(math.Floor(float64(v.Y)/g.tolerance))
+	for dx := int64(-1); dx <= 1; dx++ {
+		for dy := int64(-1); dy <= 1; dy++ {
+			for _, w := range g.cells[[2]int64{cx + dx, cy + dy}] {
+				if math.Hypot(float64(v.X-w.X), float64(v.Y-w.Y)) <= g.tolerance {
+					return w, false
+				}
+			}
+		}
+	}
+	g.cells[[2]int64{cx, cy}] = append(g.cells[[2]int64{cx, cy}], v)
+	return v, true
+}
+
+// ringSegment 轮廓contour中从第index个顶点出发的边
+type ringSegment struct {
+	contour, index int
+}
+
+// ringSegments 按均匀网格索引的轮廓边，用于找出经过某一点的所有边
+type ringSegments struct {
+	rings      []Contour
+	tolerance  float64
+	minX, minY float64
+	cellSize   float64
+	nx, ny     int
+	cells      [][]ringSegment
+}
+
+func newRingSegments(rings []Contour, tolerance float64) *ringSegments {
+	s := &ringSegments{rings: rings, tolerance: tolerance}
+	count := 0
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, r := range rings {
+		count += len(r)
+		for _, v := range r {
+			minX, minY = math.Min(minX, float64(v.X)), math.Min(minY, float64(v.Y))
+			maxX, maxY = math.Max(maxX, float64(v.X)), math.Max(maxY, float64(v.Y))
+		}
+	}
+	if count == 0 {
+		return s
+	}
+	// 格子数与边数相当
+	s.minX, s.minY = minX-tolerance, minY-tolerance
+	w, h := maxX-minX+2*tolerance, maxY-minY+2*tolerance
+	s.cellSize = math.Max(math.Sqrt(w*h/float64(count)), math.Max(w, h)/float64(count))
+	s.nx = maxInt(1, int(math.Ceil(w/s.cellSize)))
+	s.ny = maxInt(1, int(math.Ceil(h/s.cellSize)))
+	s.cells = make([][]ringSegment, s.nx*s.ny)
+	for c, r := range rings {
+		for i, v := range r {
+			w := r[(i+1)%len(r)]
+			x0, y0 := s.cell(math.Min(float64(v.X), float64(w.X))-tolerance, math.Min(float64(v.Y), float64(w.Y))-tolerance)
+			x1, y1 := s.cell(math.Max(float64(v.X), float64(w.X))+tolerance, math.Max(float64(v.Y), float64(w.Y))+tolerance)
+			for y := y0; y <= y1; y++ {
+				for x := x0; x <= x1; x++ {
+					s.cells[y*s.nx+x] = append(s.cells[y*s.nx+x], ringSegment{c, i})
+				}
+			}
+		}
+	}
+	return s
+}
+
+// cell 返回点所在的格子，超出范围时取最近的格子
+func (s *ringSegments) cell(x, y float64) (int, int) {
+	return clampInt(int((x-s.minX)/s.cellSize), 0, s.nx-1), clampInt(int((y-s.minY)/s.cellSize), 0, s.ny-1)
+}
+
+// near 返回与点p相距不超过tolerance的边
+func (s *ringSegments) near(p Vertex) []ringSegment {
+	if len(s.cells) == 0 {
+		return nil
+	}
+	var result []ringSegment
+	x, y := s.cell(float64(p.X), float64(p.Y))
+	for _, seg := range s.cells[y*s.nx+x] {
+		r := s.rings[seg.contour]
+		if segmentDistance(p, r[seg.index], r[(seg.index+1)%len(r)]) <= s.tolerance {
+			result = append(result, seg)
+		}
+	}
+	return result
+}
+
+// ringPass 轮廓经过某一点的一次：位于顶点上（position为顶点序号的2倍）
+// 或边的内部（position为边序号的2倍加1），in和out为来向和去向一侧的相邻点
+type ringPass struct {
+	contour  int
+	position int
+	in, out  Vertex
+}
+
+// classify 判断经过点p的各段轮廓之间是交叉、重叠还是仅仅接触，返回发现的问题
+func (s *ringSegments) classify(p Vertex) []ValidationProblem {
+	near := func(a Vertex) bool {
+		return math.Hypot(float64(a.X-p.X), float64(a.Y-p.Y)) <= s.tolerance
+	}
+	var passes []ringPass
+	seen := make(map[[2]int]bool)
+	for _, seg := range s.near(p) {
+		r := s.rings[seg.contour]
+		n := len(r)
+		a, b := r[seg.index], r[(seg.index+1)%n]
+		pass := ringPass{contour: seg.contour, position: 2*seg.index + 1, in: a, out: b}
+		if k := seg.index; near(a) {
+			pass = ringPass{contour: seg.contour, position: 2 * k, in: r[(k+n-1)%n], out: b}
+		} else if k := (seg.index + 1) % n; near(b) {
+			pass = ringPass{contour: seg.contour, position: 2 * k, in: a, out: r[(k+1)%n]}
+		}
+		if key := [2]int{pass.contour, pass.position}; !seen[key] {
+			seen[key] = true
+			passes = append(passes, pass)
+		}
+	}
+
+	found := make(map[ValidationProblemType]map[int]bool)
+	add := func(t ValidationProblemType, contours ...int) {
+		if found[t] == nil {
+			found[t] = make(map[int]bool)
+		}
+		for _, c := range contours {
+			found[t][c] = true
+		}
+	}
+	for i, a := range passes {
+		for _, b := range passes[i+1:] {
+			if a.contour == b.contour {
+				// 同一轮廓上相邻的两次经过是同一个拐角
+				n := 2 * len(s.rings[a.contour])
+				if d := (a.position - b.position + n) % n; d <= 2 || d >= n-2 {
+					continue
+				}
+			}
+			cross, overlap := passesCross(p, a, b)
+			switch {
+			case cross || overlap:
+				add(ProblemSelfIntersection, a.contour, b.contour)
+			case a.contour == b.contour:
+				add(ProblemRingSelfTouch, a.contour)
+			}
+		}
+	}
+
+	var problems []ValidationProblem
+	for _, t := range []ValidationProblemType{ProblemSelfIntersection, ProblemRingSelfTouch} {
+		if found[t] == nil {
+			continue
+		}
+		var contours []int
+		for c := range found[t] {
+			contours = append(contours, c)
+		}
+		sort.Ints(contours)
+		problems = append(problems, ValidationProblem{Type: t, Point: p, Contours: contours})
+	}
+	return problems
+}
+
+// passesCross 判断两次经过点p的轮廓是否在p处交叉：b的来向和去向分别位于a在p处分成的两个扇区中。
+// 两者有共同的方向时为重叠
+func passesCross(p Vertex, a, b ringPass) (cross, overlap bool) {
+	angle := func(v Vertex) float64 {
+		t := math.Atan2(float64(v.Y-p.Y), float64(v.X-p.X))
+		if t < 0 {
+			t += 2 * math.Pi
+		}
+		return t
+	}
+	direction := func(v Vertex) [2]float64 {
+		return [2]float64{float64(v.X - p.X), float64(v.Y - p.Y)}
+	}
+	for _, u := range []Vertex{a.in, a.out} {
+		for _, v := range []Vertex{b.in, b.out} {
+			du, dv := direction(u), direction(v)
+			cross := du[0]*dv[1] - du[1]*dv[0]
+			if du[0]*dv[0]+du[1]*dv[1] > 0 && math.Abs(cross) <= 1e-9*math.Hypot(du[0], du[1])*math.Hypot(dv[0], dv[1]) {
+				return false, true
+			}
+		}
+	}
+	from := angle(a.in)
+	sector := math.Mod(angle(a.out)-from+2*math.Pi, 2*math.Pi)
+	inside := func(v Vertex) bool {
+		return math.Mod(angle(v)-from+2*math.Pi, 2*math.Pi) < sector
+	}
+	return inside(b.in) != inside(b.out), false
+}
+
+// segmentDistance 点p到线段ab的距离
+func segmentDistance(p, a, b Vertex) float64 {
+	dx, dy := float64(b.X-a.X), float64(b.Y-a.Y)
+	px, py := float64(p.X-a.X), float64(p.Y-a.Y)
+	t := 0.0
+	if l := dx*dx + dy*dy; l > 0 {
+		t = math.Max(0, math.Min(1, (px*dx+py*dy)/l))
+	}
+	return math.Hypot(px-t*dx, py-t*dy)
+}
+
+// nestingProblems 按嵌套层数检查轮廓方向。自相交的轮廓不参与检查，
+// 顶层的顺时针轮廓在存在顶层逆时针轮廓时视为位于外轮廓之外的洞
+func nestingProblems(rings []Contour, crossing map[int]bool) []ValidationProblem {
+	var index []int
+	var planar []Contour
+	for i, r := range rings {
+		if r != nil && contourSignedArea(r) != 0 {
+			index = append(index, i)
+			planar = append(planar, r)
+		}
+	}
+	nested := newNestingRings(planar)
+	depths := make([]int, len(planar))
+	topLevelShell := false
+	for i := range planar {
+		for j := range planar {
+			if i != j && nested.inside(i, j) {
+				depths[i]++
+			}
+		}
+		if depths[i] == 0 && contourSignedArea(planar[i]) > 0 {
+			topLevelShell = true
+		}
+	}
+
+	var problems []ValidationProblem
+	for i, r := range planar {
+		if crossing[index[i]] || (depths[i]%2 == 0) == (contourSignedArea(r) > 0) {
+			continue
+		}
+		t := ProblemWrongOrientation
+		if depths[i] == 0 && topLevelShell {
+			t = ProblemHoleOutsideShell
+		}
+		problems = append(problems, ValidationProblem{Type: t, Point: r[0], Contours: []int{index[i]}})
+	}
+	return problems
+}
@@ -0,0 +1,110 @@
+package tesselator
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// reversedContour 返回方向相反的轮廓
+func reversedContour(c Contour) Contour {
+	reverseContour(c)
+	return c
+}
+
+// TestValidate 每种问题的位置和涉及的轮廓
+func TestValidate(t *testing.T) {
+	type found struct {
+		Type     ValidationProblemType
+		X, Y     float32
+		Contours []int
+	}
+	cases := []struct {
+		name     string
+		contours []Contour
+		expected []found
+	}{
+		{"valid", []Contour{squareContour(0, 0, 10), reversedContour(squareContour(2, 2, 3))}, nil},
+		// 洞与外轮廓在一点接触是允许的
+		{"hole touching shell", []Contour{squareContour(0, 0, 10), reversedContour(toContour([]Vector2f{{0, 0}, {5, 2}, {2, 5}}))}, nil},
+		{"bowtie", []Contour{toContour([]Vector2f{{0, 0}, {4, 4}, {4, 0}, {0, 4}})},
+			[]found{{ProblemSelfIntersection, 2, 2, []int{0}}}},
+		{"self touch", []Contour{toContour([]Vector2f{{0, 0}, {10, 0}, {10, 10}, {5, 0}, {0, 10}})},
+			[]found{{ProblemRingSelfTouch, 5, 0, []int{0}}}},
+		{"crossing rings", []Contour{squareContour(0, 0, 10), reversedContour(squareContour(8, 2, 5))},
+			[]found{{ProblemSelfIntersection, 10, 2, []int{0, 1}}, {ProblemSelfIntersection, 10, 7, []int{0, 1}}}},
+		{"shared edge", []Contour{squareContour(0, 0, 10), squareContour(10, 0, 10)},
+			[]found{{ProblemSelfIntersection, 10, 0, []int{0, 1}}, {ProblemSelfIntersection, 10, 10, []int{0, 1}}}},
+		{"hole outside shell", []Contour{squareContour(0, 0, 10), reversedContour(squareContour(20, 0, 3))},
+			[]found{{ProblemHoleOutsideShell, 20, 3, []int{1}}}},
+		{"clockwise shell", []Contour{reversedContour(squareContour(0, 0, 10))},
+			[]found{{ProblemWrongOrientation, 0, 10, []int{0}}}},
+		{"counter-clockwise hole", []Contour{squareContour(0, 0, 10), squareContour(2, 2, 3)},
+			[]found{{ProblemWrongOrientation, 2, 2, []int{1}}}},
+		{"duplicate points", []Contour{toContour([]Vector2f{{0, 0}, {10, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}})},
+			[]found{{ProblemDuplicatePoint, 0, 0, []int{0}}, {ProblemDuplicatePoint, 10, 0, []int{0}}}},
+		{"spike", []Contour{toContour([]Vector2f{{0, 0}, {10, 0}, {10, 5}, {14, 5}, {10, 5}, {10, 10}, {0, 10}})},
+			[]found{{ProblemSpike, 14, 5, []int{0}}}},
+		{"too few points", []Contour{squareContour(0, 0, 10), toContour([]Vector2f{{0, 0}, {1, 1}})},
+			[]found{{ProblemTooFewPoints, 0, 0, []int{1}}}},
+	}
+	for _, c := range cases {
+		problems, err := Validate(c.contours)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []found
+		for _, p := range problems {
+			got = append(got, found{p.Type, p.Point.X, p.Point.Y, p.Contours})
+		}
+		if !reflect.DeepEqual(got, c.expected) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, problems)
+		}
+	}
+}
+
+// TestMakeValid 修复后的轮廓通过检查，面积与奇偶规则的剖分结果一致
+func TestMakeValid(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		// 随机顶点组成的轮廓通常自相交，整数坐标使顶点经常落在其他边上
+		var contours []Contour
+		for k := 0; k < 1+r.Intn(3); k++ {
+			var c Contour
+			for j := 0; j < 3+r.Intn(8); j++ {
+				if i%2 == 0 {
+					c = append(c, Vertex{X: float32(r.Intn(20)), Y: float32(r.Intn(20))})
+				} else {
+					c = append(c, Vertex{X: float32(r.Float64() * 20), Y: float32(r.Float64() * 20)})
+				}
+			}
+			contours = append(contours, c)
+		}
+		repaired, err := MakeValid(contours, WindingRuleOdd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if problems, _ := Validate(repaired); len(problems) != 0 {
+			t.Fatalf("repaired contours of %v are invalid: %v", contours, problems)
+		}
+		indices, vertices, _ := Tesselate(contours, WindingRuleOdd)
+		expected := meshArea(indices, vertices)
+		area := 0.0
+		for _, c := range repaired {
+			area += contourSignedArea(c)
+		}
+		if math.Abs(area-expected) > 1e-3*math.Max(1, expected) {
+			t.Errorf("repaired area %.4f of %v differs from the tessellated area %.4f", area, contours, expected)
+		}
+	}
+
+	// 与自身接触的轮廓拆成外轮廓和在该点接触的洞
+	repaired, err := MakeValid([]Contour{toContour([]Vector2f{{0, 0}, {10, 0}, {10, 10}, {5, 0}, {0, 10}})}, WindingRuleOdd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repaired) != 2 {
+		t.Errorf("self-touching ring should split into two rings, got %v", repaired)
+	}
+}
@@ -0,0 +1,433 @@
+package tesselator
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// GeometryType WKB/WKT几何类型，取值与OGC WKB类型码一致
+type GeometryType int
+
+const (
+	GeometryPolygon      GeometryType = 3
+	GeometryMultiPolygon GeometryType = 6
+	GeometryCollection   GeometryType = 7
+	GeometryTIN          GeometryType = 16
+	GeometryTriangle     GeometryType = 17
+)
+
+func (t GeometryType) String() string {
+	switch t {
+	case GeometryPolygon:
+		return "POLYGON"
+	case GeometryMultiPolygon:
+		return "MULTIPOLYGON"
+	case GeometryCollection:
+		return "GEOMETRYCOLLECTION"
+	case GeometryTIN:
+		return "TIN"
+	case GeometryTriangle:
+		return "TRIANGLE"
+	}
+	return fmt.Sprintf("GeometryType(%d)", int(t))
+}
+
+// EWKB标志位（PostGIS扩展）
+const (
+	ewkbZ    = 0x80000000
+	ewkbM    = 0x40000000
+	ewkbSRID = 0x20000000
+)
+
+// Geometry 面状几何对象，WKT和WKB读写共用。
+// 每个环对应一个轮廓，环末尾的闭合点在读取时去掉、在写出时补上。
+type Geometry struct {
+	Type GeometryType
+	SRID int // 0表示未指定
+	HasZ bool
+	HasM bool
+
+	// Polygon和Triangle只有一个元素；MultiPolygon和TIN每个多边形（三角形）一个元素，
+	// 每个多边形的第一个轮廓为外环，其余为洞
+	Polygons [][]Contour
+	// M值，与Polygons的多边形、环、顶点一一对应，仅在HasM时使用
+	M [][][]float64
+
+	// GeometryCollection的子几何对象
+	Geometries []*Geometry
+}
+
+// Contours 返回几何对象（包括集合中的子对象）的全部环
+func (g *Geometry) Contours() []Contour {
+	var contours []Contour
+	for _, polygon := range g.Polygons {
+		contours = append(contours, polygon...)
+	}
+	for _, child := range g.Geometries {
+		contours = append(contours, child.Contours()...)
+	}
+	return contours
+}
+
+// Tesselate 对几何对象的全部环进行三角剖分
+func (g *Geometry) Tesselate(windingRule WindingRule) ([]int, []Vertex, error) {
+	return Tesselate(g.Contours(), windingRule)
+}
+
+// TriangleGeometry 将三角剖分结果转换为MULTIPOLYGON或TIN，每个三角形在XY平面上为逆时针
+func TriangleGeometry(indices []int, vertices []Vertex, geomType GeometryType) (*Geometry, error) {
+	if geomType != GeometryMultiPolygon && geomType != GeometryTIN {
+		return nil, fmt.Errorf("triangles can only be written as MULTIPOLYGON or TIN, not %v", geomType)
+	}
+	g := &Geometry{Type: geomType, Polygons: make([][]Contour, 0, len(indices)/3)}
+	for _, v := range vertices {
+		if v.Z != 0 {
+			g.HasZ = true
+			break
+		}
+	}
+	for i := 0; i+2 < len(indices); i += 3 {
+		a, b, c := vertices[indices[i]], vertices[indices[i+1]], vertices[indices[i+2]]
+		if (b.X-a.X)*(c.Y-a.Y)-(b.Y-a.Y)*(c.X-a.X) < 0 {
+			b, c = c, b
+		}
+		g.Polygons = append(g.Polygons, []Contour{{a, b, c}})
+	}
+	return g, nil
+}
+
+func (g *Geometry) mValue(p, r, i int) float64 {
+	if p < len(g.M) && r < len(g.M[p]) && i < len(g.M[p][r]) {
+		return g.M[p][r][i]
+	}
+	return 0
+}
+
+// ParseWKB 解析ISO WKB或PostGIS EWKB，支持大端和小端字节序以及Z、M维度
+func ParseWKB(data []byte) (*Geometry, error) {
+	r := &wkbReader{data: data}
+	g, err := r.geometry(0)
+	if err != nil {
+		return nil, err
+	}
+	if r.pos != len(r.data) {
+		return nil, fmt.Errorf("wkb: %d trailing bytes", len(r.data)-r.pos)
+	}
+	return g, nil
+}
+
+type wkbReader struct {
+	data  []byte
+	pos   int
+	order binary.ByteOrder
+}
+
+func (r *wkbReader) need(n int) error {
+	if n < 0 || len(r.data)-r.pos < n {
+		return fmt.Errorf("wkb: unexpected end of data at offset %d", r.pos)
+	}
+	return nil
+}
+
+func (r *wkbReader) uint32() (uint32, error) {
+	if err := r.need(4); err != nil {
+		return 0, err
+	}
+	v := r.order.Uint32(r.data[r.pos:])
+	r.pos += 4
+	return v, nil
+}
+
+func (r *wkbReader) float64() float64 {
+	v := math.Float64frombits(r.order.Uint64(r.data[r.pos:]))
+	r.pos += 8
+	return v
+}
+
+// count 读取元素个数，并检查剩余数据至少能容纳每个元素minSize字节
+func (r *wkbReader) count(minSize int) (int, error) {
+	n, err := r.uint32()
+	if err != nil {
+		return 0, err
+	}
+	if uint64(n)*uint64(minSize) > uint64(len(r.data)-r.pos) {
+		return 0, fmt.Errorf("wkb: count %d exceeds remaining data at offset %d", n, r.pos)
+	}
+	return int(n), nil
+}
+
+func (r *wkbReader) geometry(depth int) (*Geometry, error) {
+	if depth > 32 {
+		return nil, fmt.Errorf("wkb: geometry nested too deeply")
+	}
+	if err := r.need(1); err != nil {
+		return nil, err
+	}
+	switch r.data[r.pos] {
+	case 0:
+		r.order = binary.BigEndian
+	case 1:
+		r.order = binary.LittleEndian
+	default:
+		return nil, fmt.Errorf("wkb: invalid byte order %d", r.data[r.pos])
+	}
+	r.pos++
+
+	code, err := r.uint32()
+	if err != nil {
+		return nil, err
+	}
+	g := &Geometry{
+		HasZ: code&ewkbZ != 0,
+		HasM: code&ewkbM != 0,
+	}
+	if code&ewkbSRID != 0 {
+		srid, err := r.uint32()
+		if err != nil {
+			return nil, err
+		}
+		g.SRID = int(int32(srid))
+	}
+	// ISO WKB用千位表示维度：1000 Z，2000 M，3000 ZM
+	base := code & 0x0fffffff
+	switch base / 1000 {
+	case 1:
+		g.HasZ = true
+	case 2:
+		g.HasM = true
+	case 3:
+		g.HasZ, g.HasM = true, true
+	}
+	g.Type = GeometryType(base % 1000)
+
+	switch g.Type {
+	case GeometryPolygon, GeometryTriangle:
+		rings, ms, err := r.polygon(g.HasZ, g.HasM)
+		if err != nil {
+			return nil, err
+		}
+		g.Polygons = [][]Contour{rings}
+		if g.HasM {
+			g.M = [][][]float64{ms}
+		}
+	case GeometryMultiPolygon, GeometryTIN:
+		n, err := r.count(9)
+		if err != nil {
+			return nil, err
+		}
+		want := GeometryPolygon
+		if g.Type == GeometryTIN {
+			want = GeometryTriangle
+		}
+		for i := 0; i < n; i++ {
+			child, err := r.geometry(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			if child.Type != want {
+				return nil, fmt.Errorf("wkb: %v cannot contain %v", g.Type, child.Type)
+			}
+			g.Polygons = append(g.Polygons, child.Polygons...)
+			if g.HasM {
+				g.M = append(g.M, child.mPolygon(0))
+			}
+		}
+	case GeometryCollection:
+		n, err := r.count(9)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < n; i++ {
+			child, err := r.geometry(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			if child.SRID == 0 {
+				child.SRID = g.SRID
+			}
+			g.Geometries = append(g.Geometries, child)
+		}
+	default:
+		return nil, fmt.Errorf("wkb: unsupported geometry type %d", base)
+	}
+	return g, nil
+}
+
+func (g *Geometry) mPolygon(p int) [][]float64 {
+	if p < len(g.M) {
+		return g.M[p]
+	}
+	ms := make([][]float64, len(g.Polygons[p]))
+	for r, ring := range g.Polygons[p] {
+		ms[r] = make([]float64, len(ring))
+	}
+	return ms
+}
+
+func (r *wkbReader) polygon(hasZ, hasM bool) ([]Contour, [][]float64, error) {
+	dims := 2
+	if hasZ {
+		dims++
+	}
+	if hasM {
+		dims++
+	}
+	numRings, err := r.count(4)
+	if err != nil {
+		return nil, nil, err
+	}
+	rings := make([]Contour, 0, numRings)
+	var ms [][]float64
+	for i := 0; i < numRings; i++ {
+		n, err := r.count(dims * 8)
+		if err != nil {
+			return nil, nil, err
+		}
+		ring := make(Contour, n)
+		var m []float64
+		if hasM {
+			m = make([]float64, n)
+		}
+		for j := 0; j < n; j++ {
+			ring[j].X = float32(r.float64())
+			ring[j].Y = float32(r.float64())
+			if hasZ {
+				ring[j].Z = float32(r.float64())
+			}
+			if hasM {
+				m[j] = r.float64()
+			}
+		}
+		// 去掉闭合点
+		if n > 1 && ring[0] == ring[n-1] && (!hasM || m[0] == m[n-1]) {
+			ring = ring[:n-1]
+			if hasM {
+				m = m[:n-1]
+			}
+		}
+		rings = append(rings, ring)
+		if hasM {
+			ms = append(ms, m)
+		}
+	}
+	return rings, ms, nil
+}
+
+// WKB 以指定字节序编码为ISO WKB，不包含SRID
+func (g *Geometry) WKB(order binary.ByteOrder) []byte {
+	w := &wkbWriter{order: order}
+	w.geometry(g, false, false)
+	return w.buf
+}
+
+// EWKB 以指定字节序编码为PostGIS EWKB，SRID非零时写入顶层对象
+func (g *Geometry) EWKB(order binary.ByteOrder) []byte {
+	w := &wkbWriter{order: order}
+	w.geometry(g, true, g.SRID != 0)
+	return w.buf
+}
+
+type wkbWriter struct {
+	buf   []byte
+	order binary.ByteOrder
+}
+
+func (w *wkbWriter) uint32(v uint32) {
+	var b [4]byte
+	w.order.PutUint32(b[:], v)
+	w.buf = append(w.buf, b[:]...)
+}
+
+func (w *wkbWriter) float64(v float64) {
+	var b [8]byte
+	w.order.PutUint64(b[:], math.Float64bits(v))
+	w.buf = append(w.buf, b[:]...)
+}
+
+func (w *wkbWriter) header(t GeometryType, hasZ, hasM, ewkb bool, srid int) {
+	if w.order == binary.BigEndian {
+		w.buf = append(w.buf, 0)
+	} else {
+		w.buf = append(w.buf, 1)
+	}
+	code := uint32(t)
+	if ewkb {
+		if hasZ {
+			code |= ewkbZ
+		}
+		if hasM {
+			code |= ewkbM
+		}
+		if srid != 0 {
+			code |= ewkbSRID
+		}
+	} else {
+		if hasZ {
+			code += 1000
+		}
+		if hasM {
+			code += 2000
+		}
+	}
+	w.uint32(code)
+	if ewkb && srid != 0 {
+		w.uint32(uint32(int32(srid)))
+	}
+}
+
+func (w *wkbWriter) geometry(g *Geometry, ewkb, withSRID bool) {
+	srid := 0
+	if withSRID {
+		srid = g.SRID
+	}
+	w.header(g.Type, g.HasZ, g.HasM, ewkb, srid)
+
+	switch g.Type {
+	case GeometryPolygon, GeometryTriangle:
+		if len(g.Polygons) > 0 {
+			w.polygon(g, 0)
+		} else {
+			w.uint32(0)
+		}
+	case GeometryMultiPolygon, GeometryTIN:
+		childType := GeometryPolygon
+		if g.Type == GeometryTIN {
+			childType = GeometryTriangle
+		}
+		w.uint32(uint32(len(g.Polygons)))
+		for p := range g.Polygons {
+			w.header(childType, g.HasZ, g.HasM, ewkb, 0)
+			w.polygon(g, p)
+		}
+	case GeometryCollection:
+		w.uint32(uint32(len(g.Geometries)))
+		for _, child := range g.Geometries {
+			w.geometry(child, ewkb, false)
+		}
+	}
+}
+
+func (w *wkbWriter) polygon(g *Geometry, p int) {
+	rings := g.Polygons[p]
+	w.uint32(uint32(len(rings)))
+	for r, ring := range rings {
+		if len(ring) == 0 {
+			w.uint32(0)
+			continue
+		}
+		w.uint32(uint32(len(ring) + 1))
+		for i := 0; i <= len(ring); i++ {
+			j := i % len(ring)
+			v := ring[j]
+			w.float64(float64(v.X))
+			w.float64(float64(v.Y))
+			if g.HasZ {
+				w.float64(float64(v.Z))
+			}
+			if g.HasM {
+				w.float64(g.mValue(p, r, j))
+			}
+		}
+	}
+}
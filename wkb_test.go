@@ -0,0 +1,132 @@
+package tesselator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+)
+
+// TestParseWKBKnownBytes 测试解析已知的WKB和EWKB字节
+func TestParseWKBKnownBytes(t *testing.T) {
+	// PostGIS: ST_AsEWKB('SRID=4326;POLYGON((0 0,1 0,0 1,0 0))'::geometry) 的小端表示
+	ewkb, _ := hex.DecodeString("0103000020E610000001000000" +
+		"0400000000000000000000000000000000000000000000000000F03F00000000" +
+		"000000000000000000000000000000000000F03F000000000000000000000000" +
+		"00000000")
+	g, err := ParseWKB(ewkb)
+	if err != nil {
+		t.Fatalf("ParseWKB returned an error: %v", err)
+	}
+	if g.Type != GeometryPolygon || g.SRID != 4326 || g.HasZ || g.HasM {
+		t.Errorf("unexpected geometry header %+v", g)
+	}
+	if c := g.Contours(); len(c) != 1 || len(c[0]) != 3 || c[0][1].X != 1 || c[0][2].Y != 1 {
+		t.Errorf("unexpected contours %v", c)
+	}
+
+	// 大端ISO WKB：POLYGON Z，类型码1003
+	iso, _ := hex.DecodeString("00000003EB0000000100000004" +
+		"0000000000000000000000000000000040000000000000003FF0000000000000" +
+		"0000000000000000400000000000000000000000000000003FF0000000000000" +
+		"4000000000000000000000000000000000000000000000004000000000000000")
+	g, err = ParseWKB(iso)
+	if err != nil {
+		t.Fatalf("ParseWKB returned an error: %v", err)
+	}
+	if g.Type != GeometryPolygon || !g.HasZ || g.Contours()[0][0].Z != 2 {
+		t.Errorf("unexpected big-endian geometry %+v", g)
+	}
+
+	for _, bad := range [][]byte{
+		nil,
+		{2, 3, 0, 0, 0},
+		ewkb[:len(ewkb)-1],
+		append(append([]byte{}, ewkb...), 0),
+		{1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+		// 环的点数远超剩余数据
+		{1, 3, 0, 0, 0, 1, 0, 0, 0, 0xff, 0xff, 0xff, 0x7f},
+	} {
+		if _, err := ParseWKB(bad); err == nil {
+			t.Errorf("%x: expected an error", bad)
+		}
+	}
+}
+
+// TestWKBRoundTrip 测试各种类型、维度和字节序的WKB/EWKB往返
+func TestWKBRoundTrip(t *testing.T) {
+	inputs := []string{
+		"POLYGON EMPTY",
+		"POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0), (2 2, 2 8, 8 8, 8 2, 2 2))",
+		"POLYGON Z ((0 0 1, 1 0 2, 0 1 3, 0 0 1))",
+		"POLYGON M ((0 0 5, 1 0 6, 0 1 7, 0 0 5))",
+		"POLYGON ZM ((0 0 1 5, 1 0 2 6, 0 1 3 7, 0 0 1 5))",
+		"MULTIPOLYGON Z (((0 0 0, 4 0 0, 0 4 0, 0 0 0)), ((10 10 1, 12 10 1, 10 12 1, 10 10 1)))",
+		"GEOMETRYCOLLECTION (POLYGON ((0 0, 1 0, 0 1, 0 0)), MULTIPOLYGON M (((0 0 3, 1 0 3, 0 1 3, 0 0 3))), GEOMETRYCOLLECTION EMPTY)",
+		"TIN Z (((0 0 0, 1 0 0, 0 1 1, 0 0 0)), ((1 0 0, 1 1 1, 0 1 1, 1 0 0)))",
+	}
+	for _, s := range inputs {
+		g, err := ParseWKT(s)
+		if err != nil {
+			t.Fatalf("%q: %v", s, err)
+		}
+		for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+			data := g.WKB(order)
+			back, err := ParseWKB(data)
+			if err != nil {
+				t.Fatalf("%q: failed to parse WKB: %v", s, err)
+			}
+			if out := back.WKT(); out != s {
+				t.Errorf("WKB round trip mismatch:\n got %s\nwant %s", out, s)
+			}
+
+			g.SRID = 2154
+			data = g.EWKB(order)
+			back, err = ParseWKB(data)
+			if err != nil {
+				t.Fatalf("%q: failed to parse EWKB: %v", s, err)
+			}
+			if back.SRID != 2154 || back.WKT() != s {
+				t.Errorf("EWKB round trip mismatch: SRID %d, %s", back.SRID, back.WKT())
+			}
+			g.SRID = 0
+		}
+	}
+
+	// SRID只写在顶层对象上
+	g, _ := ParseWKT("SRID=4326;GEOMETRYCOLLECTION (POLYGON ((0 0, 1 0, 0 1, 0 0)))")
+	a := g.EWKB(binary.LittleEndian)
+	g.Geometries[0].SRID = 0
+	b := g.EWKB(binary.LittleEndian)
+	if !bytes.Equal(a, b) {
+		t.Error("collection members should not write their own SRID")
+	}
+	back, _ := ParseWKB(a)
+	if back.Geometries[0].SRID != 4326 {
+		t.Error("collection members should inherit the SRID")
+	}
+}
+
+// TestTriangleGeometryWKB 测试三角剖分结果经EWKB往返
+func TestTriangleGeometryWKB(t *testing.T) {
+	contours := []Contour{{{X: 0, Y: 0, Z: 1}, {X: 4, Y: 0, Z: 1}, {X: 4, Y: 4, Z: 2}, {X: 0, Y: 4, Z: 2}}}
+	indices, vertices, err := Tesselate(contours, WindingRuleOdd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tin, _ := TriangleGeometry(indices, vertices, GeometryTIN)
+	if !tin.HasZ {
+		t.Error("TIN should keep Z when the input has non-zero Z")
+	}
+	tin.SRID = 4979
+	back, err := ParseWKB(tin.EWKB(binary.BigEndian))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.Type != GeometryTIN || back.SRID != 4979 || len(back.Polygons) != 2 {
+		t.Errorf("unexpected TIN after round trip: %s", back.EWKT())
+	}
+	if idx, verts, _ := back.Tesselate(WindingRuleNonzero); meshArea(idx, verts) < 16-1e-3 {
+		t.Errorf("TIN should cover the original square")
+	}
+}
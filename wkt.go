@@ -0,0 +1,392 @@
+package tesselator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseWKT 解析WKT或PostGIS EWKT（可带"SRID=4326;"前缀）中的POLYGON、MULTIPOLYGON、
+// GEOMETRYCOLLECTION、TIN和TRIANGLE，支持Z、M和ZM维度。
+// 没有维度关键字时，3个坐标视为XYZ，4个坐标视为XYZM。
+func ParseWKT(s string) (*Geometry, error) {
+	srid := 0
+	if rest := strings.TrimSpace(s); len(rest) > 5 && strings.EqualFold(rest[:5], "SRID=") {
+		semi := strings.IndexByte(rest, ';')
+		if semi < 0 {
+			return nil, fmt.Errorf("wkt: missing ';' after SRID")
+		}
+		v, err := strconv.Atoi(strings.TrimSpace(rest[5:semi]))
+		if err != nil {
+			return nil, fmt.Errorf("wkt: invalid SRID %q", rest[5:semi])
+		}
+		srid = v
+		s = rest[semi+1:]
+	}
+
+	p := &wktParser{s: s}
+	g, err := p.geometry(0)
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.next(); tok != "" {
+		return nil, fmt.Errorf("wkt: unexpected %q after geometry", tok)
+	}
+	g.setSRID(srid)
+	return g, nil
+}
+
+func (g *Geometry) setSRID(srid int) {
+	g.SRID = srid
+	for _, child := range g.Geometries {
+		child.setSRID(srid)
+	}
+}
+
+type wktParser struct {
+	s   string
+	pos int
+}
+
+// next 返回下一个记号：括号、逗号或者由字母、数字和符号组成的单词
+func (p *wktParser) next() string {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+	if p.pos >= len(p.s) {
+		return ""
+	}
+	start := p.pos
+	if c := p.s[p.pos]; c == '(' || c == ')' || c == ',' {
+		p.pos++
+		return p.s[start:p.pos]
+	}
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n(),", p.s[p.pos]) < 0 {
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+func (p *wktParser) peek() string {
+	pos := p.pos
+	tok := p.next()
+	p.pos = pos
+	return tok
+}
+
+func (p *wktParser) expect(tok string) error {
+	if got := p.next(); got != tok {
+		if got == "" {
+			got = "end of input"
+		}
+		return fmt.Errorf("wkt: expected %q, got %q", tok, got)
+	}
+	return nil
+}
+
+func (p *wktParser) geometry(depth int) (*Geometry, error) {
+	if depth > 32 {
+		return nil, fmt.Errorf("wkt: geometry nested too deeply")
+	}
+	word := strings.ToUpper(p.next())
+	g := &Geometry{}
+
+	// EWKT允许把M写在类型名后面，如POLYGONM
+	for _, t := range []GeometryType{GeometryPolygon, GeometryMultiPolygon, GeometryCollection, GeometryTIN, GeometryTriangle} {
+		name := t.String()
+		if word == name || word == name+"M" {
+			g.Type = t
+			g.HasM = word != name
+			break
+		}
+	}
+	if g.Type == 0 {
+		if word == "" {
+			return nil, fmt.Errorf("wkt: unexpected end of input")
+		}
+		return nil, fmt.Errorf("wkt: unsupported geometry type %q", word)
+	}
+
+	explicitDims := g.HasM
+	switch strings.ToUpper(p.peek()) {
+	case "Z":
+		g.HasZ = true
+		explicitDims = true
+		p.next()
+	case "M":
+		g.HasM = true
+		explicitDims = true
+		p.next()
+	case "ZM":
+		g.HasZ, g.HasM = true, true
+		explicitDims = true
+		p.next()
+	}
+	if strings.EqualFold(p.peek(), "EMPTY") {
+		p.next()
+		return g, nil
+	}
+
+	dims := 0
+	if explicitDims {
+		dims = 2
+		if g.HasZ {
+			dims++
+		}
+		if g.HasM {
+			dims++
+		}
+	}
+
+	switch g.Type {
+	case GeometryPolygon, GeometryTriangle:
+		rings, ms, err := p.polygon(&dims, g.HasM && !g.HasZ)
+		if err != nil {
+			return nil, err
+		}
+		g.Polygons = [][]Contour{rings}
+		g.M = [][][]float64{ms}
+	case GeometryMultiPolygon, GeometryTIN:
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		for {
+			if strings.EqualFold(p.peek(), "EMPTY") {
+				p.next()
+				g.Polygons = append(g.Polygons, nil)
+				g.M = append(g.M, nil)
+			} else {
+				rings, ms, err := p.polygon(&dims, g.HasM && !g.HasZ)
+				if err != nil {
+					return nil, err
+				}
+				g.Polygons = append(g.Polygons, rings)
+				g.M = append(g.M, ms)
+			}
+			if tok := p.next(); tok == ")" {
+				break
+			} else if tok != "," {
+				return nil, fmt.Errorf("wkt: expected ',' or ')', got %q", tok)
+			}
+		}
+	case GeometryCollection:
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		for {
+			child, err := p.geometry(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			g.Geometries = append(g.Geometries, child)
+			if tok := p.next(); tok == ")" {
+				break
+			} else if tok != "," {
+				return nil, fmt.Errorf("wkt: expected ',' or ')', got %q", tok)
+			}
+		}
+		return g, nil
+	}
+
+	if !explicitDims {
+		g.HasZ = dims >= 3
+		g.HasM = dims == 4
+	}
+	if !g.HasM {
+		g.M = nil
+	}
+	return g, nil
+}
+
+// polygon 解析 ((x y, ...), (...))。dims为0时由第一个坐标确定维度，
+// mOnly表示3个坐标为XYM
+func (p *wktParser) polygon(dims *int, mOnly bool) ([]Contour, [][]float64, error) {
+	if err := p.expect("("); err != nil {
+		return nil, nil, err
+	}
+	var rings []Contour
+	var ms [][]float64
+	for {
+		if err := p.expect("("); err != nil {
+			return nil, nil, err
+		}
+		var ring Contour
+		var m []float64
+		for {
+			var coords [4]float64
+			n := 0
+			for {
+				tok := p.peek()
+				if tok == "," || tok == ")" || tok == "" {
+					break
+				}
+				p.next()
+				if n == 4 {
+					return nil, nil, fmt.Errorf("wkt: too many coordinates")
+				}
+				v, err := strconv.ParseFloat(tok, 64)
+				if err != nil {
+					return nil, nil, fmt.Errorf("wkt: invalid number %q", tok)
+				}
+				coords[n] = v
+				n++
+			}
+			if *dims == 0 {
+				if n < 2 {
+					return nil, nil, fmt.Errorf("wkt: position needs at least 2 coordinates, got %d", n)
+				}
+				*dims = n
+			}
+			if n != *dims {
+				return nil, nil, fmt.Errorf("wkt: expected %d coordinates, got %d", *dims, n)
+			}
+			v := Vertex{X: float32(coords[0]), Y: float32(coords[1])}
+			switch n {
+			case 3:
+				if mOnly {
+					m = append(m, coords[2])
+				} else {
+					v.Z = float32(coords[2])
+					m = append(m, 0)
+				}
+			case 4:
+				v.Z = float32(coords[2])
+				m = append(m, coords[3])
+			default:
+				m = append(m, 0)
+			}
+			ring = append(ring, v)
+
+			if tok := p.next(); tok == ")" {
+				break
+			} else if tok != "," {
+				return nil, nil, fmt.Errorf("wkt: expected ',' or ')', got %q", tok)
+			}
+		}
+		if n := len(ring); n > 1 && ring[0] == ring[n-1] && m[0] == m[n-1] {
+			ring = ring[:n-1]
+			m = m[:n-1]
+		}
+		rings = append(rings, ring)
+		ms = append(ms, m)
+
+		if tok := p.next(); tok == ")" {
+			break
+		} else if tok != "," {
+			return nil, nil, fmt.Errorf("wkt: expected ',' or ')', got %q", tok)
+		}
+	}
+	return rings, ms, nil
+}
+
+// WKT 编码为WKT，不包含SRID
+func (g *Geometry) WKT() string {
+	var sb strings.Builder
+	g.writeWKT(&sb)
+	return sb.String()
+}
+
+// EWKT 编码为PostGIS EWKT，SRID非零时带"SRID=n;"前缀
+func (g *Geometry) EWKT() string {
+	if g.SRID == 0 {
+		return g.WKT()
+	}
+	return fmt.Sprintf("SRID=%d;%s", g.SRID, g.WKT())
+}
+
+func (g *Geometry) writeWKT(sb *strings.Builder) {
+	sb.WriteString(g.Type.String())
+	switch {
+	case g.HasZ && g.HasM:
+		sb.WriteString(" ZM")
+	case g.HasZ:
+		sb.WriteString(" Z")
+	case g.HasM:
+		sb.WriteString(" M")
+	}
+
+	switch g.Type {
+	case GeometryPolygon, GeometryTriangle:
+		if len(g.Polygons) == 0 || emptyWKTPolygon(g.Polygons[0]) {
+			sb.WriteString(" EMPTY")
+			return
+		}
+		sb.WriteByte(' ')
+		g.writeWKTPolygon(sb, 0)
+	case GeometryMultiPolygon, GeometryTIN:
+		if len(g.Polygons) == 0 {
+			sb.WriteString(" EMPTY")
+			return
+		}
+		sb.WriteString(" (")
+		for p := range g.Polygons {
+			if p > 0 {
+				sb.WriteString(", ")
+			}
+			if emptyWKTPolygon(g.Polygons[p]) {
+				sb.WriteString("EMPTY")
+				continue
+			}
+			g.writeWKTPolygon(sb, p)
+		}
+		sb.WriteByte(')')
+	case GeometryCollection:
+		if len(g.Geometries) == 0 {
+			sb.WriteString(" EMPTY")
+			return
+		}
+		sb.WriteString(" (")
+		for i, child := range g.Geometries {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			child.writeWKT(sb)
+		}
+		sb.WriteByte(')')
+	}
+}
+
+// emptyWKTPolygon 判断多边形是否没有非空的环，此时应写为EMPTY而不是"()"
+func emptyWKTPolygon(rings []Contour) bool {
+	for _, ring := range rings {
+		if len(ring) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *Geometry) writeWKTPolygon(sb *strings.Builder, p int) {
+	sb.WriteByte('(')
+	first := true
+	for r, ring := range g.Polygons[p] {
+		if len(ring) == 0 {
+			continue
+		}
+		if !first {
+			sb.WriteString(", ")
+		}
+		first = false
+		sb.WriteByte('(')
+		for i := 0; i <= len(ring); i++ {
+			j := i % len(ring)
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			v := ring[j]
+			sb.WriteString(strconv.FormatFloat(float64(v.X), 'f', -1, 32))
+			sb.WriteByte(' ')
+			sb.WriteString(strconv.FormatFloat(float64(v.Y), 'f', -1, 32))
+			if g.HasZ {
+				sb.WriteByte(' ')
+				sb.WriteString(strconv.FormatFloat(float64(v.Z), 'f', -1, 32))
+			}
+			if g.HasM {
+				sb.WriteByte(' ')
+				sb.WriteString(strconv.FormatFloat(g.mValue(p, r, j), 'f', -1, 64))
+			}
+		}
+		sb.WriteByte(')')
+	}
+	sb.WriteByte(')')
+}
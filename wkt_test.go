@@ -0,0 +1,140 @@
+package tesselator
+
+import (
+	"math"
+	"testing"
+)
+
+// TestParseWKTPolygon 测试多边形、维度和SRID的解析
+func TestParseWKTPolygon(t *testing.T) {
+	g, err := ParseWKT("POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0), (2 2, 2 8, 8 8, 8 2, 2 2))")
+	if err != nil {
+		t.Fatalf("ParseWKT returned an error: %v", err)
+	}
+	if g.Type != GeometryPolygon || g.HasZ || g.HasM || g.SRID != 0 {
+		t.Errorf("unexpected geometry header %+v", g)
+	}
+	contours := g.Contours()
+	if len(contours) != 2 || len(contours[0]) != 4 {
+		t.Fatalf("unexpected contours %v", contours)
+	}
+	indices, vertices, err := g.Tesselate(WindingRuleOdd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a := meshArea(indices, vertices); math.Abs(a-64) > 1e-3 {
+		t.Errorf("expected area 64, got %.3f", a)
+	}
+
+	g, err = ParseWKT("SRID=4326;POLYGON ZM ((0 0 1 5, 1 0 2 6, 0 1 3 7, 0 0 1 5))")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.SRID != 4326 || !g.HasZ || !g.HasM {
+		t.Errorf("unexpected geometry header %+v", g)
+	}
+	if v := g.Polygons[0][0][2]; v.Z != 3 || g.M[0][0][2] != 7 {
+		t.Errorf("unexpected Z/M values: %v, %v", v, g.M)
+	}
+
+	// 没有维度关键字时按坐标个数推断
+	g, _ = ParseWKT("POLYGON((0 0 1, 1 0 1, 0 1 1, 0 0 1))")
+	if !g.HasZ || g.HasM || g.Polygons[0][0][0].Z != 1 {
+		t.Errorf("3 coordinates should be read as XYZ")
+	}
+	g, _ = ParseWKT("POLYGONM((0 0 4, 1 0 4, 0 1 4, 0 0 4))")
+	if g.HasZ || !g.HasM || g.Polygons[0][0][0].Z != 0 || g.M[0][0][0] != 4 {
+		t.Errorf("POLYGONM should be read as XYM")
+	}
+
+	for _, s := range []string{
+		"POLYGON ((0 0, 1 0, 0 1, 0 0)",
+		"POLYGON ((0 0, 1 0 1, 0 1, 0 0))",
+		"POLYGON ((0 0, 1 x, 0 1, 0 0))",
+		"POINT (1 2)",
+		"POLYGON ((0 0, 1 0, 0 1, 0 0)) extra",
+		"SRID=abc;POLYGON EMPTY",
+		"",
+	} {
+		if _, err := ParseWKT(s); err == nil {
+			t.Errorf("%q: expected an error", s)
+		}
+	}
+}
+
+// TestWKTRoundTrip 测试集合类型的编码和解码
+func TestWKTRoundTrip(t *testing.T) {
+	inputs := []string{
+		"POLYGON EMPTY",
+		"POLYGON ((0 0, 1 0, 0 1, 0 0))",
+		"MULTIPOLYGON (((0 0, 4 0, 0 4, 0 0)), EMPTY, ((10 10, 12 10, 10 12, 10 10), (10.5 10.5, 10.5 11, 11 10.5, 10.5 10.5)))",
+		"GEOMETRYCOLLECTION (POLYGON Z ((0 0 1, 1 0 1, 0 1 1, 0 0 1)), MULTIPOLYGON M (((0 0 3, 1 0 3, 0 1 3, 0 0 3))))",
+		"TIN Z (((0 0 0, 1 0 0, 0 1 1, 0 0 0)), ((1 0 0, 1 1 1, 0 1 1, 1 0 0)))",
+		"GEOMETRYCOLLECTION EMPTY",
+	}
+	for _, s := range inputs {
+		g, err := ParseWKT(s)
+		if err != nil {
+			t.Fatalf("%q: %v", s, err)
+		}
+		if out := g.WKT(); out != s {
+			t.Errorf("round trip mismatch:\n got %s\nwant %s", out, s)
+		}
+	}
+
+	g, _ := ParseWKT("SRID=3857;GEOMETRYCOLLECTION (POLYGON ((0 0, 1 0, 0 1, 0 0)))")
+	if g.Geometries[0].SRID != 3857 {
+		t.Error("collection members should inherit the SRID")
+	}
+	if out := g.EWKT(); out != "SRID=3857;GEOMETRYCOLLECTION (POLYGON ((0 0, 1 0, 0 1, 0 0)))" {
+		t.Errorf("unexpected EWKT %s", out)
+	}
+
+	// 只有空环的多边形写为EMPTY
+	g = &Geometry{Type: GeometryPolygon, Polygons: [][]Contour{{nil, {}}}}
+	if out := g.WKT(); out != "POLYGON EMPTY" {
+		t.Errorf("expected POLYGON EMPTY, got %s", out)
+	}
+	g = &Geometry{Type: GeometryMultiPolygon, Polygons: [][]Contour{{{}}, {{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}}}}}
+	if out := g.WKT(); out != "MULTIPOLYGON (EMPTY, ((0 0, 1 0, 0 1, 0 0)))" {
+		t.Errorf("unexpected WKT %s", out)
+	}
+}
+
+// TestTriangleGeometryWKT 测试将三角剖分结果写为MULTIPOLYGON和TIN
+func TestTriangleGeometryWKT(t *testing.T) {
+	g, _ := ParseWKT("POLYGON ((0 0, 10 0, 10 10, 0 10, 0 0), (2 2, 2 8, 8 8, 8 2, 2 2))")
+	indices, vertices, err := g.Tesselate(WindingRuleOdd)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, typ := range []GeometryType{GeometryMultiPolygon, GeometryTIN} {
+		tri, err := TriangleGeometry(indices, vertices, typ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		back, err := ParseWKT(tri.WKT())
+		if err != nil {
+			t.Fatalf("%v: failed to parse written WKT: %v", typ, err)
+		}
+		if back.Type != typ || len(back.Polygons) != len(indices)/3 {
+			t.Fatalf("%v: expected %d triangles, got %d", typ, len(indices)/3, len(back.Polygons))
+		}
+		area := 0.0
+		for _, p := range back.Polygons {
+			a := contourSignedArea(p[0])
+			if a <= 0 {
+				t.Errorf("%v: triangle %v is not counter-clockwise", typ, p[0])
+			}
+			area += a
+		}
+		if math.Abs(area-64) > 1e-3 {
+			t.Errorf("%v: expected area 64, got %.3f", typ, area)
+		}
+	}
+
+	if _, err := TriangleGeometry(indices, vertices, GeometryPolygon); err == nil {
+		t.Error("expected an error for POLYGON output")
+	}
+}